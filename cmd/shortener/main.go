@@ -1,11 +1,20 @@
 // Package main implements a URL shortener server.
-// It initializes configuration, logging and storage (file or database),
+// It initializes configuration, logging and storage (memory, file or database),
 // sets up HTTP routes with middleware, registers pprof handlers for profiling,
 // and starts the HTTP server.
+//
+// The database schema is migrated automatically at startup.
+// It can also be managed manually with the migrate command:
+//
+//	shortener -d <database DSN> migrate up|down|status
+//
+// "up" applies all pending migrations, "down" reverts the latest applied one
+// and "status" lists all migrations with their application time.
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net"
 	"net/http"
@@ -26,15 +35,19 @@ import (
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/gzip"
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/handlers"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
 	"github.com/KirillZiborov/lnkshortener/internal/config"
 	"github.com/KirillZiborov/lnkshortener/internal/database"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
 	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
 )
 
 var (
 	db       *pgxpool.Pool
 	urlStore app.URLStore
+	// changes receives URL changes made by all replicas, it is nil unless the database is used.
+	changes *database.Listener
 
 	// Use go run -ldflags to set up build variables while compiling.
 	buildVersion = "N/A" // Build version
@@ -60,8 +73,61 @@ func main() {
 	// Load the configuration.
 	cfg := config.NewConfig()
 
+	// Run the migrate command instead of the server if requested.
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(cfg, args[1:]); err != nil {
+			logging.Sugar.Errorw("Migration failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Check the deduplication policy before any storage is initialized.
+	switch cfg.DedupPolicy {
+	case config.DedupGlobal, config.DedupPerUser, config.DedupNone:
+	default:
+		logging.Sugar.Errorw("Unknown deduplication policy", "policy", cfg.DedupPolicy)
+		return
+	}
+
+	// Check the expired URLs policy as well.
+	switch cfg.ExpiredPolicy {
+	case config.ExpiredPurge, config.ExpiredArchive:
+	default:
+		logging.Sugar.Errorw("Unknown expired URLs policy", "policy", cfg.ExpiredPolicy)
+		return
+	}
+
+	// Check the default redirect status code.
+	if err := app.ValidateRedirectCode(cfg.RedirectCode); err != nil {
+		logging.Sugar.Errorw("Unsupported redirect status code", "code", cfg.RedirectCode)
+		return
+	}
+
+	// Check the response to short URLs which are not active yet and load its page.
+	var inactivePage []byte
+	switch cfg.InactiveResponse {
+	case config.InactiveNotFound:
+	case config.InactiveShowPage:
+		if cfg.InactivePage != "" {
+			if inactivePage, err = os.ReadFile(cfg.InactivePage); err != nil {
+				logging.Sugar.Errorw("Failed to read inactive page", "error", err, "path", cfg.InactivePage)
+				return
+			}
+		}
+	case config.InactiveRedirect:
+		if cfg.InactiveFallbackURL == "" {
+			logging.Sugar.Errorw("Inactive redirect response requires a fallback URL")
+			return
+		}
+	default:
+		logging.Sugar.Errorw("Unknown inactive response", "response", cfg.InactiveResponse)
+		return
+	}
+
 	// Initialize storage based on the configuration.
-	if cfg.DBPath != "" {
+	switch cfg.StorageType {
+	case config.StorageDatabase:
 		// Establish a connection to the PostgreSQL database with a timeout.
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -72,28 +138,102 @@ func main() {
 			return
 		}
 
-		// Create the URL table in the database if it doesn't exist.
-		err = database.CreateURLTable(ctx, db)
+		defer db.Close()
+
+		// Bring the database schema up to date.
+		migrations, err := database.MigrateUp(ctx, db)
 		if err != nil {
-			logging.Sugar.Errorw("Failed to create table", "error", err)
+			logging.Sugar.Errorw("Failed to migrate database", "error", err)
 			return
 		}
-		defer db.Close()
+		for _, m := range migrations {
+			logging.Sugar.Infow("Applied migration", "version", m.Version, "name", m.Name)
+		}
 
 		// Use the database store for URL storage.
-		urlStore = database.NewDBStore(db)
-	} else {
+		urlStore = database.NewDBStore(db, cfg.DedupPolicy)
+		changes = database.NewListener(cfg.DBPath)
+	case config.StorageMemory:
+		logging.Sugar.Infow("Running with in-memory storage")
+		// Keep URLs in memory only, they are lost on restart.
+		urlStore = memory.NewMemoryStore(cfg.DedupPolicy)
+	case config.StorageFile:
 		// If no database is configured, use a file-based store.
 		logging.Sugar.Infow("Running without database")
-		// Use the file for URL storage.
-		urlStore = file.NewFileStore(cfg.FilePath)
+		// Use the file for URL storage with the in-memory index in front of it.
+		fileStore, err := file.NewFileStore(cfg.FilePath, memory.NewMemoryStore(cfg.DedupPolicy), time.Duration(cfg.FileCompactInterval))
+		if err != nil {
+			logging.Sugar.Errorw("Failed to open file storage", "error", err)
+			return
+		}
+		defer fileStore.Close()
+
+		urlStore = fileStore
+	default:
+		logging.Sugar.Errorw("Unknown storage type", "storage", cfg.StorageType)
+		return
+	}
+
+	// Generate short URL IDs with the configured generator.
+	// IDs are checked against the storage itself, so the probes of unused IDs do not fill the cache.
+	ids, err := app.NewIDGenerator(cfg, urlStore)
+	if err != nil {
+		logging.Sugar.Errorw("Failed to create ID generator", "error", err)
+		return
+	}
+
+	// Serve redirects from the cache, writes through it invalidate the cached short URLs.
+	urlStore = app.NewRedirectCache(cfg, urlStore)
+
+	// Keep the cache consistent with the changes made by other replicas sharing the database.
+	if cached, ok := urlStore.(*app.CachedStore); ok && changes != nil {
+		changes.Subscribe(cached.HandleChange)
 	}
+	if changes != nil {
+		listenCtx, stopListening := context.WithCancel(context.Background())
+		defer stopListening()
+		go changes.Run(listenCtx)
+	}
+
+	// Record clicks in the background, buffered clicks are saved on shutdown.
+	clickRecorder := clicks.NewRecorder(urlStore, cfg.ClickBufferSize, time.Duration(cfg.ClickFlushInterval))
+	defer clickRecorder.Close()
+
+	// Process deletion jobs in the background, resuming the ones left pending by the previous run.
+	loadCtx, cancelLoad := context.WithTimeout(context.Background(), time.Duration(cfg.StorageReadTimeout))
+	deleteQueue, err := app.NewDeleteQueue(loadCtx, cfg, urlStore)
+	cancelLoad()
+	if err != nil {
+		logging.Sugar.Errorw("Failed to start deletion queue", "error", err)
+		return
+	}
+	// Let the queued deletions finish before the storage is closed, unfinished ones are resumed on restart.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.DeleteDrainTimeout))
+		defer cancel()
+		if err := deleteQueue.Close(ctx); err != nil {
+			logging.Sugar.Warnw("Deletion jobs left pending until restart", "error", err)
+		}
+	}()
 
 	service := app.ShortenerService{
-		Store: urlStore,
-		Cfg:   cfg,
+		Store:   urlStore,
+		Cfg:     cfg,
+		Clicks:  clickRecorder,
+		IDs:     ids,
+		Deletes: deleteQueue,
+
+		Attempts:     app.NewAttemptLimiter(cfg.PasswordMaxAttempts, time.Duration(cfg.PasswordAttemptWindow)),
+		InactivePage: inactivePage,
 	}
 
+	// Sweep expired URLs in the background until the server stops.
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go service.RunExpiredSweeper(sweeperCtx)
+	// Purge deleted URLs past the retention in the background as well.
+	go service.RunDeletedPurger(sweeperCtx)
+
 	// Setup the router with all routes and middleware.
 	router := SetupRouter(service, db)
 
@@ -188,8 +328,20 @@ func main() {
 // - POST "/api/shorten" : Creates a new shortened URL for JSON requests.
 // - POST "/api/shorten/batch" : Creates multiple shortened URLs in batch.
 // - GET "/{id}" : Redirects to the original URL based on the shortened ID.
+// - HEAD "/{id}" : Answers with the same redirect as GET without counting a click.
+// - POST "/{id}/unlock" : Redirects to the original URL of a password-protected URL with a correct password.
 // - GET "/api/user/urls" : Retrieves all URLs created by the user.
+// - GET "/api/user/urls/{id}/stats" : Retrieves click statistics of a URL created by the user.
+// - PATCH "/api/user/urls/{id}" : Changes the original URL of a URL created by the user.
+// - GET "/api/user/urls/{id}/history" : Retrieves the changes of the original URL of a URL created by the user.
+// - PUT "/api/user/urls/{id}/tags" : Replaces the tags of a URL created by the user.
+// - PUT "/api/user/urls/{id}/window" : Replaces the activation window of a URL created by the user.
+// - GET "/api/user/urls/{id}/rules" : Retrieves the redirect rules of a URL created by the user.
+// - PUT "/api/user/urls/{id}/rules" : Replaces the redirect rules of a URL created by the user.
+// - GET "/api/user/tags" : Retrieves the number of URLs created by the user and their clicks by tags.
 // - DELETE "/api/user/urls" : Deletes multiple URLs in batch.
+// - GET "/api/user/urls/delete-jobs/{id}" : Retrieves the progress of a batch deletion.
+// - POST "/api/user/urls/restore" : Restores multiple deleted URLs within the retention.
 // - GET "/ping" : Health check endpoint to verify database connection.
 // - GET "/api/internal/stats" : Stats (number of URLs and unique users) check endpoint.
 //
@@ -212,9 +364,21 @@ func SetupRouter(service app.ShortenerService, db *pgxpool.Pool) *chi.Mux {
 	r.Post("/api/shorten", gzip.Middleware(handlers.APIShortenHandler(&service)))
 	r.Post("/api/shorten/batch", gzip.Middleware(handlers.BatchShortenHandler(&service)))
 	r.Get("/{id}", gzip.Middleware(handlers.GetHandler(&service)))
+	r.Head("/{id}", gzip.Middleware(handlers.GetHandler(&service)))
+	r.Post("/{id}/unlock", gzip.Middleware(handlers.UnlockHandler(&service)))
 	r.Get("/api/user/urls", gzip.Middleware(handlers.GetUserURLsHandler(&service)))
+	r.Get("/api/user/urls/{id}/stats", gzip.Middleware(handlers.GetLinkStatsHandler(&service)))
+	r.Patch("/api/user/urls/{id}", gzip.Middleware(handlers.UpdateURLHandler(&service)))
+	r.Get("/api/user/urls/{id}/history", gzip.Middleware(handlers.GetURLHistoryHandler(&service)))
+	r.Put("/api/user/urls/{id}/tags", gzip.Middleware(handlers.SetURLTagsHandler(&service)))
+	r.Put("/api/user/urls/{id}/window", gzip.Middleware(handlers.SetURLActiveWindowHandler(&service)))
+	r.Get("/api/user/urls/{id}/rules", gzip.Middleware(handlers.GetURLRulesHandler(&service)))
+	r.Put("/api/user/urls/{id}/rules", gzip.Middleware(handlers.SetURLRulesHandler(&service)))
+	r.Get("/api/user/tags", gzip.Middleware(handlers.GetTagStatsHandler(&service)))
 	r.Get("/api/internal/stats", gzip.Middleware(handlers.GetStatsHandler(&service)))
 	r.Delete("/api/user/urls", gzip.Middleware(handlers.BatchDeleteHandler(&service)))
+	r.Get("/api/user/urls/delete-jobs/{id}", gzip.Middleware(handlers.GetDeleteJobHandler(&service)))
+	r.Post("/api/user/urls/restore", gzip.Middleware(handlers.RestoreURLsHandler(&service)))
 
 	// Conditional route for database health check.
 	if db != nil {
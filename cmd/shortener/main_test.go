@@ -1,22 +1,27 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/handlers"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
 	"github.com/KirillZiborov/lnkshortener/internal/config"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
 )
 
 func createTestFile(t *testing.T, fileName string) {
@@ -34,22 +39,35 @@ func TestServer(t *testing.T) {
 	cfg := &config.Config{
 		Address:  "localhost:8080",
 		BaseURL:  "http://localhost:8080",
-		FilePath: "test_file.json",
-	}
-
-	urlStore = file.NewFileStore(cfg.FilePath)
+		FilePath: filepath.Join(t.TempDir(), "test_file.json"),
 
-	createTestFile(t, cfg.FilePath)
+		PermanentRedirectMaxAge: config.Duration(24 * time.Hour),
+	}
 
 	service := app.ShortenerService{
-		Store: urlStore,
-		Cfg:   cfg,
+		Cfg: cfg,
 	}
 
 	r := chi.NewRouter()
 
 	r.Post("/", handlers.PostHandler(&service))
 	r.Get("/{id}", handlers.GetHandler(&service))
+	r.Head("/{id}", handlers.GetHandler(&service))
+	r.Post("/{id}/unlock", handlers.UnlockHandler(&service))
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	saveProtected := func() {
+		urlRecord := &file.URLRecord{
+			UUID:         "protected",
+			ShortURL:     cfg.BaseURL + "/protected",
+			OriginalURL:  "https://ya.ru",
+			PasswordHash: string(passwordHash),
+		}
+
+		_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+		require.NoError(t, err)
+	}
 	r.Post("/api/shorten", handlers.APIShortenHandler(&service))
 
 	type want struct {
@@ -59,12 +77,13 @@ func TestServer(t *testing.T) {
 	}
 
 	tests := []struct {
-		name       string
-		method     string
-		url        string
-		body       string
-		setupStore func()
-		want       want
+		name        string
+		method      string
+		url         string
+		body        string
+		contentType string
+		setupStore  func()
+		want        want
 	}{
 		{
 			name:   "POST 201",
@@ -91,7 +110,8 @@ func TestServer(t *testing.T) {
 			want: want{
 				code: http.StatusTemporaryRedirect,
 				headerMatches: map[string]string{
-					"Location": "https://ya.ru",
+					"Location":      "https://ya.ru",
+					"Cache-Control": "no-store",
 				},
 			},
 			setupStore: func() {
@@ -101,7 +121,157 @@ func TestServer(t *testing.T) {
 					OriginalURL: "https://ya.ru",
 				}
 
-				err := file.SaveURLRecord(urlRecord, cfg.FilePath)
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "GET 307 redirect rule",
+			method: http.MethodGet,
+			url:    "/ruled?utm_source=mail",
+			want: want{
+				code: http.StatusTemporaryRedirect,
+				headerMatches: map[string]string{
+					"Location":      "https://ya.ru/mail",
+					"Cache-Control": "no-store",
+				},
+			},
+			setupStore: func() {
+				urlRecord := &file.URLRecord{
+					UUID:        "ruled",
+					ShortURL:    cfg.BaseURL + "/ruled",
+					OriginalURL: "https://ya.ru",
+					Rules: []file.RedirectRule{
+						{Device: "android", Target: "https://ya.ru/android"},
+						{Query: map[string]string{"utm_source": "mail"}, Target: "https://ya.ru/mail"},
+					},
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "HEAD 308",
+			method: http.MethodHead,
+			url:    "/permanent",
+			want: want{
+				code: http.StatusPermanentRedirect,
+				headerMatches: map[string]string{
+					"Location":      "https://ya.ru",
+					"Cache-Control": "public, max-age=86400",
+				},
+			},
+			setupStore: func() {
+				urlRecord := &file.URLRecord{
+					UUID:         "permanent",
+					ShortURL:     cfg.BaseURL + "/permanent",
+					OriginalURL:  "https://ya.ru",
+					RedirectCode: http.StatusPermanentRedirect,
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "GET 401 protected",
+			method: http.MethodGet,
+			url:    "/protected",
+			want: want{
+				code: http.StatusUnauthorized,
+				body: `action="/protected/unlock"`,
+			},
+			setupStore: saveProtected,
+		},
+		{
+			name:        "POST unlock 401",
+			method:      http.MethodPost,
+			url:         "/protected/unlock",
+			body:        "password=wrong",
+			contentType: "application/x-www-form-urlencoded",
+			want: want{
+				code: http.StatusUnauthorized,
+				body: "Wrong password",
+			},
+			setupStore: saveProtected,
+		},
+		{
+			name:        "POST unlock 303",
+			method:      http.MethodPost,
+			url:         "/protected/unlock",
+			body:        "password=secret",
+			contentType: "application/x-www-form-urlencoded",
+			want: want{
+				code: http.StatusSeeOther,
+				headerMatches: map[string]string{
+					"Location": "https://ya.ru",
+				},
+			},
+			setupStore: saveProtected,
+		},
+		{
+			name:   "GET 410 expired",
+			method: http.MethodGet,
+			url:    "/expired",
+			want: want{
+				code: http.StatusGone,
+				body: "URL has expired",
+			},
+			setupStore: func() {
+				expiresAt := time.Now().Add(-time.Minute)
+				urlRecord := &file.URLRecord{
+					UUID:        "expired",
+					ShortURL:    cfg.BaseURL + "/expired",
+					OriginalURL: "https://ya.ru",
+					ExpiresAt:   &expiresAt,
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "GET 410 exhausted",
+			method: http.MethodGet,
+			url:    "/exhausted",
+			want: want{
+				code: http.StatusGone,
+				body: "URL has reached its click limit",
+			},
+			setupStore: func() {
+				urlRecord := &file.URLRecord{
+					UUID:        "exhausted",
+					ShortURL:    cfg.BaseURL + "/exhausted",
+					OriginalURL: "https://ya.ru",
+					MaxClicks:   1,
+					UsedClicks:  1,
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "GET 404 not yet active",
+			method: http.MethodGet,
+			url:    "/launch",
+			want: want{
+				code: http.StatusNotFound,
+				headerMatches: map[string]string{
+					"Cache-Control": "no-store",
+				},
+			},
+			setupStore: func() {
+				notBefore := time.Now().Add(time.Hour)
+				urlRecord := &file.URLRecord{
+					UUID:        "launch",
+					ShortURL:    cfg.BaseURL + "/launch",
+					OriginalURL: "https://ya.ru",
+					NotBefore:   &notBefore,
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
 				require.NoError(t, err)
 			},
 		},
@@ -127,7 +297,7 @@ func TestServer(t *testing.T) {
 					OriginalURL: "https://ya.ru",
 				}
 
-				err := file.SaveURLRecord(urlRecord, cfg.FilePath)
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
 				require.NoError(t, err)
 			},
 		},
@@ -158,17 +328,97 @@ func TestServer(t *testing.T) {
 				code: http.StatusBadRequest,
 			},
 		},
+		{
+			name:   "POST json ttl 201",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","ttl":"24h"}`,
+			want: want{
+				code: http.StatusCreated,
+			},
+		},
+		{
+			name:   "POST json ttl 400",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","ttl":"24h","expires_at":"2030-01-01T00:00:00Z"}`,
+			want: want{
+				code: http.StatusBadRequest,
+				body: "mutually exclusive",
+			},
+		},
+		{
+			name:   "POST json redirect code 400",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","redirect_code":303}`,
+			want: want{
+				code: http.StatusBadRequest,
+				body: "invalid redirect code",
+			},
+		},
+		{
+			name:   "POST json alias 201",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","alias":"spring-sale"}`,
+			want: want{
+				code: http.StatusCreated,
+				body: `{"result":"http://localhost:8080/spring-sale"}`,
+			},
+		},
+		{
+			name:   "POST json alias 400",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","alias":"api"}`,
+			want: want{
+				code: http.StatusBadRequest,
+				body: "reserved",
+			},
+		},
+		{
+			name:   "POST json alias 409",
+			method: http.MethodPost,
+			url:    "/api/shorten",
+			body:   `{"url":"https://practicum.yandex.ru","alias":"spring-sale"}`,
+			want: want{
+				code: http.StatusConflict,
+				body: "Alias is already taken",
+			},
+			setupStore: func() {
+				urlRecord := &file.URLRecord{
+					UUID:        "spring-sale",
+					ShortURL:    cfg.BaseURL + "/spring-sale",
+					OriginalURL: "https://ya.ru",
+					Alias:       true,
+				}
+
+				_, err := urlStore.SaveURLRecord(context.Background(), urlRecord)
+				require.NoError(t, err)
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			createTestFile(t, cfg.FilePath)
 
+			// Open a fresh store over the empty file for every test case.
+			fileStore, err := file.NewFileStore(cfg.FilePath, memory.NewMemoryStore(config.DedupGlobal), 0)
+			require.NoError(t, err)
+			defer fileStore.Close()
+			urlStore = fileStore
+			service.Store = urlStore
+
 			if tc.setupStore != nil {
 				tc.setupStore()
 			}
 
 			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
 			rw := httptest.NewRecorder()
 
 			r.ServeHTTP(rw, req)
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+)
+
+// migrateTimeout limits the whole migrate command.
+const migrateTimeout = 5 * time.Minute
+
+// runMigrate executes the migrate command with the given arguments: up, down or status.
+// It connects to the database from the configuration and prints the result to stdout.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: shortener migrate up|down|status")
+	}
+	if cfg.DBPath == "" {
+		return errors.New("database DSN is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrateTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.DBPath)
+	if err != nil {
+		return fmt.Errorf("unable to connect to database: %w", err)
+	}
+	defer pool.Close()
+
+	switch args[0] {
+	case "up":
+		migrations, err := database.MigrateUp(ctx, pool)
+		for _, m := range migrations {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(migrations) == 0 {
+			fmt.Println("Database is up to date")
+		}
+	case "down":
+		m, err := database.MigrateDown(ctx, pool)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %04d_%s\n", m.Version, m.Name)
+	case "status":
+		statuses, err := database.GetMigrationStatus(ctx, pool)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied at " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package grpcapi
 
 import (
 	"context"
+	"errors"
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/interceptors"
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/proto"
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -23,7 +26,62 @@ func (s *GRPCShortenerServer) BatchDelete(ctx context.Context, req *proto.BatchD
 	}
 
 	// Call to BatchDeleteAsync from app.
-	s.svc.BatchDeleteAsync(userID, shortIDs)
+	job, err := s.svc.BatchDeleteAsync(ctx, userID, shortIDs)
+	if errors.Is(err, deletion.ErrQueueClosed) {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete URLs: %v", err)
+	}
+
+	return &proto.BatchDeleteResponse{JobId: job.ID}, nil
+}
+
+// GetDeleteJob is the gRPC equivalent of the HTTP GetDeleteJobHandler from package handlers.
+func (s *GRPCShortenerServer) GetDeleteJob(ctx context.Context, req *proto.GetDeleteJobRequest) (*proto.GetDeleteJobResponse, error) {
+	if req.GetJobId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no job_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to GetDeleteJob from app.
+	job, err := s.svc.GetDeleteJob(ctx, userID, req.GetJobId())
+	if errors.Is(err, app.ErrJobNotFound) {
+		return nil, status.Error(codes.NotFound, "deletion job not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get deletion job: %v", err)
+	}
+
+	counts := job.Counts()
+	return &proto.GetDeleteJobResponse{
+		Pending: int64(counts.Pending),
+		Done:    int64(counts.Done),
+		Failed:  int64(counts.Failed),
+	}, nil
+}
+
+// RestoreURLs is the gRPC equivalent of the HTTP RestoreURLsHandler from package handlers.
+func (s *GRPCShortenerServer) RestoreURLs(ctx context.Context, req *proto.RestoreURLsRequest) (*proto.RestoreURLsResponse, error) {
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	shortIDs := req.GetShortIds()
+	if len(shortIDs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Empty short_ids array")
+	}
+
+	// Call to RestoreURLs from app.
+	restored, err := s.svc.RestoreURLs(ctx, userID, shortIDs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to restore URLs: %v", err)
+	}
 
-	return &proto.BatchDeleteResponse{}, nil
+	return &proto.RestoreURLsResponse{RestoredIds: restored}, nil
 }
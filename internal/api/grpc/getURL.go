@@ -3,33 +3,78 @@ package grpcapi
 import (
 	"context"
 	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/interceptors"
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/proto"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // GetOriginalURL is the gRPC equivalent of the HTTP GetHandler from package handlers.
+// Deleted, expired, inactive and exhausted URLs are reported with codes.FailedPrecondition.
+// Not yet active URLs are reported with codes.NotFound in the "not-found" inactive response mode.
+// Every call counts against the click limit of the URL and is recorded as a click.
+// The response has the status code the HTTP redirect is made with and the target of the first
+// redirect rule matching the visitor described in the request, or the original URL if none matches.
+// A password-protected URL requires the password in the request, codes.PermissionDenied is returned
+// if it is missing or wrong and codes.ResourceExhausted if the URL is locked after too many wrong passwords.
 func (s *GRPCShortenerServer) GetOriginalURL(ctx context.Context, req *proto.GetOriginalURLRequest) (*proto.GetOriginalURLResponse, error) {
 	shortID := req.GetShortId()
 	if shortID == "" {
 		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
 	}
 
-	// Call to GetShortURL from app.
-	originalURL, err := s.svc.GetShortURL(ctx, shortID)
-	if errors.Is(err, app.ErrURLNotFound) {
+	// Malformed pairs are skipped the same way the HTTP server parses the query of a request.
+	query, _ := url.ParseQuery(req.GetQuery())
+
+	// Call to GetRedirect from app.
+	redirect, err := s.svc.GetRedirect(ctx, shortID, app.RedirectRequest{
+		Password:       req.GetPassword(),
+		UserAgent:      req.GetUserAgent(),
+		AcceptLanguage: req.GetAcceptLanguage(),
+		Query:          query,
+	})
+	if errors.Is(err, app.ErrPasswordRequired) {
+		return nil, status.Error(codes.PermissionDenied, "password required")
+	} else if errors.Is(err, app.ErrWrongPassword) {
+		return nil, status.Error(codes.PermissionDenied, "wrong password")
+	} else if errors.Is(err, app.ErrTooManyAttempts) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	} else if errors.Is(err, app.ErrURLNotFound) {
 		return nil, status.Error(codes.NotFound, "URL not found")
 	} else if errors.Is(err, app.ErrURLDeleted) {
 		return nil, status.Error(codes.FailedPrecondition, "URL is deleted")
+	} else if errors.Is(err, app.ErrURLExpired) {
+		return nil, status.Error(codes.FailedPrecondition, "URL has expired")
+	} else if errors.Is(err, app.ErrURLNotYetActive) && s.svc.Cfg.InactiveResponse == config.InactiveNotFound {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if errors.Is(err, app.ErrURLNotYetActive) {
+		return nil, status.Error(codes.FailedPrecondition, "URL is not active yet")
+	} else if errors.Is(err, app.ErrURLNoLongerActive) {
+		return nil, status.Error(codes.FailedPrecondition, "URL is no longer active")
+	} else if errors.Is(err, app.ErrURLExhausted) {
+		return nil, status.Error(codes.FailedPrecondition, "URL has reached its click limit")
 	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, "internal server error: %v", err)
 	}
 
+	// Record the click without delaying the response.
+	referrer, userAgent, clientIP := clickSource(ctx, req)
+	s.svc.RecordClick(shortID, referrer, userAgent, clientIP)
+
 	return &proto.GetOriginalURLResponse{
-		OriginalUrl: originalURL,
+		OriginalUrl:  redirect.URL,
+		RedirectCode: int32(redirect.Code),
 	}, nil
 }
 
@@ -41,22 +86,62 @@ func (s *GRPCShortenerServer) GetUserURLs(ctx context.Context, req *proto.GetUse
 		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
 	}
 
+	q := app.URLsQuery{
+		Limit:    int(req.GetLimit()),
+		Cursor:   req.GetCursor(),
+		Sort:     req.GetSort(),
+		Status:   file.URLStatus(req.GetStatus()),
+		Contains: req.GetContains(),
+		Tag:      req.GetTag(),
+	}
+	if req.GetCreatedFrom() != nil {
+		q.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		q.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+
 	// Call to GetUserURLs from app.
-	records, err := s.svc.GetUserURLs(ctx, userID)
-	if err != nil {
+	page, err := s.svc.GetUserURLs(ctx, userID, q)
+	if errors.Is(err, app.ErrInvalidURLsQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
 		return nil, status.Error(codes.Internal, "failed to get a list of user's URLs")
 	}
 
 	// Prepare response.
 	var respRecords []*proto.URLRecord
-	for _, r := range records {
-		respRecords = append(respRecords, &proto.URLRecord{
-			ShortUrl:    r.ShortURL,
-			OriginalUrl: r.OriginalURL,
-		})
+	for _, r := range page.Records {
+		rec := &proto.URLRecord{
+			ShortUrl:       r.ShortURL,
+			OriginalUrl:    r.OriginalURL,
+			Deleted:        r.DeletedFlag,
+			UpdatedAt:      optionalTimestamp(r.UpdatedAt),
+			DeletedAt:      optionalTimestamp(r.DeletedAt),
+			LastAccessedAt: optionalTimestamp(r.LastAccessedAt),
+			Title:          r.Title,
+			Notes:          r.Notes,
+			Tags:           r.Tags,
+			RedirectCode:   int32(r.RedirectCode),
+			Protected:      r.PasswordHash != "",
+			MaxClicks:      int32(r.MaxClicks),
+			UsedClicks:     int32(r.UsedClicks),
+			NotBefore:      optionalTimestamp(r.NotBefore),
+			NotAfter:       optionalTimestamp(r.NotAfter),
+			Rules:          protoRules(r.Rules),
+		}
+		// Records saved before the creation time was kept have none.
+		if !r.CreatedAt.IsZero() {
+			rec.CreatedAt = timestamppb.New(r.CreatedAt)
+		}
+		respRecords = append(respRecords, rec)
 	}
 
-	return &proto.GetUserURLsResponse{Records: respRecords}, nil
+	return &proto.GetUserURLsResponse{
+		Records:    respRecords,
+		Total:      int64(page.Total),
+		NextCursor: page.NextCursor,
+	}, nil
 }
 
 // GetStats is the gRPC equivalent of the HTTP GetStatsHandler from package handlers.
@@ -80,7 +165,7 @@ func (s *GRPCShortenerServer) GetStats(ctx context.Context, req *proto.GetStatsR
 	}
 
 	// Call to GetStats from app.
-	urls, users, err := s.svc.GetStats()
+	urls, users, err := s.svc.GetStats(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to get stats: %v", err)
 	}
@@ -90,3 +175,111 @@ func (s *GRPCShortenerServer) GetStats(ctx context.Context, req *proto.GetStatsR
 		Users: int64(users),
 	}, nil
 }
+
+// GetLinkStats is the gRPC equivalent of the HTTP GetLinkStatsHandler from package handlers.
+func (s *GRPCShortenerServer) GetLinkStats(ctx context.Context, req *proto.GetLinkStatsRequest) (*proto.GetLinkStatsResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	q := app.StatsQuery{Bucket: req.GetBucket()}
+	if req.GetFrom() != nil {
+		q.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		q.To = req.GetTo().AsTime()
+	}
+
+	// Call to GetLinkStats from app.
+	stats, err := s.svc.GetLinkStats(ctx, userID, req.GetShortId(), q)
+	if errors.Is(err, app.ErrInvalidStatsQuery) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if errors.Is(err, app.ErrURLNotFound) {
+		return nil, status.Error(codes.NotFound, "URL not found")
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get link stats: %v", err)
+	}
+
+	// Prepare response.
+	buckets := make([]*proto.GetLinkStatsResponse_Bucket, 0, len(stats.Buckets))
+	for _, b := range stats.Buckets {
+		buckets = append(buckets, &proto.GetLinkStatsResponse_Bucket{
+			Start:  timestamppb.New(b.Start),
+			Clicks: int64(b.Clicks),
+		})
+	}
+
+	return &proto.GetLinkStatsResponse{
+		Total:   int64(stats.Total),
+		Buckets: buckets,
+	}, nil
+}
+
+// GetTagStats is the gRPC equivalent of the HTTP GetTagStatsHandler from package handlers.
+func (s *GRPCShortenerServer) GetTagStats(ctx context.Context, req *proto.GetTagStatsRequest) (*proto.GetTagStatsResponse, error) {
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to GetTagStats from app.
+	stats, err := s.svc.GetTagStats(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get tag stats: %v", err)
+	}
+
+	// Prepare response.
+	tags := make([]*proto.GetTagStatsResponse_Tag, 0, len(stats))
+	for _, t := range stats {
+		tags = append(tags, &proto.GetTagStatsResponse_Tag{
+			Tag:    t.Tag,
+			Urls:   int64(t.URLs),
+			Clicks: int64(t.Clicks),
+		})
+	}
+	return &proto.GetTagStatsResponse{Tags: tags}, nil
+}
+
+// clickSource returns the referrer, user agent and client IP address of a GetOriginalURL call.
+// They are taken from the "referer", "user-agent" and "x-real-ip" metadata the same way
+// the HTTP server takes them from the request headers. The user agent in the request takes
+// precedence over the metadata and the peer address is used if there is no "x-real-ip".
+func clickSource(ctx context.Context, req *proto.GetOriginalURLRequest) (referrer, userAgent, clientIP string) {
+	userAgent = req.GetUserAgent()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("referer"); len(v) > 0 {
+			referrer = v[0]
+		}
+		if v := md.Get("user-agent"); len(v) > 0 && userAgent == "" {
+			userAgent = v[0]
+		}
+		if v := md.Get("x-real-ip"); len(v) > 0 {
+			clientIP = strings.TrimSpace(v[0])
+		}
+	}
+
+	if clientIP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			clientIP = p.Addr.String()
+			if host, _, err := net.SplitHostPort(clientIP); err == nil {
+				clientIP = host
+			}
+		}
+	}
+	return referrer, userAgent, clientIP
+}
+
+// optionalTimestamp converts an optional time to a timestamp, nil if the time is not set.
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
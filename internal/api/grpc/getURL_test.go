@@ -0,0 +1,80 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/proto"
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestGetOriginalURLRecordsClick(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+
+	store := memory.NewMemoryStore(config.DedupGlobal)
+	svc := &app.ShortenerService{
+		Store:  store,
+		Cfg:    &config.Config{BaseURL: "http://localhost:8080"},
+		Clicks: clicks.NewRecorder(store, 10, time.Hour),
+	}
+	_, err := svc.CreateShortURL(context.Background(), "https://a.ru", "user1", app.CreateOptions{Alias: "abc"})
+	require.NoError(t, err)
+	s := NewGRPCShortenerServer(svc)
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		req  *proto.GetOriginalURLRequest
+		want clicks.Click
+	}{
+		{
+			name: "metadata",
+			md:   metadata.Pairs("referer", "https://ref.ru", "user-agent", "grpc-go/1.0", "x-real-ip", "10.1.2.3"),
+			req:  &proto.GetOriginalURLRequest{ShortId: "abc"},
+			want: clicks.Click{Referrer: "https://ref.ru", UserAgent: "grpc-go/1.0", IP: "10.1.2.0"},
+		},
+		{
+			name: "peer address and request user agent",
+			md:   metadata.Pairs("user-agent", "grpc-go/1.0"),
+			req:  &proto.GetOriginalURLRequest{ShortId: "abc", UserAgent: "Mozilla/5.0"},
+			want: clicks.Click{UserAgent: "Mozilla/5.0", IP: "192.168.0.0"},
+		},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("192.168.0.7"), Port: 51234},
+	})
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := s.GetOriginalURL(metadata.NewIncomingContext(ctx, tc.md), tc.req)
+			require.NoError(t, err)
+			assert.Equal(t, "https://a.ru", resp.GetOriginalUrl())
+		})
+	}
+
+	// Failed calls are not recorded.
+	_, err = s.GetOriginalURL(ctx, &proto.GetOriginalURLRequest{ShortId: "missing"})
+	require.Error(t, err)
+
+	// The buffered clicks are saved on close.
+	svc.Clicks.Close()
+	saved := store.Clicks()
+	require.Len(t, saved, len(tests))
+	for i, tc := range tests {
+		assert.Equal(t, "http://localhost:8080/abc", saved[i].ShortURL)
+		assert.Equal(t, tc.want.Referrer, saved[i].Referrer)
+		assert.Equal(t, tc.want.UserAgent, saved[i].UserAgent)
+		assert.Equal(t, tc.want.IP, saved[i].IP)
+	}
+}
@@ -21,12 +21,21 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ShortenerService_CreateURL_FullMethodName      = "/shortener.ShortenerService/CreateURL"
-	ShortenerService_BatchShorten_FullMethodName   = "/shortener.ShortenerService/BatchShorten"
-	ShortenerService_GetOriginalURL_FullMethodName = "/shortener.ShortenerService/GetOriginalURL"
-	ShortenerService_GetUserURLs_FullMethodName    = "/shortener.ShortenerService/GetUserURLs"
-	ShortenerService_GetStats_FullMethodName       = "/shortener.ShortenerService/GetStats"
-	ShortenerService_BatchDelete_FullMethodName    = "/shortener.ShortenerService/BatchDelete"
+	ShortenerService_CreateURL_FullMethodName          = "/shortener.ShortenerService/CreateURL"
+	ShortenerService_BatchShorten_FullMethodName       = "/shortener.ShortenerService/BatchShorten"
+	ShortenerService_GetOriginalURL_FullMethodName     = "/shortener.ShortenerService/GetOriginalURL"
+	ShortenerService_GetUserURLs_FullMethodName        = "/shortener.ShortenerService/GetUserURLs"
+	ShortenerService_GetStats_FullMethodName           = "/shortener.ShortenerService/GetStats"
+	ShortenerService_BatchDelete_FullMethodName        = "/shortener.ShortenerService/BatchDelete"
+	ShortenerService_GetLinkStats_FullMethodName       = "/shortener.ShortenerService/GetLinkStats"
+	ShortenerService_GetDeleteJob_FullMethodName       = "/shortener.ShortenerService/GetDeleteJob"
+	ShortenerService_UpdateURL_FullMethodName          = "/shortener.ShortenerService/UpdateURL"
+	ShortenerService_RestoreURLs_FullMethodName        = "/shortener.ShortenerService/RestoreURLs"
+	ShortenerService_SetURLTags_FullMethodName         = "/shortener.ShortenerService/SetURLTags"
+	ShortenerService_GetTagStats_FullMethodName        = "/shortener.ShortenerService/GetTagStats"
+	ShortenerService_SetURLActiveWindow_FullMethodName = "/shortener.ShortenerService/SetURLActiveWindow"
+	ShortenerService_SetURLRules_FullMethodName        = "/shortener.ShortenerService/SetURLRules"
+	ShortenerService_GetURLRules_FullMethodName        = "/shortener.ShortenerService/GetURLRules"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -39,6 +48,15 @@ type ShortenerServiceClient interface {
 	GetUserURLs(ctx context.Context, in *GetUserURLsRequest, opts ...grpc.CallOption) (*GetUserURLsResponse, error)
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error)
+	GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error)
+	UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*UpdateURLResponse, error)
+	RestoreURLs(ctx context.Context, in *RestoreURLsRequest, opts ...grpc.CallOption) (*RestoreURLsResponse, error)
+	SetURLTags(ctx context.Context, in *SetURLTagsRequest, opts ...grpc.CallOption) (*SetURLTagsResponse, error)
+	GetTagStats(ctx context.Context, in *GetTagStatsRequest, opts ...grpc.CallOption) (*GetTagStatsResponse, error)
+	SetURLActiveWindow(ctx context.Context, in *SetURLActiveWindowRequest, opts ...grpc.CallOption) (*SetURLActiveWindowResponse, error)
+	SetURLRules(ctx context.Context, in *SetURLRulesRequest, opts ...grpc.CallOption) (*SetURLRulesResponse, error)
+	GetURLRules(ctx context.Context, in *GetURLRulesRequest, opts ...grpc.CallOption) (*GetURLRulesResponse, error)
 }
 
 type shortenerServiceClient struct {
@@ -109,6 +127,96 @@ func (c *shortenerServiceClient) BatchDelete(ctx context.Context, in *BatchDelet
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetLinkStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeleteJobResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetDeleteJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) UpdateURL(ctx context.Context, in *UpdateURLRequest, opts ...grpc.CallOption) (*UpdateURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateURLResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RestoreURLs(ctx context.Context, in *RestoreURLsRequest, opts ...grpc.CallOption) (*RestoreURLsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreURLsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreURLs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) SetURLTags(ctx context.Context, in *SetURLTagsRequest, opts ...grpc.CallOption) (*SetURLTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetURLTagsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_SetURLTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetTagStats(ctx context.Context, in *GetTagStatsRequest, opts ...grpc.CallOption) (*GetTagStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetTagStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) SetURLActiveWindow(ctx context.Context, in *SetURLActiveWindowRequest, opts ...grpc.CallOption) (*SetURLActiveWindowResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetURLActiveWindowResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_SetURLActiveWindow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) SetURLRules(ctx context.Context, in *SetURLRulesRequest, opts ...grpc.CallOption) (*SetURLRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetURLRulesResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_SetURLRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetURLRules(ctx context.Context, in *GetURLRulesRequest, opts ...grpc.CallOption) (*GetURLRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetURLRulesResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetURLRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility.
@@ -119,6 +227,15 @@ type ShortenerServiceServer interface {
 	GetUserURLs(context.Context, *GetUserURLsRequest) (*GetUserURLsResponse, error)
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error)
+	GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error)
+	UpdateURL(context.Context, *UpdateURLRequest) (*UpdateURLResponse, error)
+	RestoreURLs(context.Context, *RestoreURLsRequest) (*RestoreURLsResponse, error)
+	SetURLTags(context.Context, *SetURLTagsRequest) (*SetURLTagsResponse, error)
+	GetTagStats(context.Context, *GetTagStatsRequest) (*GetTagStatsResponse, error)
+	SetURLActiveWindow(context.Context, *SetURLActiveWindowRequest) (*SetURLActiveWindowResponse, error)
+	SetURLRules(context.Context, *SetURLRulesRequest) (*SetURLRulesResponse, error)
+	GetURLRules(context.Context, *GetURLRulesRequest) (*GetURLRulesResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -147,6 +264,33 @@ func (UnimplementedShortenerServiceServer) GetStats(context.Context, *GetStatsRe
 func (UnimplementedShortenerServiceServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeleteJob not implemented")
+}
+func (UnimplementedShortenerServiceServer) UpdateURL(context.Context, *UpdateURLRequest) (*UpdateURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateURL not implemented")
+}
+func (UnimplementedShortenerServiceServer) RestoreURLs(context.Context, *RestoreURLsRequest) (*RestoreURLsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreURLs not implemented")
+}
+func (UnimplementedShortenerServiceServer) SetURLTags(context.Context, *SetURLTagsRequest) (*SetURLTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLTags not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetTagStats(context.Context, *GetTagStatsRequest) (*GetTagStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTagStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) SetURLActiveWindow(context.Context, *SetURLActiveWindowRequest) (*SetURLActiveWindowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLActiveWindow not implemented")
+}
+func (UnimplementedShortenerServiceServer) SetURLRules(context.Context, *SetURLRulesRequest) (*SetURLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetURLRules not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetURLRules(context.Context, *GetURLRulesRequest) (*GetURLRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetURLRules not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 func (UnimplementedShortenerServiceServer) testEmbeddedByValue()                          {}
 
@@ -276,6 +420,168 @@ func _ShortenerService_BatchDelete_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_GetLinkStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetLinkStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, req.(*GetLinkStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetDeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeleteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetDeleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, req.(*GetDeleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_UpdateURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateURL(ctx, req.(*UpdateURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RestoreURLs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreURLsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreURLs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreURLs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreURLs(ctx, req.(*RestoreURLsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_SetURLTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).SetURLTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_SetURLTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).SetURLTags(ctx, req.(*SetURLTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetTagStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetTagStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetTagStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetTagStats(ctx, req.(*GetTagStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_SetURLActiveWindow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLActiveWindowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).SetURLActiveWindow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_SetURLActiveWindow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).SetURLActiveWindow(ctx, req.(*SetURLActiveWindowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_SetURLRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetURLRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).SetURLRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_SetURLRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).SetURLRules(ctx, req.(*SetURLRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetURLRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetURLRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetURLRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetURLRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetURLRules(ctx, req.(*GetURLRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortenerService_ServiceDesc is the grpc.ServiceDesc for ShortenerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -307,6 +613,42 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BatchDelete",
 			Handler:    _ShortenerService_BatchDelete_Handler,
 		},
+		{
+			MethodName: "GetLinkStats",
+			Handler:    _ShortenerService_GetLinkStats_Handler,
+		},
+		{
+			MethodName: "GetDeleteJob",
+			Handler:    _ShortenerService_GetDeleteJob_Handler,
+		},
+		{
+			MethodName: "UpdateURL",
+			Handler:    _ShortenerService_UpdateURL_Handler,
+		},
+		{
+			MethodName: "RestoreURLs",
+			Handler:    _ShortenerService_RestoreURLs_Handler,
+		},
+		{
+			MethodName: "SetURLTags",
+			Handler:    _ShortenerService_SetURLTags_Handler,
+		},
+		{
+			MethodName: "GetTagStats",
+			Handler:    _ShortenerService_GetTagStats_Handler,
+		},
+		{
+			MethodName: "SetURLActiveWindow",
+			Handler:    _ShortenerService_SetURLActiveWindow_Handler,
+		},
+		{
+			MethodName: "SetURLRules",
+			Handler:    _ShortenerService_SetURLRules_Handler,
+		},
+		{
+			MethodName: "GetURLRules",
+			Handler:    _ShortenerService_GetURLRules_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/shortener.proto",
@@ -3,6 +3,7 @@ package grpcapi
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/interceptors"
 	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/proto"
@@ -11,9 +12,21 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // CreateURL is the gRPC equivalent of the HTTP PostHandler from package handlers.
+// It returns the existing short URL with codes.AlreadyExists if the original URL
+// is already shortened according to the deduplication policy.
+// An optional alias is used as the short URL ID, codes.InvalidArgument is returned
+// if it is invalid and codes.AlreadyExists with a distinct message if it is taken.
+// An optional expiration is set by either expires_at or ttl, an optional title and notes are kept as is
+// and optional tags are normalized. An optional redirect code replaces the server default one
+// and an optional password protects the short URL. An optional click limit deactivates the short URL
+// after the number of redirects and an optional activation window limits the time it redirects within.
+// The original URL is normalized, codes.InvalidArgument is returned
+// if it is invalid or its scheme is not allowed.
 func (s *GRPCShortenerServer) CreateURL(ctx context.Context, req *proto.CreateURLRequest) (*proto.CreateURLResponse, error) {
 	if req.OriginalUrl == "" {
 		return nil, status.Error(codes.InvalidArgument, "original_url is empty")
@@ -26,8 +39,22 @@ func (s *GRPCShortenerServer) CreateURL(ctx context.Context, req *proto.CreateUR
 	}
 
 	// Call CreateShortURL from app.
-	shortURL, err := s.svc.CreateShortURL(ctx, req.OriginalUrl, userID)
-	if errors.Is(err, database.ErrorDuplicate) {
+	opts := createOptions(req.Alias, req.GetExpiresAt(), req.GetTtl())
+	opts.Title, opts.Notes, opts.Tags = req.GetTitle(), req.GetNotes(), req.GetTags()
+	opts.RedirectCode, opts.Password = int(req.GetRedirectCode()), req.GetPassword()
+	opts.MaxClicks = int(req.GetMaxClicks())
+	opts.NotBefore, opts.NotAfter = optionalTime(req.GetNotBefore()), optionalTime(req.GetNotAfter())
+	shortURL, err := s.svc.CreateShortURL(ctx, req.OriginalUrl, userID, opts)
+	if errors.Is(err, app.ErrInvalidURL) || errors.Is(err, app.ErrInvalidAlias) || errors.Is(err, app.ErrInvalidExpiry) ||
+		errors.Is(err, app.ErrInvalidMetadata) || errors.Is(err, app.ErrInvalidTags) ||
+		errors.Is(err, app.ErrInvalidRedirectCode) || errors.Is(err, app.ErrInvalidPassword) || errors.Is(err, app.ErrInvalidMaxClicks) ||
+		errors.Is(err, app.ErrInvalidWindow) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if errors.Is(err, app.ErrEmptyURL) {
+		return nil, status.Error(codes.InvalidArgument, "original_url is empty")
+	} else if errors.Is(err, app.ErrAliasTaken) {
+		return nil, status.Error(codes.AlreadyExists, "alias already taken")
+	} else if errors.Is(err, database.ErrorDuplicate) {
 		return &proto.CreateURLResponse{ShortUrl: shortURL}, status.Error(codes.AlreadyExists, "URL already exists")
 	} else if err != nil {
 		return nil, status.Error(codes.Internal, "Failed to save URL")
@@ -37,6 +64,7 @@ func (s *GRPCShortenerServer) CreateURL(ctx context.Context, req *proto.CreateUR
 }
 
 // BatchShorten is the gRPC equivalent of the HTTP BatchShortenHandler from package handlers.
+// Every item gets its own status, a rejected atomic batch is reported by the rejected flag.
 func (s *GRPCShortenerServer) BatchShorten(ctx context.Context, req *proto.BatchShortenRequest) (*proto.BatchShortenResponse, error) {
 	// Get userID from context (using interceptor).
 	userID, ok := interceptors.GetUserIDFromContext(ctx)
@@ -47,15 +75,24 @@ func (s *GRPCShortenerServer) BatchShorten(ctx context.Context, req *proto.Batch
 	// Convert input to internal logic structure BatchReq.
 	var requests []app.BatchReq
 	for _, item := range req.GetItems() {
+		opts := createOptions(item.Alias, item.GetExpiresAt(), item.GetTtl())
+		opts.Title, opts.Notes, opts.Tags = item.GetTitle(), item.GetNotes(), item.GetTags()
+		opts.RedirectCode, opts.Password = int(item.GetRedirectCode()), item.GetPassword()
+		opts.MaxClicks = int(item.GetMaxClicks())
+		opts.NotBefore, opts.NotAfter = optionalTime(item.GetNotBefore()), optionalTime(item.GetNotAfter())
 		requests = append(requests, app.BatchReq{
 			CorrelationID: item.CorrelationId,
 			OriginalURL:   item.OriginalUrl,
+			CreateOptions: opts,
 		})
 	}
 
 	// Call to BatchShorten from app.
-	results, err := s.svc.BatchShorten(ctx, userID, requests)
-	if err != nil {
+	results, err := s.svc.BatchShorten(ctx, userID, requests, app.BatchMode(req.GetMode()))
+	rejected := errors.Is(err, app.ErrBatchRejected)
+	if errors.Is(err, app.ErrUnknownBatchMode) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && !rejected {
 		return nil, status.Errorf(codes.Internal, "BatchShorten error: %v", err)
 	}
 
@@ -65,10 +102,35 @@ func (s *GRPCShortenerServer) BatchShorten(ctx context.Context, req *proto.Batch
 		respItems = append(respItems, &proto.BatchShortenResponse_Item{
 			CorrelationId: r.CorrelationID,
 			ShortUrl:      r.ShortURL,
+			Status:        string(r.Status),
+			Error:         r.Error,
 		})
 	}
 
 	return &proto.BatchShortenResponse{
-		Items: respItems,
+		Items:    respItems,
+		Rejected: rejected,
 	}, nil
 }
+
+// createOptions converts the optional fields of a request to app.CreateOptions.
+func createOptions(alias string, expiresAt *timestamppb.Timestamp, ttl *durationpb.Duration) app.CreateOptions {
+	opts := app.CreateOptions{Alias: alias}
+	if expiresAt != nil {
+		t := expiresAt.AsTime()
+		opts.ExpiresAt = &t
+	}
+	if ttl != nil {
+		opts.TTL = ttl.AsDuration()
+	}
+	return opts
+}
+
+// optionalTime converts an optional timestamp to a time, nil if the timestamp is not set.
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
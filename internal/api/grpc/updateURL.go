@@ -0,0 +1,191 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/interceptors"
+	"github.com/KirillZiborov/lnkshortener/internal/api/grpc/proto"
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// UpdateURL is the gRPC equivalent of the HTTP UpdateURLHandler from package handlers.
+func (s *GRPCShortenerServer) UpdateURL(ctx context.Context, req *proto.UpdateURLRequest) (*proto.UpdateURLResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to UpdateURL from app.
+	rev, err := s.svc.UpdateURL(ctx, userID, req.GetShortId(), req.GetOriginalUrl())
+	switch {
+	case errors.Is(err, app.ErrEmptyURL):
+		return nil, status.Error(codes.InvalidArgument, "original_url is empty")
+	case errors.Is(err, app.ErrInvalidURL):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, "URL not found")
+	case errors.Is(err, database.ErrorDuplicate):
+		return nil, status.Error(codes.AlreadyExists, "URL already exists")
+	case errors.Is(err, app.ErrURLDeleted):
+		return nil, status.Error(codes.FailedPrecondition, "URL is deleted")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to update URL: %v", err)
+	}
+
+	return &proto.UpdateURLResponse{
+		ShortUrl:  rev.ShortURL,
+		OldUrl:    rev.OldURL,
+		NewUrl:    rev.NewURL,
+		ChangedAt: timestamppb.New(rev.ChangedAt),
+	}, nil
+}
+
+// SetURLTags is the gRPC equivalent of the HTTP SetURLTagsHandler from package handlers.
+func (s *GRPCShortenerServer) SetURLTags(ctx context.Context, req *proto.SetURLTagsRequest) (*proto.SetURLTagsResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to SetURLTags from app.
+	tags, err := s.svc.SetURLTags(ctx, userID, req.GetShortId(), req.GetTags())
+	switch {
+	case errors.Is(err, app.ErrInvalidTags):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, "URL not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to set tags: %v", err)
+	}
+
+	return &proto.SetURLTagsResponse{Tags: tags}, nil
+}
+
+// SetURLActiveWindow is the gRPC equivalent of the HTTP SetURLActiveWindowHandler from package handlers.
+func (s *GRPCShortenerServer) SetURLActiveWindow(ctx context.Context, req *proto.SetURLActiveWindowRequest) (*proto.SetURLActiveWindowResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to SetURLActiveWindow from app.
+	err := s.svc.SetURLActiveWindow(ctx, userID, req.GetShortId(), optionalTime(req.GetNotBefore()), optionalTime(req.GetNotAfter()))
+	switch {
+	case errors.Is(err, app.ErrInvalidWindow):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, "URL not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to set activation window: %v", err)
+	}
+
+	return &proto.SetURLActiveWindowResponse{NotBefore: req.GetNotBefore(), NotAfter: req.GetNotAfter()}, nil
+}
+
+// SetURLRules is the gRPC equivalent of the HTTP SetURLRulesHandler from package handlers.
+func (s *GRPCShortenerServer) SetURLRules(ctx context.Context, req *proto.SetURLRulesRequest) (*proto.SetURLRulesResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to SetURLRules from app.
+	rules, err := s.svc.SetURLRules(ctx, userID, req.GetShortId(), fileRules(req.GetRules()))
+	switch {
+	case errors.Is(err, app.ErrInvalidRules):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, "URL not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to set redirect rules: %v", err)
+	}
+
+	return &proto.SetURLRulesResponse{Rules: protoRules(rules)}, nil
+}
+
+// GetURLRules is the gRPC equivalent of the HTTP GetURLRulesHandler from package handlers.
+func (s *GRPCShortenerServer) GetURLRules(ctx context.Context, req *proto.GetURLRulesRequest) (*proto.GetURLRulesResponse, error) {
+	if req.GetShortId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no short_id provided")
+	}
+
+	// Get userID from context (using interceptor).
+	userID, ok := interceptors.GetUserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing userID in context")
+	}
+
+	// Call to GetURLRules from app.
+	originalURL, rules, err := s.svc.GetURLRules(ctx, userID, req.GetShortId())
+	switch {
+	case errors.Is(err, app.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, "URL not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to get redirect rules: %v", err)
+	}
+
+	return &proto.GetURLRulesResponse{DefaultUrl: originalURL, Rules: protoRules(rules)}, nil
+}
+
+// fileRules converts the redirect rules of a request to the stored ones.
+func fileRules(rules []*proto.RedirectRule) []file.RedirectRule {
+	res := make([]file.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		rule := file.RedirectRule{Device: r.GetDevice(), Language: r.GetLanguage(), Target: r.GetTarget()}
+		if len(r.GetQuery()) > 0 {
+			rule.Query = make(map[string]string, len(r.GetQuery()))
+			for _, q := range r.GetQuery() {
+				rule.Query[q.GetName()] = q.GetValue()
+			}
+		}
+		res = append(res, rule)
+	}
+	return res
+}
+
+// protoRules converts the stored redirect rules to the ones of a response,
+// the query parameters of a rule are ordered by name.
+func protoRules(rules []file.RedirectRule) []*proto.RedirectRule {
+	res := make([]*proto.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		rule := &proto.RedirectRule{Device: r.Device, Language: r.Language, Target: r.Target}
+		names := make([]string, 0, len(r.Query))
+		for name := range r.Query {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			rule.Query = append(rule.Query, &proto.QueryParam{Name: name, Value: r.Query[name]})
+		}
+		res = append(res, rule)
+	}
+	return res
+}
@@ -4,19 +4,31 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/go-chi/chi"
+
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/auth"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
 )
 
+// DeleteJobResponse holds the ID of a deletion job and the number of its short URLs in each status.
+type DeleteJobResponse struct {
+	JobID string `json:"job_id"`
+	deletion.Counts
+}
+
 // BatchDeleteHandler handles the deletion of multiple shortened URLs for an authenticated user.
 // It expects a DELETE request with a JSON array of short URL IDs.
-// Upon successful deletion, it responds with a 202 Accepted status and processes the deletion asynchronously.
+// Upon successful submission, it responds with a 202 Accepted status and the deletion job in JSON format,
+// the deletion is processed asynchronously and its progress is available at the Location header.
 //
 // Possible error codes in response:
 // - 400 (Bad Request) if the request body is empty.
 // - 401 (Unauthorized) if the authentification token is invalid.
+// - 503 (Service Unavailable) if the server is shutting down.
 // - 500 (Internal Server Error) if the server fails.
 func BatchDeleteHandler(svc *app.ShortenerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -25,6 +37,7 @@ func BatchDeleteHandler(svc *app.ShortenerService) http.HandlerFunc {
 		userID, err := auth.AuthGet(r)
 		if err != nil {
 			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
 		}
 
 		var ids []string
@@ -36,9 +49,106 @@ func BatchDeleteHandler(svc *app.ShortenerService) http.HandlerFunc {
 		}
 		defer r.Body.Close()
 
+		// Save the deletion job to be processed asynchronously.
+		job, err := svc.BatchDeleteAsync(r.Context(), userID, ids)
+		if errors.Is(err, deletion.ErrQueueClosed) {
+			http.Error(w, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to delete URLs", http.StatusInternalServerError)
+			return
+		}
+
 		// Respond with a 202 Accepted status indicating that the deletion is being processed.
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/user/urls/delete-jobs/"+job.ID)
 		w.WriteHeader(http.StatusAccepted)
-		// Process the batch deletion asynchronously.
-		svc.BatchDeleteAsync(userID, ids)
+		json.NewEncoder(w).Encode(DeleteJobResponse{JobID: job.ID, Counts: job.Counts()})
+	}
+}
+
+// RestoreResponse holds the IDs of the restored short URLs and the ones that cannot be restored in JSON format.
+type RestoreResponse struct {
+	Restored    []string `json:"restored"`
+	NotRestored []string `json:"not_restored"`
+}
+
+// RestoreURLsHandler restores multiple deleted short URLs of an authenticated user.
+// It expects a POST request with a JSON array of short URL IDs.
+// Short URLs can be restored within the retention from the configuration after they are deleted.
+// It responds with the restored IDs and the ones that cannot be restored in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the request body is empty.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 500 (Internal Server Error) if the server fails.
+func RestoreURLsHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate the user and retrieve the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		var ids []string
+		// Decode the JSON request body into a slice of short URL IDs.
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil || len(ids) == 0 {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+
+		// Call to RestoreURLs from app.
+		restored, err := svc.RestoreURLs(r.Context(), userID, ids)
+		if err != nil {
+			http.Error(w, "Failed to restore URLs", http.StatusInternalServerError)
+			return
+		}
+
+		resp := RestoreResponse{Restored: restored, NotRestored: []string{}}
+		done := make(map[string]bool, len(restored))
+		for _, id := range restored {
+			done[id] = true
+		}
+		for _, id := range ids {
+			if !done[id] {
+				resp.NotRestored = append(resp.NotRestored, id)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// GetDeleteJobHandler returns the progress of a deletion job submitted by the authenticated user.
+// It responds with the number of short URLs pending, deleted and failed to be deleted
+// in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the job does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func GetDeleteJobHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Call to GetDeleteJob from app.
+		job, err := svc.GetDeleteJob(r.Context(), userID, chi.URLParam(r, "id"))
+		if errors.Is(err, app.ErrJobNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to get deletion job", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(DeleteJobResponse{JobID: job.ID, Counts: job.Counts()})
 	}
 }
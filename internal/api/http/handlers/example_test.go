@@ -9,14 +9,15 @@ import (
 	"strings"
 
 	"github.com/KirillZiborov/lnkshortener/internal/app"
-	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
 )
 
 // ExamplePostHandler demonstrates how to use the PostHandler.
 func ExamplePostHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -46,7 +47,7 @@ func ExamplePostHandler() {
 func ExampleAPIShortenHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -82,7 +83,7 @@ func ExampleAPIShortenHandler() {
 func ExampleBatchShortenHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -121,7 +122,7 @@ func ExampleBatchShortenHandler() {
 func ExampleGetHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -147,7 +148,7 @@ func ExampleGetHandler() {
 func ExampleGetUserURLsHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -176,7 +177,7 @@ func ExampleGetUserURLsHandler() {
 func ExampleBatchDeleteHandler() {
 	// Initialize the configuration.
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
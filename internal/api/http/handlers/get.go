@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi"
@@ -12,15 +14,27 @@ import (
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/auth"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
 )
 
 // GetHandler handles redirection from a short URL to the original URL.
-// It expects a GET request with the short URL.
-// Upon finding the original URL, it redirects the client to it with a 302 Found status.
+// It expects a GET or HEAD request with the short URL.
+// Upon finding the original URL, it redirects the client to it, or to the target of the first redirect rule
+// matching the User-Agent and Accept-Language headers and the query parameters of the request,
+// with the redirect status code of the short URL: 301, 302, 307 or 308. Permanent redirects are allowed to be cached,
+// temporary ones are not. Clicks of GET requests are recorded asynchronously,
+// HEAD requests of link checkers are not counted as clicks. Every GET request of a click-limited
+// short URL counts against its limit, HEAD requests do not and get no Location of such a short URL.
+// A password-protected short URL is answered with the password form posted to UnlockHandler.
+// A short URL which is not active yet is answered according to the inactive response from the configuration:
+// as if it did not exist, with the inactive page or with a 302 Found redirect to the fallback URL.
 //
 // Possible error codes in response:
-// - 404 (Not Found) if there is no original URL for the requested short URL.
-// - 410 (Gone) if the URL is deleted.
+// - 401 (Unauthorized) with the password form if the short URL is password-protected.
+// - 404 (Not Found) if there is no original URL for the requested short URL, or if it is not active yet.
+// - 410 (Gone) if the URL is deleted, has expired, is no longer active or has reached its click limit.
 // - 500 (Internal Server Error) if the server fails.
 func GetHandler(svc *app.ShortenerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -28,21 +42,94 @@ func GetHandler(svc *app.ShortenerService) http.HandlerFunc {
 		// Extract a shortURL from request parameters.
 		id := chi.URLParam(r, "id")
 
-		// Call to GetShortURL from app.
-		originalURL, err := svc.GetShortURL(r.Context(), id)
-		if errors.Is(err, app.ErrURLNotFound) {
-			http.Error(w, "Not found", http.StatusNotFound)
-			return
-		} else if errors.Is(err, app.ErrURLDeleted) {
-			http.Error(w, "URL has been deleted", http.StatusGone)
+		// Call to GetRedirect from app.
+		redirect, err := svc.GetRedirect(r.Context(), id, app.RedirectRequest{
+			Peek:           r.Method == http.MethodHead,
+			UserAgent:      r.UserAgent(),
+			AcceptLanguage: r.Header.Get("Accept-Language"),
+			Query:          r.URL.Query(),
+		})
+		if errors.Is(err, app.ErrPasswordRequired) {
+			writeUnlockForm(w, r, id, "", http.StatusUnauthorized)
 			return
 		} else if err != nil {
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			writeRedirectError(w, r, svc, err)
 			return
 		}
+
+		// Record the click without delaying the redirect.
+		if r.Method != http.MethodHead {
+			svc.RecordClick(id, r.Referer(), r.UserAgent(), clientIP(r))
+		}
+
 		// Redirect to the original URL.
-		w.Header().Set("Location", originalURL)
-		w.WriteHeader(http.StatusTemporaryRedirect)
+		if redirect.MaxAge > 0 {
+			w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(redirect.MaxAge.Seconds())))
+		} else {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		if redirect.URL != "" {
+			w.Header().Set("Location", redirect.URL)
+		}
+		w.WriteHeader(redirect.Code)
+	}
+}
+
+// inactivePage is the built-in page served for short URLs which are not active yet.
+const inactivePage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Link is not active yet</title>
+</head>
+<body>
+<p>This link is not active yet, try again later.</p>
+</body>
+</html>
+`
+
+// writeNotYetActive responds to a short URL which is not active yet
+// according to the inactive response from the configuration.
+// The response is never cached, so the short URL works as soon as it becomes active.
+func writeNotYetActive(w http.ResponseWriter, r *http.Request, svc *app.ShortenerService) {
+	w.Header().Set("Cache-Control", "no-store")
+	switch svc.Cfg.InactiveResponse {
+	case config.InactiveShowPage:
+		page := svc.InactivePage
+		if len(page) == 0 {
+			page = []byte(inactivePage)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		if r.Method != http.MethodHead {
+			w.Write(page)
+		}
+	case config.InactiveRedirect:
+		w.Header().Set("Location", svc.Cfg.InactiveFallbackURL)
+		w.WriteHeader(http.StatusFound)
+	default:
+		http.Error(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// writeRedirectError responds with the status of an error of following a short URL.
+func writeRedirectError(w http.ResponseWriter, r *http.Request, svc *app.ShortenerService, err error) {
+	switch {
+	case errors.Is(err, app.ErrURLNotYetActive):
+		writeNotYetActive(w, r, svc)
+	case errors.Is(err, app.ErrURLNotFound):
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, app.ErrURLDeleted):
+		http.Error(w, "URL has been deleted", http.StatusGone)
+	case errors.Is(err, app.ErrURLExpired):
+		http.Error(w, "URL has expired", http.StatusGone)
+	case errors.Is(err, app.ErrURLNoLongerActive):
+		http.Error(w, "URL is no longer active", http.StatusGone)
+	case errors.Is(err, app.ErrURLExhausted):
+		http.Error(w, "URL has reached its click limit", http.StatusGone)
+	default:
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
 	}
 }
 
@@ -67,11 +154,50 @@ func PingDBHandler(db *pgxpool.Pool) http.HandlerFunc {
 	}
 }
 
-// GetUserURLsHandler retrieves all URLs created by the authenticated user.
-// It expects a GET request and responds with a JSON array of the user's URLs and a 200 OK status.
+// UserURLResponse holds a URL created by the user in JSON format.
+type UserURLResponse struct {
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+	Deleted     bool   `json:"deleted"`
+	// CreatedAt is omitted for URLs saved before the creation time was kept.
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	Title          string     `json:"title,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	Tags           []string   `json:"tags,omitempty"`
+	// RedirectCode is omitted for URLs redirecting with the server default code.
+	RedirectCode int `json:"redirect_code,omitempty"`
+	// Protected is true if a password is required to follow the URL.
+	Protected bool `json:"protected,omitempty"`
+	// MaxClicks and UsedClicks are omitted for URLs without a click limit.
+	MaxClicks  int `json:"max_clicks,omitempty"`
+	UsedClicks int `json:"used_clicks,omitempty"`
+	// NotBefore and NotAfter are omitted for URLs without an activation window.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	// Rules are omitted for URLs redirecting every visitor to the original URL.
+	Rules []file.RedirectRule `json:"rules,omitempty"`
+}
+
+// GetUserURLsHandler retrieves a page of the URLs created by the authenticated user ordered by creation time.
+// It expects a GET request with optional query parameters:
+// - limit: the maximum number of URLs in the page, all URLs if not set.
+// - cursor: the cursor of the page from the X-Next-Cursor header of the previous page.
+// - sort: "created_asc" (default) or "created_desc".
+// - status: "active" or "deleted" to list only URLs which are not deleted or deleted ones.
+// - contains: a substring of the original URLs.
+// - created_from, created_to: RFC 3339 times limiting the creation period, created_from is inclusive
+// and created_to is exclusive.
+// - tag: a tag of the URLs.
+// It responds with a JSON array of the user's URLs and a 200 OK status.
+// The number of URLs matching the filters is set in the X-Total-Count header
+// and the cursor of the next page in the X-Next-Cursor header if there is one.
 //
 // Possible error codes in response:
-// - 204 (No Content) if there is no user's URLs.
+// - 204 (No Content) if there is no user's URLs in the page.
+// - 400 (Bad Request) if the query parameters are invalid.
 // - 401 (Unauthorized) if the authentification token is invalid.
 // - 500 (Internal Server Error) if the server fails.
 func GetUserURLsHandler(svc *app.ShortenerService) http.HandlerFunc {
@@ -80,25 +206,153 @@ func GetUserURLsHandler(svc *app.ShortenerService) http.HandlerFunc {
 		userID, err := auth.AuthGet(r)
 		if err != nil {
 			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Parse the query parameters.
+		params := r.URL.Query()
+		q := app.URLsQuery{
+			Cursor:   params.Get("cursor"),
+			Sort:     params.Get("sort"),
+			Status:   file.URLStatus(params.Get("status")),
+			Contains: params.Get("contains"),
+			Tag:      params.Get("tag"),
+		}
+		if v := params.Get("limit"); v != "" {
+			if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 {
+				http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+				return
+			}
+		}
+		if q.CreatedFrom, err = parseTimeParam(params.Get("created_from")); err != nil {
+			http.Error(w, "created_from must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		if q.CreatedTo, err = parseTimeParam(params.Get("created_to")); err != nil {
+			http.Error(w, "created_to must be an RFC 3339 time", http.StatusBadRequest)
+			return
 		}
 
 		// Call to GetUserURLs from app.
-		records, err := svc.GetUserURLs(r.Context(), userID)
-		if err != nil {
+		page, err := svc.GetUserURLs(r.Context(), userID, q)
+		if errors.Is(err, app.ErrInvalidURLsQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if err != nil {
 			http.Error(w, "Failed to get a list of user's URLs", http.StatusInternalServerError)
 			return
 		}
 
+		w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+		if page.NextCursor != "" {
+			w.Header().Set("X-Next-Cursor", page.NextCursor)
+		}
+
 		// Respond with 204 StatusNoContent if there is no URLs found in storage.
-		if len(records) == 0 {
+		if len(page.Records) == 0 {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
 
+		resp := make([]UserURLResponse, 0, len(page.Records))
+		for _, rec := range page.Records {
+			url := UserURLResponse{
+				ShortURL:       rec.ShortURL,
+				OriginalURL:    rec.OriginalURL,
+				Deleted:        rec.DeletedFlag,
+				UpdatedAt:      rec.UpdatedAt,
+				DeletedAt:      rec.DeletedAt,
+				LastAccessedAt: rec.LastAccessedAt,
+				Title:          rec.Title,
+				Notes:          rec.Notes,
+				Tags:           rec.Tags,
+				RedirectCode:   rec.RedirectCode,
+				Protected:      rec.PasswordHash != "",
+				MaxClicks:      rec.MaxClicks,
+				UsedClicks:     rec.UsedClicks,
+				NotBefore:      rec.NotBefore,
+				NotAfter:       rec.NotAfter,
+				Rules:          rec.Rules,
+			}
+			if !rec.CreatedAt.IsZero() {
+				url.CreatedAt = &rec.CreatedAt
+			}
+			resp = append(resp, url)
+		}
+
 		// Respond with the user's URLs.
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(records)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// GetURLRulesHandler returns the redirect rules of a short URL owned by the authenticated user.
+// It expects a GET request and responds with the rules in the order they are checked
+// and the original URL as the default target in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func GetURLRulesHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Call to GetURLRules from app.
+		id := chi.URLParam(r, "id")
+		originalURL, rules, err := svc.GetURLRules(r.Context(), userID, id)
+		if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to get redirect rules", http.StatusInternalServerError)
+			return
+		}
+		if rules == nil {
+			rules = []file.RedirectRule{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RulesResponse{ShortURL: svc.Cfg.BaseURL + "/" + id, Default: originalURL, Rules: rules})
+	}
+}
+
+// GetTagStatsHandler returns the number of URLs created by the authenticated user and their clicks by tags.
+// It expects a GET request and responds with a JSON array of the statistics of every tag ordered by tag
+// and a 200 OK status.
+//
+// Possible error codes in response:
+// - 204 (No Content) if the user has no tags.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 500 (Internal Server Error) if the server fails.
+func GetTagStatsHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Call to GetTagStats from app.
+		stats, err := svc.GetTagStats(r.Context(), userID)
+		if err != nil {
+			http.Error(w, "Failed to get tag stats", http.StatusInternalServerError)
+			return
+		}
+		if len(stats) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
 	}
 }
 
@@ -109,6 +363,21 @@ type StatsResponse struct {
 
 	// Users is a number of unique users in the service.
 	Users int `json:"users"`
+
+	// Cache holds the redirect cache counters, it is omitted if the cache is disabled.
+	Cache *CacheStatsResponse `json:"cache,omitempty"`
+}
+
+// CacheStatsResponse holds the redirect cache counters in JSON format.
+type CacheStatsResponse struct {
+	// Hits is a number of redirects served from the cache.
+	Hits int64 `json:"hits"`
+
+	// Misses is a number of redirects the storage is queried for.
+	Misses int64 `json:"misses"`
+
+	// Size is a number of cached short URLs.
+	Size int `json:"size"`
 }
 
 // GetStatsHandler checks if an IP address is in trusted subnet.
@@ -139,7 +408,7 @@ func GetStatsHandler(svc *app.ShortenerService) http.HandlerFunc {
 		}
 
 		// Call to GetStats from app.
-		urls, users, err := svc.GetStats()
+		urls, users, err := svc.GetStats(r.Context())
 		if err != nil {
 			http.Error(w, "Failed to get stats", http.StatusInternalServerError)
 			return
@@ -149,8 +418,142 @@ func GetStatsHandler(svc *app.ShortenerService) http.HandlerFunc {
 			URLs:  urls,
 			Users: users,
 		}
+		if cache, ok := svc.GetCacheStats(); ok {
+			resp.Cache = &CacheStatsResponse{
+				Hits:   cache.Hits,
+				Misses: cache.Misses,
+				Size:   cache.Size,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// clientIP returns the client IP address from the X-Real-IP header set by a proxy,
+// or the remote address of the connection if there is no header.
+func clientIP(r *http.Request) string {
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// LinkStatsResponse holds click statistics of a short URL in JSON format.
+type LinkStatsResponse struct {
+	// ShortURL is the short URL the statistics belong to.
+	ShortURL string `json:"short_url"`
+	clicks.Stats
+}
+
+// GetLinkStatsHandler returns click statistics of a short URL owned by the authenticated user.
+// It expects a GET request with optional query parameters:
+// - bucket: "hour" or "day" (default), the interval clicks are counted by.
+// - from, to: RFC 3339 times limiting the period, from is inclusive and to is exclusive.
+// It responds with the total number of clicks and non-empty buckets in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the query parameters are invalid.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func GetLinkStatsHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Parse the query parameters.
+		params := r.URL.Query()
+		q := app.StatsQuery{Bucket: params.Get("bucket")}
+		if q.From, err = parseTimeParam(params.Get("from")); err != nil {
+			http.Error(w, "from must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		if q.To, err = parseTimeParam(params.Get("to")); err != nil {
+			http.Error(w, "to must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+
+		// Call to GetLinkStats from app.
+		id := chi.URLParam(r, "id")
+		stats, err := svc.GetLinkStats(r.Context(), userID, id, q)
+		if errors.Is(err, app.ErrInvalidStatsQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to get link stats", http.StatusInternalServerError)
+			return
+		}
+
+		resp := LinkStatsResponse{
+			ShortURL: svc.Cfg.BaseURL + "/" + id,
+			Stats:    stats,
+		}
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(resp)
 	}
 }
+
+// GetURLHistoryHandler returns the changes of the original URL of a short URL owned by the authenticated user.
+// It expects a GET request and responds with the revisions in the order they were made
+// in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 204 (No Content) if the original URL has never changed.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func GetURLHistoryHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Call to GetURLHistory from app.
+		revisions, err := svc.GetURLHistory(r.Context(), userID, chi.URLParam(r, "id"))
+		if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to get URL history", http.StatusInternalServerError)
+			return
+		}
+
+		if len(revisions) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		resp := make([]RevisionResponse, 0, len(revisions))
+		for _, rev := range revisions {
+			resp = append(resp, newRevisionResponse(rev))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter, empty value results in zero time.
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -2,14 +2,21 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KirillZiborov/lnkshortener/internal/app"
 	"github.com/KirillZiborov/lnkshortener/internal/config"
-	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
 )
 
 func NewTestConfig() *config.Config {
@@ -23,7 +30,7 @@ func NewTestConfig() *config.Config {
 
 func BenchmarkPostHandler(b *testing.B) {
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -48,7 +55,7 @@ func BenchmarkPostHandler(b *testing.B) {
 
 func BenchmarkAPIShortenHandler(b *testing.B) {
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -73,7 +80,7 @@ func BenchmarkAPIShortenHandler(b *testing.B) {
 
 func BenchmarkBatchShortenHandler(b *testing.B) {
 	cfg := NewTestConfig()
-	urlStore := file.NewFileStore(cfg.FilePath)
+	urlStore := memory.NewMemoryStore(config.DedupGlobal)
 	service := app.ShortenerService{
 		Store: urlStore,
 		Cfg:   cfg,
@@ -104,3 +111,70 @@ func BenchmarkBatchShortenHandler(b *testing.B) {
 		}
 	}
 }
+
+func TestNotYetActive(t *testing.T) {
+	notBefore := time.Now().Add(time.Hour)
+	tests := []struct {
+		name     string
+		response string
+		page     []byte
+		code     int
+		location string
+		body     string
+	}{
+		{name: "not found", response: config.InactiveNotFound, code: http.StatusNotFound, body: "Not found"},
+		{name: "built-in page", response: config.InactiveShowPage, code: http.StatusNotFound, body: "not active yet"},
+		{name: "custom page", response: config.InactiveShowPage, page: []byte("<p>Coming soon</p>"), code: http.StatusNotFound, body: "Coming soon"},
+		{name: "redirect", response: config.InactiveRedirect, code: http.StatusFound, location: "https://example.com/soon"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewTestConfig()
+			cfg.InactiveResponse = tc.response
+			cfg.InactiveFallbackURL = "https://example.com/soon"
+			service := app.ShortenerService{
+				Store:        memory.NewMemoryStore(config.DedupGlobal),
+				Cfg:          cfg,
+				InactivePage: tc.page,
+			}
+			_, err := service.CreateShortURL(context.Background(), "https://ya.ru", "user1", app.CreateOptions{
+				Alias:     "launch",
+				NotBefore: &notBefore,
+			})
+			require.NoError(t, err)
+
+			r := chi.NewRouter()
+			r.Get("/{id}", GetHandler(&service))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/launch", nil))
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			assert.Equal(t, tc.location, w.Header().Get("Location"))
+			assert.Contains(t, w.Body.String(), tc.body)
+		})
+	}
+}
+
+func TestUnlockFormAction(t *testing.T) {
+	service := app.ShortenerService{
+		Store: memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:   NewTestConfig(),
+	}
+	_, err := service.CreateShortURL(context.Background(), "https://ya.ru", "user1", app.CreateOptions{
+		Alias:    "protected",
+		Password: "secret",
+	})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Post("/{id}/unlock", UnlockHandler(&service))
+	req := httptest.NewRequest(http.MethodPost, "/protected/unlock?utm_source=mail", strings.NewReader("password=wrong"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// The form shown again at "/protected/unlock" posts to the same route, not relative to it.
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), `action="/protected/unlock?utm_source=mail"`)
+}
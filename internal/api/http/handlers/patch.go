@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/KirillZiborov/lnkshortener/internal/api/http/auth"
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// RevisionResponse holds a change of the original URL of a short URL in JSON format.
+type RevisionResponse struct {
+	ShortURL  string    `json:"short_url"`
+	UserID    string    `json:"user_id"`
+	OldURL    string    `json:"old_url"`
+	NewURL    string    `json:"new_url"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// newRevisionResponse converts a revision to its JSON representation.
+func newRevisionResponse(rev file.URLRevision) RevisionResponse {
+	return RevisionResponse{
+		ShortURL:  rev.ShortURL,
+		UserID:    rev.UserUUID,
+		OldURL:    rev.OldURL,
+		NewURL:    rev.NewURL,
+		ChangedAt: rev.ChangedAt,
+	}
+}
+
+// UpdateURLHandler changes the original URL of a short URL owned by the authenticated user.
+// It expects a PATCH request with the new original URL in JSON format: {"url": "..."}.
+// It responds with the recorded revision in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the request body is invalid or the URL is empty or invalid.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 409 (Conflict) if the new original URL is already shortened according to the deduplication policy.
+// - 410 (Gone) if the short URL is deleted.
+// - 500 (Internal Server Error) if the server fails.
+func UpdateURLHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req jsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		// Call to UpdateURL from app.
+		rev, err := svc.UpdateURL(r.Context(), userID, chi.URLParam(r, "id"), req.URL)
+		switch {
+		case errors.Is(err, app.ErrEmptyURL), errors.Is(err, app.ErrInvalidURL):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case errors.Is(err, app.ErrURLNotFound):
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		case errors.Is(err, database.ErrorDuplicate):
+			http.Error(w, "URL already exists", http.StatusConflict)
+			return
+		case errors.Is(err, app.ErrURLDeleted):
+			http.Error(w, "URL is deleted", http.StatusGone)
+			return
+		case err != nil:
+			http.Error(w, "Failed to update URL", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newRevisionResponse(*rev))
+	}
+}
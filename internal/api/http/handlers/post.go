@@ -3,8 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/KirillZiborov/lnkshortener/internal/api/http/auth"
 	"github.com/KirillZiborov/lnkshortener/internal/app"
@@ -15,9 +17,10 @@ import (
 // Upon successful creation, it responds with a 201 Created status and the shortened URL.
 //
 // Possible error codes in response:
-// - 400 (Bad Request) if the request body is empty.
+// - 400 (Bad Request) if the request body is empty or is not a valid URL with an allowed scheme,
+// the reason is in the response body.
 // - 401 (Unauthorized) if the authentification token is invalid.
-// - 409 (Conflict) if the shortURL already exists for the original URL.
+// - 409 (Conflict) if the original URL is already shortened according to the deduplication policy.
 // - 500 (Internal Server Error) if the server fails.
 func PostHandler(svc *app.ShortenerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -36,8 +39,11 @@ func PostHandler(svc *app.ShortenerService) http.HandlerFunc {
 		}
 
 		// Call CreateShortURL from app.
-		shortURL, err := svc.CreateShortURL(r.Context(), originalURL, userID)
-		if errors.Is(err, database.ErrorDuplicate) {
+		shortURL, err := svc.CreateShortURL(r.Context(), originalURL, userID, app.CreateOptions{})
+		if errors.Is(err, app.ErrEmptyURL) || errors.Is(err, app.ErrInvalidURL) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, database.ErrorDuplicate) {
 			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 			w.WriteHeader(http.StatusConflict)
 			w.Write([]byte(shortURL))
@@ -56,7 +62,36 @@ func PostHandler(svc *app.ShortenerService) http.HandlerFunc {
 
 // jsonRequest holds an original URL in JSON format.
 type jsonRequest struct {
-	URL string `json:"url"`
+	URL       string     `json:"url"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TTL       string     `json:"ttl,omitempty"`
+	Title     string     `json:"title,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	// RedirectCode is one of 301, 302, 307 or 308, the server default if omitted.
+	RedirectCode int `json:"redirect_code,omitempty"`
+	// Password protects the short URL if it is set.
+	Password string `json:"password,omitempty"`
+	// MaxClicks is the number of redirects the short URL is deactivated after, unlimited if omitted.
+	MaxClicks int `json:"max_clicks,omitempty"`
+	// NotBefore and NotAfter limit the time the short URL redirects within.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+}
+
+// createOptions converts the optional fields of a request to app.CreateOptions.
+// The TTL is a duration string like "24h".
+func createOptions(alias string, expiresAt *time.Time, ttl string) (app.CreateOptions, error) {
+	opts := app.CreateOptions{Alias: alias, ExpiresAt: expiresAt}
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return opts, fmt.Errorf("%w: ttl must be a duration like \"24h\"", app.ErrInvalidExpiry)
+		}
+		opts.TTL = d
+	}
+	return opts, nil
 }
 
 // JSONResponse holds a short URL in JSON format.
@@ -65,13 +100,21 @@ type JSONResponse struct {
 }
 
 // APIShortenHandler handles the creation of a new shortened URL in JSON format.
-// It expects a POST request with a JSON payload containing the original URL.
+// It expects a POST request with a JSON payload containing the original URL,
+// an optional custom alias to use as the short URL ID, an optional expiration
+// as either an absolute RFC 3339 time "expires_at" or a duration "ttl", an optional "title", "notes",
+// an array of "tags", a "redirect_code" of 301, 302, 307 or 308, a "password" protecting the short URL
+// "max_clicks", the number of redirects the short URL is deactivated after, 1 for a one-time link,
+// and RFC 3339 times "not_before" and "not_after" limiting the time the short URL redirects within.
 // Upon successful creation, it responds with a 201 Created status and the shortened URL.
 //
 // Possible error codes in response:
-// - 400 (Bad Request) if the original URL is empty.
+// - 400 (Bad Request) if the original URL is empty, invalid or has a scheme that is not allowed,
+// the alias, the expiration, the tags, the redirect code, the click limit or the activation window are invalid,
+// or the title, the notes or the password are too long.
 // - 401 (Unauthorized) if the authentification token is invalid.
-// - 409 (Conflict) if the shortURL already exists for the original URL.
+// - 409 (Conflict) if the original URL is already shortened according to the deduplication policy,
+// or if the alias is already taken.
 // - 500 (Internal Server Error) if the server fails.
 func APIShortenHandler(svc *app.ShortenerService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -91,9 +134,28 @@ func APIShortenHandler(svc *app.ShortenerService) http.HandlerFunc {
 			return
 		}
 
+		// Convert the optional fields to creation options.
+		opts, err := createOptions(req.Alias, req.ExpiresAt, req.TTL)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		opts.Title, opts.Notes, opts.Tags = req.Title, req.Notes, req.Tags
+		opts.RedirectCode, opts.Password, opts.MaxClicks = req.RedirectCode, req.Password, req.MaxClicks
+		opts.NotBefore, opts.NotAfter = req.NotBefore, req.NotAfter
+
 		// Call to CreateShortURL from app.
-		shortURL, err := svc.CreateShortURL(r.Context(), req.URL, userID)
-		if errors.Is(err, database.ErrorDuplicate) {
+		shortURL, err := svc.CreateShortURL(r.Context(), req.URL, userID, opts)
+		if errors.Is(err, app.ErrEmptyURL) || errors.Is(err, app.ErrInvalidURL) || errors.Is(err, app.ErrInvalidAlias) ||
+			errors.Is(err, app.ErrInvalidExpiry) || errors.Is(err, app.ErrInvalidMetadata) || errors.Is(err, app.ErrInvalidTags) ||
+			errors.Is(err, app.ErrInvalidRedirectCode) || errors.Is(err, app.ErrInvalidPassword) || errors.Is(err, app.ErrInvalidMaxClicks) ||
+			errors.Is(err, app.ErrInvalidWindow) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrAliasTaken) {
+			http.Error(w, "Alias is already taken", http.StatusConflict)
+			return
+		} else if errors.Is(err, database.ErrorDuplicate) {
 			res := JSONResponse{
 				Result: shortURL,
 			}
@@ -127,22 +189,42 @@ func APIShortenHandler(svc *app.ShortenerService) http.HandlerFunc {
 
 // BatchRequest holds correlation ID and corresponding original URL in JSON format.
 type BatchRequest struct {
-	CorrelationID string `json:"correlation_id"`
-	OriginalURL   string `json:"original_url"`
+	CorrelationID string     `json:"correlation_id"`
+	OriginalURL   string     `json:"original_url"`
+	Alias         string     `json:"alias,omitempty"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	TTL           string     `json:"ttl,omitempty"`
+	Title         string     `json:"title,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	RedirectCode  int        `json:"redirect_code,omitempty"`
+	Password      string     `json:"password,omitempty"`
+	MaxClicks     int        `json:"max_clicks,omitempty"`
+	NotBefore     *time.Time `json:"not_before,omitempty"`
+	NotAfter      *time.Time `json:"not_after,omitempty"`
 }
 
-// BatchResponse holds correlation ID and corresponding short URL in JSON format.
+// BatchResponse holds correlation ID and the outcome of shortening the corresponding original URL in JSON format.
 type BatchResponse struct {
 	CorrelationID string `json:"correlation_id"`
-	ShortURL      string `json:"short_url"`
+	ShortURL      string `json:"short_url,omitempty"`
+	// Status is one of "created", "duplicate", "invalid" or "aborted".
+	Status string `json:"status"`
+	// Error is the reason the item is invalid.
+	Error string `json:"error,omitempty"`
 }
 
 // BatchShortenHandler handles the creation of multiple shortened URLs in a single request.
-// It expects a POST request with a JSON array of original URLs.
-// Upon successful creation, it responds with a 201 Created status and an array of shortened URLs.
+// It expects a POST request with a JSON array of original URLs with optional custom aliases
+// and expirations. The optional "mode" query parameter selects how invalid items are handled:
+// "best-effort" (default) saves the valid items, "atomic" saves nothing if any item is invalid.
+// It responds with an array of the item outcomes: a created short URL, the existing short URL
+// of a duplicate original URL, or the reason the item is invalid.
 //
-// Possible error codes in response:
-// - 400 (Bad Request) if the request body is empty.
+// Possible codes in response:
+// - 201 (Created) if the batch is processed, some of the items may be invalid in the best-effort mode.
+// - 400 (Bad Request) if the request body is empty or the mode is unknown,
+// or with the item outcomes if the atomic batch is rejected.
 // - 401 (Unauthorized) if the authentification token is invalid.
 // - 500 (Internal Server Error) if the server fails.
 func BatchShortenHandler(svc *app.ShortenerService) http.HandlerFunc {
@@ -166,32 +248,50 @@ func BatchShortenHandler(svc *app.ShortenerService) http.HandlerFunc {
 		// Convert batchRequests to internal logic structure BatchReq.
 		reqs := make([]app.BatchReq, 0, len(batchRequests))
 		for _, br := range batchRequests {
+			opts, err := createOptions(br.Alias, br.ExpiresAt, br.TTL)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			opts.Title, opts.Notes, opts.Tags = br.Title, br.Notes, br.Tags
+			opts.RedirectCode, opts.Password, opts.MaxClicks = br.RedirectCode, br.Password, br.MaxClicks
+			opts.NotBefore, opts.NotAfter = br.NotBefore, br.NotAfter
 			reqs = append(reqs, app.BatchReq{
 				CorrelationID: br.CorrelationID,
 				OriginalURL:   br.OriginalURL,
+				CreateOptions: opts,
 			})
 		}
 
 		// Call to BatchShorten from app.
-		results, err := svc.BatchShorten(r.Context(), userID, reqs)
-		if err != nil {
+		mode := app.BatchMode(r.URL.Query().Get("mode"))
+		results, err := svc.BatchShorten(r.Context(), userID, reqs, mode)
+		code := http.StatusCreated
+		if errors.Is(err, app.ErrUnknownBatchMode) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrBatchRejected) {
+			code = http.StatusBadRequest
+		} else if err != nil {
 			http.Error(w, "Failed to save URL", http.StatusInternalServerError)
 			return
 		}
 
 		// Initialize a slice of BatchResponse.
-		batchResponses := make([]BatchResponse, 0, len(batchRequests))
+		batchResponses := make([]BatchResponse, 0, len(results))
 		// Convert back to BatchResponse with JSON.
 		for _, r := range results {
 			batchResponses = append(batchResponses, BatchResponse{
 				CorrelationID: r.CorrelationID,
 				ShortURL:      r.ShortURL,
+				Status:        string(r.Status),
+				Error:         r.Error,
 			})
 		}
 
-		// Respond with the array of shortened URLs.
+		// Respond with the array of item outcomes.
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(code)
 		json.NewEncoder(w).Encode(batchResponses)
 	}
 }
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/KirillZiborov/lnkshortener/internal/api/http/auth"
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// TagsResponse holds the tags of a short URL in JSON format.
+type TagsResponse struct {
+	ShortURL string   `json:"short_url"`
+	Tags     []string `json:"tags"`
+}
+
+// SetURLTagsHandler replaces the tags of a short URL owned by the authenticated user.
+// It expects a PUT request with a JSON array of tags, an empty array removes all tags.
+// It responds with the tags in the canonical form in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the request body or the tags are invalid.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func SetURLTagsHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		var tags []string
+		if err := json.NewDecoder(r.Body).Decode(&tags); err != nil {
+			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		// Call to SetURLTags from app.
+		id := chi.URLParam(r, "id")
+		tags, err = svc.SetURLTags(r.Context(), userID, id, tags)
+		if errors.Is(err, app.ErrInvalidTags) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to set tags", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(TagsResponse{ShortURL: svc.Cfg.BaseURL + "/" + id, Tags: tags})
+	}
+}
+
+// ActiveWindowRequest holds the activation window of a short URL in JSON format.
+// An omitted or null time leaves that end of the window open.
+type ActiveWindowRequest struct {
+	NotBefore *time.Time `json:"not_before"`
+	NotAfter  *time.Time `json:"not_after"`
+}
+
+// ActiveWindowResponse holds the activation window of a short URL in JSON format.
+type ActiveWindowResponse struct {
+	ShortURL  string     `json:"short_url"`
+	NotBefore *time.Time `json:"not_before"`
+	NotAfter  *time.Time `json:"not_after"`
+}
+
+// SetURLActiveWindowHandler replaces the activation window of a short URL owned by the authenticated user.
+// It expects a PUT request with RFC 3339 times "not_before" and "not_after" in JSON format,
+// the short URL only redirects at or after "not_before" and before "not_after".
+// An omitted or null time leaves that end of the window open, so {} makes the short URL always active.
+// It responds with the activation window in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the request body or the window is invalid.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func SetURLActiveWindowHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req ActiveWindowRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		// Call to SetURLActiveWindow from app.
+		id := chi.URLParam(r, "id")
+		err = svc.SetURLActiveWindow(r.Context(), userID, id, req.NotBefore, req.NotAfter)
+		if errors.Is(err, app.ErrInvalidWindow) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to set activation window", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ActiveWindowResponse{
+			ShortURL:  svc.Cfg.BaseURL + "/" + id,
+			NotBefore: req.NotBefore,
+			NotAfter:  req.NotAfter,
+		})
+	}
+}
+
+// RulesResponse holds the redirect rules of a short URL in JSON format.
+type RulesResponse struct {
+	ShortURL string `json:"short_url"`
+	// Default is the original URL visitors matching no rule are redirected to.
+	Default string              `json:"default,omitempty"`
+	Rules   []file.RedirectRule `json:"rules"`
+}
+
+// SetURLRulesHandler replaces the redirect rules of a short URL owned by the authenticated user.
+// It expects a PUT request with a JSON array of rules, an empty array removes all rules.
+// Every rule has a "target" and at least one condition: a "device" of "ios", "android", "desktop" or "bot",
+// a "language" tag like "de" or "pt-br" and an object of "query" parameters, an empty value matches any value.
+// The first rule matching a visitor selects the target, the original URL is the default.
+// It responds with the rules in the canonical form in JSON format and a 200 OK status.
+//
+// Possible error codes in response:
+// - 400 (Bad Request) if the request body or the rules are invalid.
+// - 401 (Unauthorized) if the authentification token is invalid.
+// - 404 (Not Found) if the short URL does not exist or belongs to another user.
+// - 500 (Internal Server Error) if the server fails.
+func SetURLRulesHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Authenticate and get the user ID.
+		userID, err := auth.AuthGet(r)
+		if err != nil {
+			http.Error(w, "Unathorized", http.StatusUnauthorized)
+			return
+		}
+
+		var rules []file.RedirectRule
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+			return
+		}
+
+		// Call to SetURLRules from app.
+		id := chi.URLParam(r, "id")
+		rules, err = svc.SetURLRules(r.Context(), userID, id, rules)
+		if errors.Is(err, app.ErrInvalidRules) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		} else if errors.Is(err, app.ErrURLNotFound) {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, "Failed to set redirect rules", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(RulesResponse{ShortURL: svc.Cfg.BaseURL + "/" + id, Rules: rules})
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/KirillZiborov/lnkshortener/internal/app"
+)
+
+// unlockForm is the page asking for the password of a protected short URL.
+// The form is posted to "/{id}/unlock" with the query of the request, so the redirect rules
+// matching query parameters apply after unlocking. The action is absolute, since the form
+// is shown again at "/{id}/unlock" after a wrong password.
+var unlockForm = template.Must(template.New("unlock").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+</head>
+<body>
+<form method="post" action="{{.Action}}">
+<p>This link is protected with a password.</p>
+{{if .Message}}<p role="alert">{{.Message}}</p>{{end}}
+<input type="password" name="password" autocomplete="current-password" autofocus required>
+<button type="submit">Open</button>
+</form>
+</body>
+</html>
+`))
+
+// writeUnlockForm responds with the password form of the short URL ID and an optional message.
+func writeUnlockForm(w http.ResponseWriter, r *http.Request, id, message string, code int) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if r.Method == http.MethodHead {
+		return
+	}
+	action := "/" + id + "/unlock"
+	if r.URL.RawQuery != "" {
+		action += "?" + r.URL.RawQuery
+	}
+	unlockForm.Execute(w, struct{ Action, Message string }{Action: action, Message: message})
+}
+
+// UnlockHandler follows a password-protected short URL.
+// It expects a POST request of the form served by GetHandler with the "password" field.
+// Upon a correct password, it redirects the client to the original URL with a 303 See Other status,
+// so the original URL is requested with GET, and records the click asynchronously.
+// Wrong passwords are limited per short URL.
+//
+// Possible error codes in response:
+// - 401 (Unauthorized) with the form again if the password is empty or wrong.
+// - 404 (Not Found) if there is no original URL for the requested short URL, or if it is not active yet.
+// - 410 (Gone) if the URL is deleted, has expired, is no longer active or has reached its click limit.
+// - 429 (Too Many Requests) with the form again if the short URL is locked after too many wrong passwords.
+// - 500 (Internal Server Error) if the server fails.
+func UnlockHandler(svc *app.ShortenerService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Extract a shortURL from request parameters.
+		id := chi.URLParam(r, "id")
+
+		// Call to GetRedirect from app with the password from the form.
+		redirect, err := svc.GetRedirect(r.Context(), id, app.RedirectRequest{
+			Password:       r.PostFormValue("password"),
+			UserAgent:      r.UserAgent(),
+			AcceptLanguage: r.Header.Get("Accept-Language"),
+			Query:          r.URL.Query(),
+		})
+		switch {
+		case errors.Is(err, app.ErrPasswordRequired):
+			writeUnlockForm(w, r, id, "Enter the password.", http.StatusUnauthorized)
+			return
+		case errors.Is(err, app.ErrWrongPassword):
+			writeUnlockForm(w, r, id, "Wrong password.", http.StatusUnauthorized)
+			return
+		case errors.Is(err, app.ErrTooManyAttempts):
+			writeUnlockForm(w, r, id, "Too many wrong passwords, try again later.", http.StatusTooManyRequests)
+			return
+		case err != nil:
+			writeRedirectError(w, r, svc, err)
+			return
+		}
+
+		// Record the click without delaying the redirect.
+		svc.RecordClick(id, r.Referer(), r.UserAgent(), clientIP(r))
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Location", redirect.URL)
+		w.WriteHeader(http.StatusSeeOther)
+	}
+}
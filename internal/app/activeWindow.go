@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+)
+
+// ErrInvalidWindow is returned when the activation window of a short URL is invalid.
+// The returned error wraps it together with the reason.
+var ErrInvalidWindow = errors.New("invalid activation window")
+
+// validateWindow checks that the activation window ends after it starts, either end may be open.
+func validateWindow(notBefore, notAfter *time.Time) error {
+	if notBefore != nil && notAfter != nil && !notAfter.After(*notBefore) {
+		return fmt.Errorf("%w: not_after must be after not_before", ErrInvalidWindow)
+	}
+	return nil
+}
+
+// SetURLActiveWindow changes the activation window of the short URL ID owned by the user.
+// The short URL only redirects at or after notBefore and before notAfter, nil leaves the end open.
+//
+// Returns:
+// - ErrInvalidWindow if the window is invalid, ErrURLNotFound if the short URL does not exist
+// or belongs to another user, or an error if the storage fails.
+func (s *ShortenerService) SetURLActiveWindow(ctx context.Context, userID, shortID string, notBefore, notAfter *time.Time) error {
+	if err := validateWindow(notBefore, notAfter); err != nil {
+		return err
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	err := s.Store.SetActiveWindow(ctx, s.Cfg.BaseURL+"/"+shortID, userID, notBefore, notAfter)
+	// Do not reveal that other users' short URLs exist.
+	if errors.Is(err, os.ErrProcessDone) || errors.Is(err, database.ErrorNotOwner) {
+		return ErrURLNotFound
+	}
+	return err
+}
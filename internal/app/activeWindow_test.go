@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestActiveWindow(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080", PermanentRedirectMaxAge: config.Duration(24 * time.Hour)},
+	}
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "bad", NotBefore: at(time.Hour), NotAfter: at(time.Minute)})
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+
+	_, err = s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{
+		Alias:        "launch",
+		RedirectCode: http.StatusPermanentRedirect,
+		NotBefore:    at(time.Hour),
+	})
+	require.NoError(t, err)
+	_, err = s.GetRedirect(ctx, "launch", RedirectRequest{})
+	assert.ErrorIs(t, err, ErrURLNotYetActive)
+
+	// Only the owner changes the window.
+	err = s.SetURLActiveWindow(ctx, "user2", "launch", nil, nil)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+	err = s.SetURLActiveWindow(ctx, "user1", "launch", at(time.Hour), at(time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+
+	// A cached permanent redirect does not outlive the window.
+	require.NoError(t, s.SetURLActiveWindow(ctx, "user1", "launch", at(-time.Hour), at(time.Hour)))
+	redirect, err := s.GetRedirect(ctx, "launch", RedirectRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", redirect.URL)
+	assert.LessOrEqual(t, redirect.MaxAge, time.Hour)
+	assert.Greater(t, redirect.MaxAge, time.Duration(0))
+
+	require.NoError(t, s.SetURLActiveWindow(ctx, "user1", "launch", nil, at(-time.Minute)))
+	_, err = s.GetRedirect(ctx, "launch", RedirectRequest{})
+	assert.ErrorIs(t, err, ErrURLNoLongerActive)
+
+	rec, err := s.Store.GetURLRecord(ctx, "http://localhost:8080/launch")
+	require.NoError(t, err)
+	assert.Nil(t, rec.NotBefore)
+	require.NotNil(t, rec.NotAfter)
+	assert.NotNil(t, rec.UpdatedAt)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Alias length limits.
+const (
+	minAliasLength = 3
+	maxAliasLength = 64
+)
+
+// reservedAliases holds path segments used by the service itself.
+// They are compared case-insensitively.
+var reservedAliases = map[string]struct{}{
+	"api":   {},
+	"ping":  {},
+	"debug": {},
+}
+
+var (
+	// ErrInvalidAlias is returned when a custom alias breaks the validation rules.
+	// The returned error wraps it together with the reason.
+	ErrInvalidAlias = errors.New("invalid alias")
+	// ErrAliasTaken is returned when a custom alias is already used by another short URL.
+	ErrAliasTaken = errors.New("alias is already taken")
+)
+
+// ValidateAlias checks a custom alias against the validation rules:
+// it must be 3 to 64 characters long, consist of latin letters, digits, '-' and '_',
+// and must not be a reserved word.
+//
+// Returns:
+// - An error wrapping ErrInvalidAlias with the reason if the alias is invalid.
+func ValidateAlias(alias string) error {
+	if len(alias) < minAliasLength || len(alias) > maxAliasLength {
+		return fmt.Errorf("%w: length must be between %d and %d characters", ErrInvalidAlias, minAliasLength, maxAliasLength)
+	}
+
+	for _, c := range alias {
+		if !isAliasChar(c) {
+			return fmt.Errorf("%w: character %q is not allowed, use letters, digits, '-' and '_'", ErrInvalidAlias, c)
+		}
+	}
+
+	if isReserved(alias) {
+		return fmt.Errorf("%w: %q is reserved", ErrInvalidAlias, alias)
+	}
+
+	return nil
+}
+
+// isReserved reports whether the ID is a path segment used by the service itself.
+func isReserved(id string) bool {
+	_, ok := reservedAliases[strings.ToLower(id)]
+	return ok
+}
+
+// isAliasChar reports whether the character is allowed in aliases.
+func isAliasChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
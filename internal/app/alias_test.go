@@ -0,0 +1,36 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		alias   string
+		wantErr bool
+	}{
+		{name: "valid", alias: "spring-sale", wantErr: false},
+		{name: "digits and underscore", alias: "sale_2024", wantErr: false},
+		{name: "too short", alias: "ab", wantErr: true},
+		{name: "too long", alias: strings.Repeat("a", 65), wantErr: true},
+		{name: "invalid character", alias: "spring/sale", wantErr: true},
+		{name: "non-latin", alias: "распродажа", wantErr: true},
+		{name: "reserved", alias: "api", wantErr: true},
+		{name: "reserved in upper case", alias: "PING", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateAlias(tc.alias)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidAlias)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/KirillZiborov/lnkshortener/internal/cache"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// CacheStats holds the counters of the redirect cache.
+type CacheStats struct {
+	// Hits is a number of lookups served from the cache, including the unknown short URLs.
+	Hits int64
+	// Misses is a number of lookups the storage is queried for.
+	Misses int64
+	// Size is a number of cached short URLs.
+	Size int
+}
+
+// CachedStore is a URLStore keeping recently looked up URL records in a size-bounded LRU cache.
+// Unknown short URLs are cached as well for a shorter time, so repeated requests for them
+// do not reach the storage. Concurrent lookups of the same uncached short URL query the storage once.
+//
+// Cached records are invalidated when they are saved, changed, deleted, restored, purged or expired through the store.
+// Changes made by other replicas are applied with HandleChange if the storage publishes them,
+// otherwise they become visible when the cached records expire.
+type CachedStore struct {
+	URLStore
+
+	lru         *cache.LRU[string, *file.URLRecord]
+	ttl         time.Duration
+	negativeTTL time.Duration
+	readTimeout time.Duration
+	group       singleflight.Group
+
+	// mu orders invalidations with caching of the records being looked up concurrently,
+	// gen is incremented on every invalidation.
+	mu  sync.Mutex
+	gen uint64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachedStore wraps the store with a redirect cache.
+//
+// Parameters:
+// - store: The storage to be cached.
+// - size: The maximum number of cached short URLs.
+// - ttl: The time a found URL record is cached for.
+// - negativeTTL: The time an unknown short URL is cached for, zero disables negative caching.
+// - readTimeout: The time limit of a lookup shared by concurrent callers, zero means no limit.
+//
+// Returns:
+// - A pointer to a CachedStore instance.
+func NewCachedStore(store URLStore, size int, ttl, negativeTTL, readTimeout time.Duration) *CachedStore {
+	return &CachedStore{
+		URLStore:    store,
+		lru:         cache.NewLRU[string, *file.URLRecord](size),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		readTimeout: readTimeout,
+	}
+}
+
+// NewRedirectCache wraps the store with a redirect cache configured by cfg.
+// The store is returned as is if the cache is disabled.
+func NewRedirectCache(cfg *config.Config, store URLStore) URLStore {
+	if cfg.RedirectCacheSize < 0 {
+		return store
+	}
+	return NewCachedStore(store, cfg.RedirectCacheSize,
+		time.Duration(cfg.RedirectCacheTTL), time.Duration(cfg.RedirectCacheNegativeTTL), time.Duration(cfg.StorageReadTimeout))
+}
+
+// GetURLRecord returns the cached URL record of the short URL or retrieves it from the storage.
+// An unknown short URL results in os.ErrProcessDone as it does in the storage.
+// The lookup shared by concurrent callers is not canceled with the context of any of them,
+// a caller whose context is done stops waiting for it.
+func (c *CachedStore) GetURLRecord(ctx context.Context, shortURL string) (*file.URLRecord, error) {
+	if rec, ok := c.lru.Get(shortURL); ok {
+		c.hits.Add(1)
+		return cachedRecord(rec)
+	}
+	c.misses.Add(1)
+
+	ch := c.group.DoChan(shortURL, func() (interface{}, error) {
+		c.mu.Lock()
+		gen := c.gen
+		c.mu.Unlock()
+
+		lookupCtx, cancel := withTimeout(context.WithoutCancel(ctx), c.readTimeout)
+		defer cancel()
+
+		rec, err := c.URLStore.GetURLRecord(lookupCtx, shortURL)
+		switch {
+		case err == nil:
+			c.add(gen, shortURL, rec, c.ttl)
+		case errors.Is(err, os.ErrProcessDone):
+			c.add(gen, shortURL, nil, c.negativeTTL)
+		}
+		return rec, err
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return cachedRecord(res.Val.(*file.URLRecord))
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SaveURLRecord saves the URL record and invalidates its short URL, which may be cached as unknown.
+func (c *CachedStore) SaveURLRecord(ctx context.Context, urlRecord *file.URLRecord) (string, error) {
+	defer c.invalidate(urlRecord.ShortURL)
+	return c.URLStore.SaveURLRecord(ctx, urlRecord)
+}
+
+// SaveURLRecords saves the batch of URL records and invalidates their short URLs.
+func (c *CachedStore) SaveURLRecords(ctx context.Context, records []*file.URLRecord, atomic bool) ([]file.SaveResult, error) {
+	shortURLs := make([]string, len(records))
+	for i, rec := range records {
+		shortURLs[i] = rec.ShortURL
+	}
+	defer c.invalidate(shortURLs...)
+	return c.URLStore.SaveURLRecords(ctx, records, atomic)
+}
+
+// UpdateOriginalURL changes the original URL of the short URL and invalidates it.
+func (c *CachedStore) UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*file.URLRevision, error) {
+	defer c.invalidate(shortURL)
+	return c.URLStore.UpdateOriginalURL(ctx, shortURL, userID, originalURL)
+}
+
+// BatchUpdateDeleteFlag marks the URL records as deleted and invalidates their short URLs.
+func (c *CachedStore) BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error {
+	defer c.invalidate(urlIDs...)
+	return c.URLStore.BatchUpdateDeleteFlag(ctx, urlIDs, userID)
+}
+
+// HandleChange invalidates the short URL changed in the storage, possibly by another replica.
+// A resync after missed changes invalidates the whole cache.
+// It is meant to be subscribed to database.Listener.
+func (c *CachedStore) HandleChange(change database.Change) {
+	if change.Op == database.ChangeResync {
+		c.clear()
+		return
+	}
+	c.invalidate(change.ShortURL)
+}
+
+// PurgeDeleted removes the URL records deleted before the given time and clears the cache
+// if any are removed, since the store does not report which short URLs they are.
+func (c *CachedStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	n, err := c.URLStore.PurgeDeleted(ctx, deletedBefore)
+	if n > 0 || err != nil {
+		c.clear()
+	}
+	return n, err
+}
+
+// DeleteExpired removes or archives the URL records expired by the given time and clears the cache
+// if any are removed, since the store does not report which short URLs they are.
+func (c *CachedStore) DeleteExpired(ctx context.Context, now time.Time, policy string) (int, error) {
+	n, err := c.URLStore.DeleteExpired(ctx, now, policy)
+	if n > 0 || err != nil {
+		c.clear()
+	}
+	return n, err
+}
+
+// RestoreURLs restores the deleted URL records and invalidates their short URLs.
+func (c *CachedStore) RestoreURLs(ctx context.Context, urlIDs []string, userID string, deletedSince time.Time) ([]string, error) {
+	defer c.invalidate(urlIDs...)
+	return c.URLStore.RestoreURLs(ctx, urlIDs, userID, deletedSince)
+}
+
+// SetTags replaces the tags of the short URL and invalidates it.
+func (c *CachedStore) SetTags(ctx context.Context, shortURL, userID string, tags []string) error {
+	defer c.invalidate(shortURL)
+	return c.URLStore.SetTags(ctx, shortURL, userID, tags)
+}
+
+// SetActiveWindow changes the activation window of the short URL and invalidates it.
+func (c *CachedStore) SetActiveWindow(ctx context.Context, shortURL, userID string, notBefore, notAfter *time.Time) error {
+	defer c.invalidate(shortURL)
+	return c.URLStore.SetActiveWindow(ctx, shortURL, userID, notBefore, notAfter)
+}
+
+// SetRules replaces the redirect rules of the short URL and invalidates it.
+func (c *CachedStore) SetRules(ctx context.Context, shortURL, userID string, rules []file.RedirectRule) error {
+	defer c.invalidate(shortURL)
+	return c.URLStore.SetRules(ctx, shortURL, userID, rules)
+}
+
+// UseClick counts a redirect of the short URL and invalidates it, so the number of redirects left is fresh.
+func (c *CachedStore) UseClick(ctx context.Context, shortURL string) error {
+	defer c.invalidate(shortURL)
+	return c.URLStore.UseClick(ctx, shortURL)
+}
+
+// Stats returns the current counters of the cache.
+func (c *CachedStore) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   c.lru.Len(),
+	}
+}
+
+// add caches the record unless the cache is invalidated since the lookup generation gen.
+// A nil record marks the short URL as unknown.
+func (c *CachedStore) add(gen uint64, shortURL string, rec *file.URLRecord, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.lru.Add(shortURL, rec, ttl)
+	}
+}
+
+// invalidate removes the short URLs from the cache, lookups still in progress are not cached
+// and new lookups query the storage again.
+func (c *CachedStore) invalidate(shortURLs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.lru.Remove(shortURLs...)
+	for _, shortURL := range shortURLs {
+		c.group.Forget(shortURL)
+	}
+}
+
+// clear removes all short URLs from the cache, lookups still in progress are not cached.
+func (c *CachedStore) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.lru.Purge()
+}
+
+// cachedRecord returns a copy of the cached record, so callers cannot change the cache,
+// or os.ErrProcessDone if the short URL is cached as unknown.
+func cachedRecord(rec *file.URLRecord) (*file.URLRecord, error) {
+	if rec == nil {
+		return nil, os.ErrProcessDone
+	}
+	cp := *rec
+	return &cp, nil
+}
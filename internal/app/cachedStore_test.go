@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+// lookupStore counts lookups of the wrapped store, every lookup waits for release
+// or the end of its context if release is set.
+type lookupStore struct {
+	*memory.MemoryStore
+	lookups atomic.Int64
+	release chan struct{}
+}
+
+func (s *lookupStore) GetURLRecord(ctx context.Context, shortURL string) (*file.URLRecord, error) {
+	s.lookups.Add(1)
+	if s.release != nil {
+		select {
+		case <-s.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return s.MemoryStore.GetURLRecord(ctx, shortURL)
+}
+
+func TestCachedStore(t *testing.T) {
+	ctx := context.Background()
+	store := &lookupStore{MemoryStore: memory.NewMemoryStore(config.DedupNone)}
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "a", OriginalURL: "https://a.ru", UserUUID: "user1"})
+	require.NoError(t, err)
+
+	cached := NewCachedStore(store, 10, time.Minute, time.Minute, 0)
+
+	t.Run("hit", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			rec, err := cached.GetURLRecord(ctx, "a")
+			require.NoError(t, err)
+			assert.Equal(t, "https://a.ru", rec.OriginalURL)
+		}
+		assert.Equal(t, int64(1), store.lookups.Load())
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Size: 1}, cached.Stats())
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := cached.GetURLRecord(ctx, "b")
+			assert.ErrorIs(t, err, os.ErrProcessDone)
+		}
+		assert.Equal(t, int64(2), store.lookups.Load())
+
+		// Saving the unknown short URL invalidates it.
+		_, err := cached.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "b", OriginalURL: "https://b.ru", UserUUID: "user1"})
+		require.NoError(t, err)
+		rec, err := cached.GetURLRecord(ctx, "b")
+		require.NoError(t, err)
+		assert.Equal(t, "https://b.ru", rec.OriginalURL)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, cached.BatchUpdateDeleteFlag(ctx, []string{"a"}, "user1"))
+		rec, err := cached.GetURLRecord(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, rec.DeletedFlag)
+	})
+
+	t.Run("change", func(t *testing.T) {
+		_, err := cached.GetURLRecord(ctx, "c")
+		assert.ErrorIs(t, err, os.ErrProcessDone)
+
+		// Changes made by another replica bypass the cache until they are received.
+		_, err = store.MemoryStore.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "c", OriginalURL: "https://c.ru", UserUUID: "user2"})
+		require.NoError(t, err)
+		_, err = cached.GetURLRecord(ctx, "c")
+		assert.ErrorIs(t, err, os.ErrProcessDone)
+
+		cached.HandleChange(database.Change{Op: database.ChangeCreated, ShortURL: "c"})
+		rec, err := cached.GetURLRecord(ctx, "c")
+		require.NoError(t, err)
+		assert.Equal(t, "https://c.ru", rec.OriginalURL)
+
+		cached.HandleChange(database.Change{Op: database.ChangeResync})
+		assert.Zero(t, cached.Stats().Size)
+	})
+}
+
+func TestCachedStoreRemoved(t *testing.T) {
+	ctx := context.Background()
+	cached := NewCachedStore(memory.NewMemoryStore(config.DedupNone), 10, time.Minute, time.Minute, 0)
+	expiresAt := time.Now().Add(-time.Minute)
+	_, err := cached.SaveURLRecords(ctx, []*file.URLRecord{
+		{ShortURL: "a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{ShortURL: "b", OriginalURL: "https://b.ru", UserUUID: "user1", ExpiresAt: &expiresAt},
+	}, true)
+	require.NoError(t, err)
+	require.NoError(t, cached.BatchUpdateDeleteFlag(ctx, []string{"a"}, "user1"))
+
+	for _, shortURL := range []string{"a", "b"} {
+		_, err := cached.GetURLRecord(ctx, shortURL)
+		require.NoError(t, err)
+	}
+
+	// The records removed by the storage are not served from the cache.
+	n, err := cached.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = cached.GetURLRecord(ctx, "a")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+
+	n, err = cached.DeleteExpired(ctx, time.Now(), config.ExpiredPurge)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = cached.GetURLRecord(ctx, "b")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+}
+
+func TestCachedStoreSingleflight(t *testing.T) {
+	ctx := context.Background()
+	store := &lookupStore{MemoryStore: memory.NewMemoryStore(config.DedupNone), release: make(chan struct{})}
+	_, err := store.MemoryStore.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "a", OriginalURL: "https://a.ru"})
+	require.NoError(t, err)
+
+	cached := NewCachedStore(store, 10, time.Minute, time.Minute, 0)
+
+	// Concurrent misses of the same short URL wait for a single lookup.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec, err := cached.GetURLRecord(ctx, "a")
+			assert.NoError(t, err)
+			assert.Equal(t, "https://a.ru", rec.OriginalURL)
+		}()
+	}
+	require.Eventually(t, func() bool { return cached.Stats().Misses == 10 }, time.Second, time.Millisecond)
+	// Give the goroutines time to join the lookup in progress.
+	time.Sleep(10 * time.Millisecond)
+	close(store.release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), store.lookups.Load())
+}
+
+func TestCachedStoreSharedLookupCancel(t *testing.T) {
+	store := &lookupStore{MemoryStore: memory.NewMemoryStore(config.DedupNone), release: make(chan struct{})}
+	_, err := store.MemoryStore.SaveURLRecord(context.Background(), &file.URLRecord{ShortURL: "a", OriginalURL: "https://a.ru"})
+	require.NoError(t, err)
+
+	cached := NewCachedStore(store, 10, time.Minute, time.Minute, time.Minute)
+
+	// The first caller starts the lookup and gives up on it.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := cached.GetURLRecord(ctx, "a")
+		first <- err
+	}()
+	require.Eventually(t, func() bool { return store.lookups.Load() == 1 }, time.Second, time.Millisecond)
+
+	second := make(chan *file.URLRecord)
+	go func() {
+		rec, err := cached.GetURLRecord(context.Background(), "a")
+		assert.NoError(t, err)
+		second <- rec
+	}()
+	require.Eventually(t, func() bool { return cached.Stats().Misses == 2 }, time.Second, time.Millisecond)
+	// Give the second caller time to join the lookup in progress.
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	// The waiter still gets the record of the shared lookup.
+	close(store.release)
+	rec := <-second
+	require.NotNil(t, rec)
+	assert.Equal(t, "https://a.ru", rec.OriginalURL)
+	assert.Equal(t, int64(1), store.lookups.Load())
+}
+
+func TestCachedStoreIDProbes(t *testing.T) {
+	ctx := context.Background()
+	cached := NewCachedStore(memory.NewMemoryStore(config.DedupNone), 10, time.Minute, time.Minute, 0)
+	s := &ShortenerService{
+		Store: cached,
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080", IDMaxAttempts: 5},
+	}
+
+	// Generated IDs are checked against the wrapped store, so they are not cached as unknown.
+	shortURL, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{}, cached.Stats())
+
+	rec, err := cached.GetURLRecord(ctx, shortURL)
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", rec.OriginalURL)
+}
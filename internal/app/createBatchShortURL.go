@@ -2,55 +2,260 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/KirillZiborov/lnkshortener/internal/database"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
 )
 
-// BatchReq holds correlation ID and corresponding original URL.
+// BatchMode defines what happens to a batch if some of its items cannot be shortened.
+type BatchMode string
+
+// Batch modes supported by BatchShorten.
+const (
+	// BatchBestEffort saves the valid items and reports the invalid ones.
+	BatchBestEffort BatchMode = "best-effort"
+	// BatchAtomic saves nothing if any item is invalid.
+	BatchAtomic BatchMode = "atomic"
+)
+
+// BatchStatus is the outcome of shortening a single item of a batch.
+type BatchStatus string
+
+// Statuses of batch items.
+const (
+	BatchCreated   BatchStatus = "created"   // BatchCreated means a new short URL is created.
+	BatchDuplicate BatchStatus = "duplicate" // BatchDuplicate means the existing short URL of the original URL is returned.
+	BatchInvalid   BatchStatus = "invalid"   // BatchInvalid means the item cannot be shortened, the reason is in the error.
+	BatchAborted   BatchStatus = "aborted"   // BatchAborted means the valid item is not saved because the atomic batch is rejected.
+)
+
+var (
+	// ErrEmptyURL is returned when the original URL of a batch item is empty.
+	ErrEmptyURL = errors.New("original URL is empty")
+	// ErrUnknownBatchMode is returned when the batch mode is not one of BatchMode values.
+	ErrUnknownBatchMode = errors.New("unknown batch mode")
+	// ErrBatchRejected is returned together with the item results when an atomic batch
+	// is not saved because some of its items are invalid.
+	ErrBatchRejected = errors.New("batch rejected: some items are invalid")
+)
+
+// BatchReq holds correlation ID and corresponding original URL
+// together with the optional parameters of its short URL.
 type BatchReq struct {
 	CorrelationID string
 	OriginalURL   string
+	CreateOptions
 }
 
-// BatchRes holds correlation ID and corresponding short URL.
+// BatchRes holds correlation ID and the outcome of shortening the corresponding original URL.
 type BatchRes struct {
 	CorrelationID string
-	ShortURL      string
+	// ShortURL is the created or the existing short URL, empty for invalid and aborted items.
+	ShortURL string
+	Status   BatchStatus
+	// Error is the reason the item is invalid.
+	Error string
 }
 
-// BatchShorten handles a batch of URLs and returns a batch of corresponding short URLs.
-func (s *ShortenerService) BatchShorten(ctx context.Context, userID string, requests []BatchReq) ([]BatchRes, error) {
-	var results []BatchRes
+// BatchShorten handles a batch of URLs and returns the outcome of every item in the order of the requests.
+// An empty or invalid original URL, an invalid alias, an alias repeated within the batch or already taken,
+// an invalid expiration time, a too long title, notes or password, invalid tags, an unsupported redirect code,
+// a negative click limit and an invalid activation window make the item invalid.
+// An original URL that is already shortened according to the deduplication policy,
+// including the one repeated within the batch, results in the existing short URL.
+// All new short URLs are saved in a single storage operation.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - userID: The user ID the short URLs are created for.
+// - requests: The items of the batch.
+// - mode: BatchBestEffort to save the valid items only or BatchAtomic to save nothing if any item is invalid,
+// BatchBestEffort if empty.
+//
+// Returns:
+// - The results of all items.
+// - ErrBatchRejected together with the results if the atomic batch is not saved, ErrUnknownBatchMode,
+// or an error if the storage fails.
+func (s *ShortenerService) BatchShorten(ctx context.Context, userID string, requests []BatchReq, mode BatchMode) ([]BatchRes, error) {
+	switch mode {
+	case BatchBestEffort, BatchAtomic:
+	case "":
+		mode = BatchBestEffort
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBatchMode, mode)
+	}
 
-	// Iterate through all sent URLs.
-	for _, req := range requests {
-		// Generate a short URL.
-		id := generateID()
-		shortURL := s.Cfg.BaseURL + "/" + id
+	results := make([]BatchRes, len(requests))
+	for i, req := range requests {
+		results[i].CorrelationID = req.CorrelationID
+	}
 
-		// Create a structure with information about the URL.
-		urlRecord := &file.URLRecord{
-			UUID:        id,
-			ShortURL:    shortURL,
-			OriginalURL: req.OriginalURL,
-			UserUUID:    userID,
+	// Validate all items before generating IDs or touching the storage.
+	now := time.Now()
+	invalid := false
+	for i, err := range s.validateBatch(requests) {
+		if err == nil {
+			err = s.resolveExpiry(&requests[i].CreateOptions, now)
 		}
+		if err != nil {
+			results[i].Status = BatchInvalid
+			results[i].Error = err.Error()
+			invalid = true
+		}
+	}
+	if invalid && mode == BatchAtomic {
+		return abortBatch(results), ErrBatchRejected
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
 
-		// Store the URL info in the file storage or database.
-		_, err := s.Store.SaveURLRecord(urlRecord)
+	// Create the records of the valid items, pos maps a record to its item.
+	records := make([]*file.URLRecord, 0, len(requests))
+	pos := make([]int, 0, len(requests))
+	for i, req := range requests {
+		if results[i].Status == BatchInvalid {
+			continue
+		}
+		urlRecord, err := s.newURLRecord(ctx, req.OriginalURL, userID, req.CreateOptions)
 		if err != nil {
 			return nil, err
 		}
+		records = append(records, urlRecord)
+		pos = append(pos, i)
+	}
+	if len(records) == 0 {
+		return results, nil
+	}
 
-		// Add BatchRes with short URL to the results slice.
-		results = append(results, BatchRes{
-			CorrelationID: req.CorrelationID,
-			ShortURL:      shortURL,
-		})
-
-		// Update the counter.
-		Counter++
+	// Store all records in the file storage or database at once.
+	saved, err := s.saveBatch(ctx, records, mode == BatchAtomic)
+	if err != nil && !errors.Is(err, database.ErrorBatchRejected) {
+		return nil, err
+	}
+	for j, res := range saved {
+		i := pos[j]
+		switch {
+		case res.Err == nil:
+			results[i].Status = BatchCreated
+			results[i].ShortURL = res.ShortURL
+		case errors.Is(res.Err, database.ErrorDuplicate):
+			// Reuse the existing short URL if the original URL is already shortened.
+			results[i].Status = BatchDuplicate
+			results[i].ShortURL = res.ShortURL
+		case errors.Is(res.Err, database.ErrorShortURLTaken) && records[j].Alias:
+			results[i].Status = BatchInvalid
+			results[i].Error = fmt.Sprintf("%v: %q", ErrAliasTaken, requests[i].Alias)
+		default:
+			results[i].Status = BatchInvalid
+			results[i].Error = res.Err.Error()
+		}
+	}
+	if err != nil {
+		return abortBatch(results), ErrBatchRejected
 	}
 
 	return results, nil
 }
+
+// validateBatch checks the original URL, the metadata and the alias of every item
+// and makes sure the aliases are unique. The original URLs and the tags of the items are normalized in place.
+// It returns the error of every item in the order of the requests.
+func (s *ShortenerService) validateBatch(requests []BatchReq) []error {
+	errs := make([]error, len(requests))
+	seen := make(map[string]struct{})
+	for i, req := range requests {
+		originalURL, err := s.NormalizeURL(req.OriginalURL)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		requests[i].OriginalURL = originalURL
+		if err := validateMetadata(&requests[i].CreateOptions); err != nil {
+			errs[i] = err
+			continue
+		}
+		if req.Alias == "" {
+			continue
+		}
+		if err := ValidateAlias(req.Alias); err != nil {
+			errs[i] = err
+			continue
+		}
+		if _, ok := seen[req.Alias]; ok {
+			errs[i] = fmt.Errorf("%w: %q is used more than once", ErrInvalidAlias, req.Alias)
+			continue
+		}
+		seen[req.Alias] = struct{}{}
+	}
+	return errs
+}
+
+// saveBatch saves the records in a single storage operation and saves the records whose generated IDs
+// are taken by concurrent requests after they are checked again with new IDs, up to IDMaxAttempts times in total.
+// An atomic batch is saved again as a whole, since none of its records is saved when it is rejected,
+// unless an alias is taken and the batch is rejected anyway.
+// The results are in the order of the records.
+func (s *ShortenerService) saveBatch(ctx context.Context, records []*file.URLRecord, atomic bool) ([]file.SaveResult, error) {
+	saved, err := s.Store.SaveURLRecords(ctx, records, atomic)
+	for attempt := 1; attempt < s.Cfg.IDMaxAttempts; attempt++ {
+		if err != nil && !errors.Is(err, database.ErrorBatchRejected) {
+			return nil, err
+		}
+
+		// Find the records whose generated IDs are taken.
+		var retry []int
+		for i, res := range saved {
+			if !errors.Is(res.Err, database.ErrorShortURLTaken) {
+				continue
+			}
+			if records[i].Alias && atomic {
+				return saved, err
+			}
+			if !records[i].Alias {
+				retry = append(retry, i)
+			}
+		}
+		if len(retry) == 0 {
+			return saved, err
+		}
+
+		for _, i := range retry {
+			if err := s.regenerateID(ctx, records[i]); err != nil {
+				return nil, err
+			}
+		}
+		if atomic {
+			saved, err = s.Store.SaveURLRecords(ctx, records, true)
+			continue
+		}
+
+		// The other records of a best-effort batch are already saved.
+		again := make([]*file.URLRecord, 0, len(retry))
+		for _, i := range retry {
+			again = append(again, records[i])
+		}
+		var res []file.SaveResult
+		if res, err = s.Store.SaveURLRecords(ctx, again, false); err != nil {
+			return nil, err
+		}
+		for j, i := range retry {
+			saved[i] = res[j]
+		}
+	}
+	return saved, err
+}
+
+// abortBatch marks all items of the rejected batch except the invalid ones as aborted.
+func abortBatch(results []BatchRes) []BatchRes {
+	for i := range results {
+		if results[i].Status != BatchInvalid {
+			results[i].Status = BatchAborted
+			results[i].ShortURL = ""
+		}
+	}
+	return results
+}
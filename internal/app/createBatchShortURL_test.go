@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestBatchShorten(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore(config.DedupGlobal)
+	s := &ShortenerService{
+		Store: store,
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080"},
+	}
+
+	requests := func() []BatchReq {
+		return []BatchReq{
+			{CorrelationID: "1", OriginalURL: "https://a.ru"},
+			{CorrelationID: "2", OriginalURL: "https://a.ru"},
+			{CorrelationID: "3", OriginalURL: ""},
+			{CorrelationID: "4", OriginalURL: "https://b.ru", CreateOptions: CreateOptions{Alias: "b!"}},
+			{CorrelationID: "5", OriginalURL: "https://c.ru", CreateOptions: CreateOptions{Title: strings.Repeat("c", MaxTitleLength+1)}},
+		}
+	}
+
+	t.Run("atomic", func(t *testing.T) {
+		results, err := s.BatchShorten(ctx, "user1", requests(), BatchAtomic)
+		assert.ErrorIs(t, err, ErrBatchRejected)
+		require.Len(t, results, 5)
+		assert.Equal(t, BatchAborted, results[0].Status)
+		assert.Equal(t, BatchAborted, results[1].Status)
+		assert.Equal(t, BatchInvalid, results[2].Status)
+		assert.Equal(t, ErrEmptyURL.Error(), results[2].Error)
+		assert.Equal(t, BatchInvalid, results[3].Status)
+		assert.Equal(t, BatchInvalid, results[4].Status)
+
+		urls, err := store.GetURLsCount(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, urls)
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		results, err := s.BatchShorten(ctx, "user1", requests(), "")
+		require.NoError(t, err)
+		require.Len(t, results, 5)
+		assert.Equal(t, BatchCreated, results[0].Status)
+		assert.NotEmpty(t, results[0].ShortURL)
+		assert.Equal(t, BatchRes{CorrelationID: "2", ShortURL: results[0].ShortURL, Status: BatchDuplicate}, results[1])
+		assert.Equal(t, BatchInvalid, results[2].Status)
+		assert.Equal(t, BatchInvalid, results[3].Status)
+		assert.Contains(t, results[4].Error, ErrInvalidMetadata.Error())
+
+		urls, err := store.GetURLsCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, urls)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := s.BatchShorten(ctx, "user1", requests(), "partial")
+		assert.ErrorIs(t, err, ErrUnknownBatchMode)
+	})
+}
+
+func TestBatchShortenTakenID(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore(config.DedupNone)
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "http://localhost:8080/taken", OriginalURL: "https://a.ru"})
+	require.NoError(t, err)
+	requests := func() []BatchReq {
+		return []BatchReq{
+			{CorrelationID: "1", OriginalURL: "https://b.ru"},
+			{CorrelationID: "2", OriginalURL: "https://c.ru"},
+		}
+	}
+
+	for _, mode := range []BatchMode{BatchAtomic, BatchBestEffort} {
+		t.Run(string(mode), func(t *testing.T) {
+			s := &ShortenerService{
+				Store: store,
+				Cfg:   &config.Config{BaseURL: "http://localhost:8080", IDMaxAttempts: 3},
+				IDs:   &listIDs{ids: []string{"taken", string(mode) + "1", string(mode) + "2"}},
+			}
+
+			// The item whose generated ID is taken is saved with the next one.
+			results, err := s.BatchShorten(ctx, "user1", requests(), mode)
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			assert.Equal(t, BatchRes{CorrelationID: "1", ShortURL: "http://localhost:8080/" + string(mode) + "2", Status: BatchCreated}, results[0])
+			assert.Equal(t, BatchRes{CorrelationID: "2", ShortURL: "http://localhost:8080/" + string(mode) + "1", Status: BatchCreated}, results[1])
+		})
+	}
+}
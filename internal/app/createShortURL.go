@@ -3,37 +3,192 @@ package app
 import (
 	"context"
 	"errors"
-	"strconv"
+	"fmt"
+	"time"
+	"unicode/utf8"
 
 	"github.com/KirillZiborov/lnkshortener/internal/database"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/idgen"
 )
 
-// CreateShortURL reads the original URL, generates an ID, creates a record, and saves it.
+// CreateOptions holds optional parameters of a new short URL.
+type CreateOptions struct {
+	// Alias is a custom ID of the short URL chosen by the user.
+	// A random ID is generated if it is empty.
+	Alias string
+	// ExpiresAt is the time the short URL expires at.
+	ExpiresAt *time.Time
+	// TTL is the lifetime of the short URL, it is mutually exclusive with ExpiresAt.
+	// The default TTL from the configuration is used if neither is set.
+	TTL time.Duration
+	// Title is a free-form title of the short URL, at most MaxTitleLength characters.
+	Title string
+	// Notes are free-form notes on the short URL, at most MaxNotesLength characters.
+	Notes string
+	// Tags are the tags of the short URL, they are validated and normalized by NormalizeTags.
+	Tags []string
+	// RedirectCode is the HTTP status code of the redirect, see ValidateRedirectCode.
+	// The default code from the configuration is used if it is zero.
+	RedirectCode int
+	// Password protects the short URL, it is required to follow the short URL if it is not empty.
+	// Only its salted hash is stored.
+	Password string
+	// MaxClicks is the number of redirects the short URL is deactivated after, 1 for a one-time link.
+	// The short URL is not limited if it is zero.
+	MaxClicks int
+	// NotBefore and NotAfter are the activation window of the short URL, see SetURLActiveWindow.
+	// The short URL is active right away and stays active if they are nil.
+	NotBefore *time.Time
+	NotAfter  *time.Time
+}
+
+// Limits of the free-form metadata of a short URL in characters.
+const (
+	MaxTitleLength = 256
+	MaxNotesLength = 4096
+)
+
+// ErrInvalidMetadata is returned when the title or the notes of a short URL are too long.
+// The returned error wraps it together with the reason.
+var ErrInvalidMetadata = errors.New("invalid metadata")
+
+// validateMetadata checks the lengths of the title, the notes and the password, the redirect code,
+// the click limit and the activation window and normalizes the tags.
+func validateMetadata(opts *CreateOptions) error {
+	if err := validatePassword(opts.Password); err != nil {
+		return err
+	}
+	if opts.MaxClicks < 0 {
+		return ErrInvalidMaxClicks
+	}
+	if err := validateWindow(opts.NotBefore, opts.NotAfter); err != nil {
+		return err
+	}
+	if opts.RedirectCode != 0 {
+		if err := ValidateRedirectCode(opts.RedirectCode); err != nil {
+			return err
+		}
+	}
+	if utf8.RuneCountInString(opts.Title) > MaxTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrInvalidMetadata, MaxTitleLength)
+	}
+	if utf8.RuneCountInString(opts.Notes) > MaxNotesLength {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidMetadata, MaxNotesLength)
+	}
+	tags, err := NormalizeTags(opts.Tags)
+	if err != nil {
+		return err
+	}
+	opts.Tags = tags
+	return nil
+}
+
+// CreateShortURL normalizes the original URL, generates an ID, creates a record, and saves it.
 // Returns the final short URL or an error.
-func (s *ShortenerService) CreateShortURL(ctx context.Context, originalURL, userID string) (string, error) {
-	// Generate a short URL.
-	id := generateID()
-	shortenedURL := s.Cfg.BaseURL + "/" + id
+// An empty original URL results in ErrEmptyURL and an invalid one in ErrInvalidURL, see NormalizeURL.
+// If the original URL is already shortened according to the deduplication policy
+// from the configuration, it returns the existing short URL and database.ErrorDuplicate.
+// If opts has an alias, it is used as the ID instead: the record skips deduplication,
+// an invalid alias results in ErrInvalidAlias and an already used one in ErrAliasTaken.
+// An invalid expiration time or TTL results in ErrInvalidExpiry,
+// a too long title or notes in ErrInvalidMetadata, invalid tags in ErrInvalidTags
+// an unsupported redirect code in ErrInvalidRedirectCode, a too long password in ErrInvalidPassword
+// a negative click limit in ErrInvalidMaxClicks and an invalid activation window in ErrInvalidWindow.
+// A generated ID taken by a concurrent request is replaced with a new one,
+// idgen.ErrNoFreeID is returned if all IDMaxAttempts IDs are taken.
+func (s *ShortenerService) CreateShortURL(ctx context.Context, originalURL, userID string, opts CreateOptions) (string, error) {
+	originalURL, err := s.NormalizeURL(originalURL)
+	if err != nil {
+		return "", err
+	}
+	if opts.Alias != "" {
+		if err := ValidateAlias(opts.Alias); err != nil {
+			return "", err
+		}
+	}
+	if err := validateMetadata(&opts); err != nil {
+		return "", err
+	}
+	if err := s.resolveExpiry(&opts, time.Now()); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
 
 	// Create a structure with information about the URL.
-	urlRecord := &file.URLRecord{
-		UUID:        strconv.Itoa(Counter),
-		ShortURL:    shortenedURL,
-		OriginalURL: originalURL,
-		UserUUID:    userID,
+	urlRecord, err := s.newURLRecord(ctx, originalURL, userID, opts)
+	if err != nil {
+		return "", err
 	}
 
 	// Store the URL info in the file storage or database.
-	shortURL, err := s.Store.SaveURLRecord(urlRecord)
+	shortURL, err := s.Store.SaveURLRecord(ctx, urlRecord)
+	// A generated ID can be taken by a concurrent request after it is checked, save with another one then.
+	for attempt := 1; errors.Is(err, database.ErrorShortURLTaken) && !urlRecord.Alias; attempt++ {
+		if attempt >= s.Cfg.IDMaxAttempts {
+			return "", idgen.ErrNoFreeID
+		}
+		if err := s.regenerateID(ctx, urlRecord); err != nil {
+			return "", err
+		}
+		shortURL, err = s.Store.SaveURLRecord(ctx, urlRecord)
+	}
 	if errors.Is(err, database.ErrorDuplicate) {
 		return shortURL, database.ErrorDuplicate
+	} else if errors.Is(err, database.ErrorShortURLTaken) && urlRecord.Alias {
+		return "", ErrAliasTaken
 	} else if err != nil {
 		return "", err
 	}
 
-	// Update the counter
-	Counter++
+	return urlRecord.ShortURL, nil
+}
+
+// newURLRecord creates a record for the original URL with the alias from opts as its ID,
+// or with a generated ID if there is no alias. The expiry in opts must be already resolved.
+// The password in opts is hashed.
+// The record UUID is assigned by the storage.
+func (s *ShortenerService) newURLRecord(ctx context.Context, originalURL, userID string, opts CreateOptions) (*file.URLRecord, error) {
+	id := opts.Alias
+	if id == "" {
+		// Generate a short URL.
+		var err error
+		if id, err = s.nextID(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	passwordHash, err := hashPassword(opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file.URLRecord{
+		ShortURL:     s.Cfg.BaseURL + "/" + id,
+		OriginalURL:  originalURL,
+		UserUUID:     userID,
+		Alias:        opts.Alias != "",
+		ExpiresAt:    opts.ExpiresAt,
+		Title:        opts.Title,
+		Notes:        opts.Notes,
+		Tags:         opts.Tags,
+		RedirectCode: opts.RedirectCode,
+		PasswordHash: passwordHash,
+		MaxClicks:    opts.MaxClicks,
+		NotBefore:    opts.NotBefore,
+		NotAfter:     opts.NotAfter,
+	}, nil
+}
 
-	return shortenedURL, nil
+// regenerateID replaces the generated short URL of the record with a new one
+// after the storage rejects it as taken.
+func (s *ShortenerService) regenerateID(ctx context.Context, urlRecord *file.URLRecord) error {
+	id, err := s.nextID(ctx)
+	if err != nil {
+		return err
+	}
+	urlRecord.ShortURL = s.Cfg.BaseURL + "/" + id
+	return nil
 }
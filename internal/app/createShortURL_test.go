@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/idgen"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+// listIDs generates the listed IDs in order without checking whether they are taken,
+// like an ID taken by a concurrent request after the check.
+type listIDs struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (g *listIDs) NextID(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.ids) == 0 {
+		return "", idgen.ErrNoFreeID
+	}
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+	return id, nil
+}
+
+func TestCreateShortURLTakenID(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore(config.DedupNone)
+	s := &ShortenerService{
+		Store: store,
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080", IDMaxAttempts: 3},
+		IDs:   &listIDs{ids: []string{"taken", "fresh", "taken", "taken", "taken"}},
+	}
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "http://localhost:8080/taken", OriginalURL: "https://a.ru"})
+	require.NoError(t, err)
+
+	shortURL, err := s.CreateShortURL(ctx, "https://b.ru", "user1", CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/fresh", shortURL)
+
+	// The save is retried up to IDMaxAttempts times.
+	_, err = s.CreateShortURL(ctx, "https://c.ru", "user1", CreateOptions{})
+	assert.ErrorIs(t, err, idgen.ErrNoFreeID)
+}
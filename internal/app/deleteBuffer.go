@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deleteRequest is a request to delete short URLs waiting in the deleteBuffer.
+type deleteRequest struct {
+	userID    string
+	shortURLs []string
+	done      chan error // done receives the result of the flush the request is part of.
+}
+
+// deleteBuffer coalesces deletion requests of all users arriving within a short window,
+// so the storage is updated with one statement per user instead of one per request.
+// The window starts with the first buffered request, the buffer is flushed when the window
+// passes or when the number of buffered short URLs reaches the maximum batch size.
+type deleteBuffer struct {
+	store    URLStore
+	window   time.Duration
+	maxBatch int
+	timeout  time.Duration // timeout limits a single storage update.
+
+	mu      sync.Mutex
+	pending []deleteRequest
+	size    int         // size counts the short URLs of the pending requests.
+	timer   *time.Timer // timer flushes the pending requests when the window passes.
+}
+
+// newDeleteBuffer creates a deleteBuffer, it needs no closing as the pending requests
+// are always flushed within the window.
+func newDeleteBuffer(store URLStore, window time.Duration, maxBatch int, timeout time.Duration) *deleteBuffer {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+	return &deleteBuffer{store: store, window: window, maxBatch: maxBatch, timeout: timeout}
+}
+
+// Delete buffers the short URLs of the user and waits until they are deleted.
+// It matches deletion.DeleteFunc.
+//
+// Returns:
+// - The error of the storage update the request is part of, or the context error.
+// The short URLs may still be deleted after the context is done.
+func (b *deleteBuffer) Delete(ctx context.Context, userID string, shortURLs []string) error {
+	if len(shortURLs) == 0 {
+		return nil
+	}
+	req := deleteRequest{userID: userID, shortURLs: shortURLs, done: make(chan error, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, req)
+	b.size += len(shortURLs)
+	switch {
+	case b.size >= b.maxBatch || b.window <= 0:
+		batch := b.takeLocked()
+		go b.flush(batch)
+	case b.timer == nil:
+		b.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			batch := b.takeLocked()
+			b.mu.Unlock()
+			b.flush(batch)
+		})
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-req.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// takeLocked takes the pending requests and resets the window, b.mu must be held.
+func (b *deleteBuffer) takeLocked() []deleteRequest {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.pending
+	b.pending = nil
+	b.size = 0
+	return batch
+}
+
+// flush deletes the short URLs of the requests with one storage update per user
+// and passes every request the result of its user's update.
+func (b *deleteBuffer) flush(batch []deleteRequest) {
+	if len(batch) == 0 {
+		return
+	}
+
+	byUser := make(map[string][]string)
+	var users []string
+	for _, req := range batch {
+		if _, ok := byUser[req.userID]; !ok {
+			users = append(users, req.userID)
+		}
+		byUser[req.userID] = append(byUser[req.userID], req.shortURLs...)
+	}
+
+	errs := make(map[string]error, len(users))
+	for _, userID := range users {
+		ctx, cancel := withTimeout(context.Background(), b.timeout)
+		errs[userID] = b.store.BatchUpdateDeleteFlag(ctx, byUser[userID], userID)
+		cancel()
+	}
+
+	for _, req := range batch {
+		req.done <- errs[req.userID]
+	}
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+// countingStore counts batch deletions of the wrapped store.
+type countingStore struct {
+	*memory.MemoryStore
+	mu      sync.Mutex
+	batches int
+}
+
+func (s *countingStore) BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error {
+	s.mu.Lock()
+	s.batches++
+	s.mu.Unlock()
+	return s.MemoryStore.BatchUpdateDeleteFlag(ctx, urlIDs, userID)
+}
+
+func TestDeleteBuffer(t *testing.T) {
+	ctx := context.Background()
+	store := &countingStore{MemoryStore: memory.NewMemoryStore(config.DedupNone)}
+	for _, rec := range []file.URLRecord{
+		{ShortURL: "a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{ShortURL: "b", OriginalURL: "https://b.ru", UserUUID: "user1"},
+		{ShortURL: "c", OriginalURL: "https://c.ru", UserUUID: "user2"},
+	} {
+		_, err := store.SaveURLRecord(ctx, &rec)
+		require.NoError(t, err)
+	}
+
+	buffer := newDeleteBuffer(store, 50*time.Millisecond, 100, time.Second)
+
+	// Requests arriving within the window are flushed with one update per user.
+	var wg sync.WaitGroup
+	for _, req := range []struct {
+		userID   string
+		shortURL string
+	}{{"user1", "a"}, {"user1", "b"}, {"user2", "c"}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, buffer.Delete(ctx, req.userID, []string{req.shortURL}))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, store.batches)
+
+	for _, shortURL := range []string{"a", "b", "c"} {
+		rec, err := store.GetURLRecord(ctx, shortURL)
+		require.NoError(t, err)
+		assert.True(t, rec.DeletedFlag, shortURL)
+	}
+
+	// A full batch is flushed without waiting for the window.
+	buffer = newDeleteBuffer(store, time.Hour, 2, time.Second)
+	require.NoError(t, buffer.Delete(ctx, "user1", []string{"a", "b"}))
+	assert.Equal(t, 3, store.batches)
+}
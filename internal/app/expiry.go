@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// ErrInvalidExpiry is returned when the requested expiration of a short URL is invalid.
+// The returned error wraps it together with the reason.
+var ErrInvalidExpiry = errors.New("invalid expiry")
+
+// resolveExpiry sets opts.ExpiresAt from the requested expiration time or TTL,
+// or from the default TTL from the configuration if neither is requested.
+// Zero default TTL leaves the short URL without expiration.
+func (s *ShortenerService) resolveExpiry(opts *CreateOptions, now time.Time) error {
+	switch {
+	case opts.ExpiresAt != nil && opts.TTL != 0:
+		return fmt.Errorf("%w: expires_at and ttl are mutually exclusive", ErrInvalidExpiry)
+	case opts.ExpiresAt != nil:
+		if !opts.ExpiresAt.After(now) {
+			return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidExpiry)
+		}
+	case opts.TTL < 0:
+		return fmt.Errorf("%w: ttl must be positive", ErrInvalidExpiry)
+	case opts.TTL > 0:
+		expiresAt := now.Add(opts.TTL)
+		opts.ExpiresAt = &expiresAt
+	case s.Cfg.DefaultTTL > 0:
+		expiresAt := now.Add(time.Duration(s.Cfg.DefaultTTL))
+		opts.ExpiresAt = &expiresAt
+	}
+	opts.TTL = 0
+	return nil
+}
+
+// SweepExpired removes expired short URLs from the storage
+// according to the expired URLs policy from the configuration.
+//
+// Returns:
+// - A number of removed short URLs.
+// - An error if the removal fails.
+func (s *ShortenerService) SweepExpired(ctx context.Context) (int, error) {
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	return s.Store.DeleteExpired(ctx, time.Now(), s.Cfg.ExpiredPolicy)
+}
+
+// RunExpiredSweeper sweeps expired short URLs at the interval from the configuration
+// until the context is done. A non-positive interval disables sweeping.
+func (s *ShortenerService) RunExpiredSweeper(ctx context.Context) {
+	if s.Cfg.ExpiredSweepInterval <= 0 {
+		logging.Sugar.Warnw("Expired URLs sweeping is disabled", "interval", time.Duration(s.Cfg.ExpiredSweepInterval))
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.Cfg.ExpiredSweepInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.SweepExpired(ctx)
+			if err != nil {
+				logging.Sugar.Errorw("Failed to sweep expired URLs", "error", err)
+			} else if n > 0 {
+				logging.Sugar.Infow("Swept expired URLs", "count", n, "policy", s.Cfg.ExpiredPolicy)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestRunExpiredSweeperDisabled(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		s := &ShortenerService{
+			Store: memory.NewMemoryStore(config.DedupGlobal),
+			Cfg:   &config.Config{BaseURL: "http://localhost:8080", ExpiredSweepInterval: config.Duration(interval)},
+		}
+
+		// The sweeper returns at once instead of running until the context is done.
+		done := make(chan struct{})
+		go func() {
+			s.RunExpiredSweeper(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("sweeper with interval %v is running", interval)
+		}
+	}
+}
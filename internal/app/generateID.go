@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/idgen"
+)
+
+// defaultIDLength is the ID length used if the configuration does not set it.
+const defaultIDLength = 8
+
+// ErrUnknownIDGenerator is returned when the configuration selects an unknown ID generator.
+var ErrUnknownIDGenerator = errors.New("unknown ID generator")
+
+// NewIDGenerator creates the short URL ID generator selected in the configuration.
+// The generator skips IDs that are reserved or already used in the store.
+//
+// Parameters:
+// - cfg: The configuration with the generator type, ID length, salt and number of attempts.
+// - store: The storage to check IDs against and to take the sequence from, not wrapped with a cache,
+// so the probes of unused IDs neither fill the cache nor count as its misses.
+//
+// Returns:
+// - The ID generator.
+// - ErrUnknownIDGenerator if the generator type is unknown.
+func NewIDGenerator(cfg *config.Config, store URLStore) (idgen.Generator, error) {
+	length := cfg.IDLength
+	if length <= 0 {
+		length = defaultIDLength
+	}
+
+	var gen idgen.Generator
+	switch cfg.IDGenerator {
+	case config.IDRandom, "":
+		gen = idgen.NewRandom(length)
+	case config.IDSequence:
+		gen = idgen.NewSequence(store, cfg.IDSalt, length)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIDGenerator, cfg.IDGenerator)
+	}
+
+	taken := func(ctx context.Context, id string) (bool, error) {
+		if isReserved(id) {
+			return true, nil
+		}
+		_, err := store.GetURLRecord(ctx, cfg.BaseURL+"/"+id)
+		if errors.Is(err, os.ErrProcessDone) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	return idgen.NewRetry(gen, taken, cfg.IDMaxAttempts), nil
+}
+
+// nextID generates a new short URL ID.
+func (s *ShortenerService) nextID(ctx context.Context) (string, error) {
+	gen := s.IDs
+	if gen == nil {
+		store := s.Store
+		if cached, ok := store.(*CachedStore); ok {
+			store = cached.URLStore
+		}
+		var err error
+		if gen, err = NewIDGenerator(s.Cfg, store); err != nil {
+			return "", err
+		}
+	}
+	return gen.NextID(ctx)
+}
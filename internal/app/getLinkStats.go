@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+)
+
+// ErrInvalidStatsQuery is returned when the requested statistics period or bucket size is invalid.
+// The returned error wraps it together with the reason.
+var ErrInvalidStatsQuery = errors.New("invalid stats query")
+
+// StatsQuery holds the parameters of link statistics.
+type StatsQuery struct {
+	From   time.Time // From is the inclusive beginning of the period, zero means no limit.
+	To     time.Time // To is the exclusive end of the period, zero means no limit.
+	Bucket string    // Bucket is the bucket size, one of clicks.Bucket* values, "day" if empty.
+}
+
+// GetLinkStats returns click statistics of the short URL ID owned by the user.
+//
+// Returns:
+// - The total number of clicks in the period and non-empty buckets in ascending order.
+// - ErrURLNotFound if the short URL does not exist or belongs to another user,
+// ErrInvalidStatsQuery if the query is invalid, or an error if the query fails.
+func (s *ShortenerService) GetLinkStats(ctx context.Context, userID, shortID string, q StatsQuery) (clicks.Stats, error) {
+	if q.Bucket == "" {
+		q.Bucket = clicks.BucketDay
+	}
+	if q.Bucket != clicks.BucketDay && q.Bucket != clicks.BucketHour {
+		return clicks.Stats{}, fmt.Errorf("%w: bucket must be %q or %q", ErrInvalidStatsQuery, clicks.BucketHour, clicks.BucketDay)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return clicks.Stats{}, fmt.Errorf("%w: from must be before to", ErrInvalidStatsQuery)
+	}
+
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+
+	// Check the owner of the short URL.
+	shortURL := s.Cfg.BaseURL + "/" + shortID
+	rec, err := s.Store.GetURLRecord(ctx, shortURL)
+	if errors.Is(err, os.ErrProcessDone) {
+		return clicks.Stats{}, ErrURLNotFound
+	} else if err != nil {
+		return clicks.Stats{}, err
+	}
+	// Do not reveal that other users' short URLs exist.
+	if rec.UserUUID != userID {
+		return clicks.Stats{}, ErrURLNotFound
+	}
+
+	return s.Store.GetClickStats(ctx, clicks.Query{
+		ShortURL: shortURL,
+		From:     q.From,
+		To:       q.To,
+		Bucket:   q.Bucket,
+	})
+}
@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/database"
 )
 
 var (
@@ -12,26 +17,180 @@ var (
 	ErrURLNotFound = errors.New("url not found")
 	// ErrURLDeleted is returned when attempting to get a URL which is marked as deleted.
 	ErrURLDeleted = errors.New("url deleted")
+	// ErrURLExpired is returned when attempting to get a URL which has expired
+	// but is not swept from the storage yet.
+	ErrURLExpired = errors.New("url expired")
+	// ErrURLNotYetActive is returned when attempting to get a URL before the start of its activation window.
+	ErrURLNotYetActive = errors.New("url is not active yet")
+	// ErrURLNoLongerActive is returned when attempting to get a URL after the end of its activation window.
+	ErrURLNoLongerActive = errors.New("url is no longer active")
+	// ErrURLExhausted is returned when attempting to get a URL which has reached its click limit.
+	ErrURLExhausted = errors.New("url click limit reached")
+	// ErrInvalidRedirectCode is returned when a redirect status code is not one of the supported ones.
+	ErrInvalidRedirectCode = errors.New("invalid redirect code: must be 301, 302, 307 or 308")
+	// ErrInvalidMaxClicks is returned when the click limit of a new short URL is negative.
+	ErrInvalidMaxClicks = errors.New("invalid max clicks: must not be negative")
 )
 
-// GetShortURL finds the corresponding original URL by its shortened version.
-func (s *ShortenerService) GetShortURL(ctx context.Context, shortID string) (originalURL string, err error) {
+// ValidateRedirectCode checks that the code is one of the supported redirect status codes:
+// 301 Moved Permanently, 302 Found, 307 Temporary Redirect or 308 Permanent Redirect.
+//
+// Returns:
+// - ErrInvalidRedirectCode if the code is not supported.
+func ValidateRedirectCode(code int) error {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return nil
+	}
+	return ErrInvalidRedirectCode
+}
+
+// RedirectRequest holds the details of a request following a short URL.
+type RedirectRequest struct {
+	// Password is the password of a protected short URL, it is ignored if the short URL is not protected.
+	Password string
+	// Peek resolves the redirect without counting it against the click limit of the short URL.
+	// The original URL of a click-limited short URL is not revealed then.
+	Peek bool
+	// UserAgent, AcceptLanguage and Query are the User-Agent and Accept-Language headers and the query parameters
+	// of the request, the redirect rules of the short URL are matched on them.
+	UserAgent      string
+	AcceptLanguage string
+	Query          url.Values
+}
+
+// Redirect describes how a client is redirected from a short URL to its original URL.
+type Redirect struct {
+	// URL is the original URL or the target of the matching redirect rule, empty if a click-limited short URL is peeked.
+	URL string
+	// Code is the HTTP status code of the redirect.
+	Code int
+	// MaxAge is how long clients may cache the redirect, zero if it must not be cached.
+	MaxAge time.Duration
+}
+
+// GetRedirect finds the original URL by the short URL ID together with the redirect status code:
+// the code of the short URL or the default one from the configuration.
+// Permanent redirects may be cached by clients for the time from the configuration, temporary ones may not.
+// A password-protected short URL is only followed with the correct password, wrong passwords are limited
+// by the attempt limiter of the service. Every redirect of a click-limited short URL is counted atomically,
+// so the limit is never exceeded. A short URL with an activation window only redirects within the window.
+// The target is selected by the first redirect rule of the short URL the request matches, see SetURLRules,
+// or is the original URL. Redirects of protected, click-limited and rule-based short URLs are never cached,
+// and cached redirects of other short URLs do not outlive the activation window.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortID: The ID of the short URL.
+// - req: The details of the request.
+//
+// Returns:
+// - The redirect, it has the original URL even if the short URL is deleted.
+// - ErrURLNotFound, ErrURLDeleted for deleted URLs, ErrURLExpired for expired ones,
+// ErrURLNotYetActive and ErrURLNoLongerActive for the ones outside their activation window,
+// ErrURLExhausted for the ones which reached their click limit,
+// ErrPasswordRequired, ErrWrongPassword or ErrTooManyAttempts for protected ones,
+// or an error if the query fails.
+func (s *ShortenerService) GetRedirect(ctx context.Context, shortID string, req RedirectRequest) (Redirect, error) {
 	// Prepend the base URL to ID to form the complete short URL.
 	shortURL := fmt.Sprintf("%s/%s", s.Cfg.BaseURL, shortID)
 
 	// Get the original URL by the short URL.
-	orig, deleted, e := s.Store.GetOriginalURL(shortURL)
-	if e != nil {
+	readCtx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+	rec, err := s.Store.GetURLRecord(readCtx, shortURL)
+	if err != nil {
 		// Get os.ErrProcessDone if the storage is fully checked but URL is not found.
-		if errors.Is(e, os.ErrProcessDone) {
-			return "", ErrURLNotFound
+		if errors.Is(err, os.ErrProcessDone) {
+			return Redirect{}, ErrURLNotFound
 		}
-		return "", e
+		return Redirect{}, err
 	}
 
 	// Check if the URL is deleted.
-	if deleted {
-		return orig, ErrURLDeleted
+	if rec.DeletedFlag {
+		return Redirect{URL: rec.OriginalURL}, ErrURLDeleted
+	}
+	// Check if the URL has expired.
+	now := time.Now()
+	if rec.IsExpired(now) {
+		return Redirect{}, ErrURLExpired
+	}
+	// Check if the URL is within its activation window.
+	if rec.IsNoLongerActive(now) {
+		return Redirect{}, ErrURLNoLongerActive
+	}
+	if rec.IsNotYetActive(now) {
+		return Redirect{}, ErrURLNotYetActive
+	}
+	// Check if the URL has reached its click limit.
+	if rec.MaxClicks > 0 && rec.UsedClicks >= rec.MaxClicks {
+		return Redirect{}, ErrURLExhausted
+	}
+
+	redirect := Redirect{URL: rec.OriginalURL, Code: s.redirectCode(rec.RedirectCode)}
+	if rec.PasswordHash != "" {
+		if err := s.checkPassword(shortURL, rec.PasswordHash, req.Password); err != nil {
+			return Redirect{}, err
+		}
+	}
+	if target := matchRule(rec.Rules, req); target != "" {
+		redirect.URL = target
+	}
+	switch {
+	case rec.MaxClicks > 0 && req.Peek:
+		redirect.URL = ""
+	case rec.MaxClicks > 0:
+		if err := s.useClick(ctx, shortURL); err != nil {
+			return Redirect{}, err
+		}
+	case rec.PasswordHash != "":
+		// The password is checked on every redirect, so it is never cached.
+	case len(rec.Rules) > 0:
+		// The target depends on the visitor, so it is never cached.
+	case redirect.Code == http.StatusMovedPermanently || redirect.Code == http.StatusPermanentRedirect:
+		redirect.MaxAge = time.Duration(s.Cfg.PermanentRedirectMaxAge)
+		if rec.NotAfter != nil {
+			redirect.MaxAge = min(redirect.MaxAge, rec.NotAfter.Sub(now))
+		}
+	}
+	return redirect, nil
+}
+
+// GetShortURL finds the corresponding original URL by its shortened version.
+// It returns ErrURLDeleted for deleted URLs, ErrURLExpired for expired ones,
+// ErrURLNotYetActive and ErrURLNoLongerActive for the ones outside their activation window
+// and ErrPasswordRequired for password-protected ones. A redirect of a click-limited one is counted.
+func (s *ShortenerService) GetShortURL(ctx context.Context, shortID string) (originalURL string, err error) {
+	redirect, err := s.GetRedirect(ctx, shortID, RedirectRequest{})
+	return redirect.URL, err
+}
+
+// useClick counts a redirect of the click-limited short URL in the storage.
+// A redirect made concurrently may have reached the limit after the record was read.
+func (s *ShortenerService) useClick(ctx context.Context, shortURL string) error {
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	err := s.Store.UseClick(ctx, shortURL)
+	switch {
+	case errors.Is(err, database.ErrorExhausted):
+		return ErrURLExhausted
+	case errors.Is(err, os.ErrProcessDone):
+		return ErrURLNotFound
+	}
+	return err
+}
+
+// redirectCode returns the redirect status code of a short URL with the given code,
+// the default one from the configuration if it is zero, or 307 if neither is set.
+func (s *ShortenerService) redirectCode(code int) int {
+	switch {
+	case code != 0:
+		return code
+	case s.Cfg.RedirectCode != 0:
+		return s.Cfg.RedirectCode
+	default:
+		return http.StatusTemporaryRedirect
 	}
-	return orig, nil
 }
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestClickLimit(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080"},
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "neg", MaxClicks: -1})
+	assert.ErrorIs(t, err, ErrInvalidMaxClicks)
+
+	_, err = s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "three", MaxClicks: 3})
+	require.NoError(t, err)
+
+	// Peeking neither consumes a click nor reveals the original URL.
+	redirect, err := s.GetRedirect(ctx, "three", RedirectRequest{Peek: true})
+	require.NoError(t, err)
+	assert.Empty(t, redirect.URL)
+
+	// Concurrent redirects never exceed the limit.
+	var ok atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			redirect, err := s.GetRedirect(ctx, "three", RedirectRequest{})
+			if err == nil {
+				assert.Equal(t, "https://a.ru", redirect.URL)
+				ok.Add(1)
+				return
+			}
+			assert.ErrorIs(t, err, ErrURLExhausted)
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 3, ok.Load())
+
+	_, err = s.GetRedirect(ctx, "three", RedirectRequest{Peek: true})
+	assert.ErrorIs(t, err, ErrURLExhausted)
+
+	rec, err := s.Store.GetURLRecord(ctx, "http://localhost:8080/three")
+	require.NoError(t, err)
+	assert.Equal(t, 3, rec.UsedClicks)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"net"
 	"strings"
@@ -12,18 +13,34 @@ import (
 // - A number of shortened URLs.
 // - A number of unique users.
 // - An error if the query fails.
-func (s *ShortenerService) GetStats() (int, int, error) {
-	urlsCount, err := s.Store.GetURLsCount()
+func (s *ShortenerService) GetStats(ctx context.Context) (int, int, error) {
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+
+	urlsCount, err := s.Store.GetURLsCount(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
-	usersCount, err := s.Store.GetUsersCount()
+	usersCount, err := s.Store.GetUsersCount(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
 	return urlsCount, usersCount, nil
 }
 
+// GetCacheStats returns the counters of the redirect cache.
+//
+// Returns:
+// - The cache counters.
+// - False if the storage is not cached.
+func (s *ShortenerService) GetCacheStats() (CacheStats, bool) {
+	cached, ok := s.Store.(*CachedStore)
+	if !ok {
+		return CacheStats{}, false
+	}
+	return cached.Stats(), true
+}
+
 // ErrNoTrustedSubnet is returned when there is no trusted subnet specified.
 var ErrNoTrustedSubnet = errors.New("no trusted subnet specified")
 
@@ -2,17 +2,136 @@ package app
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/KirillZiborov/lnkshortener/internal/file"
 )
 
-// GetUserURLs returns all non-deleted short URLs created by user.
-func (s *ShortenerService) GetUserURLs(ctx context.Context, userID string) ([]file.URLRecord, error) {
+// ErrInvalidURLsQuery is returned when the requested page, order or filters of the user's URLs are invalid.
+// The returned error wraps it together with the reason.
+var ErrInvalidURLsQuery = errors.New("invalid URLs query")
+
+// MaxURLsLimit is the maximum number of the user's URLs in a page.
+const MaxURLsLimit = 1000
+
+// Orders of the user's URLs.
+const (
+	SortCreatedAsc  = "created_asc"  // SortCreatedAsc orders the URLs from the oldest to the newest.
+	SortCreatedDesc = "created_desc" // SortCreatedDesc orders the URLs from the newest to the oldest.
+)
+
+// URLsQuery holds the parameters of a page of the user's URLs.
+type URLsQuery struct {
+	Limit       int            // Limit is the maximum number of URLs in the page, 0 means all URLs.
+	Cursor      string         // Cursor is the opaque position of the page returned with the previous page, empty for the first page.
+	Sort        string         // Sort is the order of the URLs, one of Sort* values, SortCreatedAsc if empty.
+	Status      file.URLStatus // Status selects the URLs by their deletion status, all URLs if empty.
+	Contains    string         // Contains selects the URLs with the original URL containing it, if not empty.
+	CreatedFrom time.Time      // CreatedFrom is the inclusive beginning of the creation period, zero means no limit.
+	CreatedTo   time.Time      // CreatedTo is the exclusive end of the creation period, zero means no limit.
+	Tag         string         // Tag selects the URLs tagged with it, if not empty.
+}
+
+// URLsPage is a page of the user's URLs.
+type URLsPage struct {
+	Records    []file.URLRecord // Records are the URLs of the page.
+	Total      int              // Total is the number of URLs matching the filters on all pages.
+	NextCursor string           // NextCursor is the cursor of the next page, empty if it is the last page.
+}
+
+// GetUserURLs returns a page of the short URLs created by user which match the filters of the query.
+// The cursor of the next page must be used with the same order and filters.
+//
+// Returns:
+// - The page of the user's URLs with the total number of matching URLs.
+// - ErrInvalidURLsQuery if the query is invalid, or an error if the storage fails.
+func (s *ShortenerService) GetUserURLs(ctx context.Context, userID string, q URLsQuery) (URLsPage, error) {
+	storeQuery, err := q.storeQuery()
+	if err != nil {
+		return URLsPage{}, err
+	}
+
 	// Retrieve the user's URLs from the storage.
-	records, err := s.Store.GetUserURLs(userID)
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+	page, err := s.Store.GetUserURLs(ctx, userID, storeQuery)
 	if err != nil {
-		return nil, err
+		return URLsPage{}, err
+	}
+
+	result := URLsPage{Records: page.Records, Total: page.Total}
+	if page.Next != nil {
+		result.NextCursor = encodeCursor(*page.Next)
 	}
+	return result, nil
+}
+
+// storeQuery validates the query and converts it to the storage query.
+func (q URLsQuery) storeQuery() (file.URLQuery, error) {
+	if q.Limit < 0 || q.Limit > MaxURLsLimit {
+		return file.URLQuery{}, fmt.Errorf("%w: limit must be from 0 to %d", ErrInvalidURLsQuery, MaxURLsLimit)
+	}
+	storeQuery := file.URLQuery{Limit: q.Limit, Status: q.Status, Contains: q.Contains}
 
-	return records, nil
+	switch q.Sort {
+	case "", SortCreatedAsc:
+	case SortCreatedDesc:
+		storeQuery.Desc = true
+	default:
+		return file.URLQuery{}, fmt.Errorf("%w: sort must be %q or %q", ErrInvalidURLsQuery, SortCreatedAsc, SortCreatedDesc)
+	}
+
+	switch q.Status {
+	case file.URLStatusAll, file.URLStatusActive, file.URLStatusDeleted:
+	default:
+		return file.URLQuery{}, fmt.Errorf("%w: status must be %q or %q", ErrInvalidURLsQuery, file.URLStatusActive, file.URLStatusDeleted)
+	}
+
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && !q.CreatedFrom.Before(q.CreatedTo) {
+		return file.URLQuery{}, fmt.Errorf("%w: created_from must be before created_to", ErrInvalidURLsQuery)
+	}
+	if !q.CreatedFrom.IsZero() {
+		storeQuery.CreatedFrom = &q.CreatedFrom
+	}
+	if !q.CreatedTo.IsZero() {
+		storeQuery.CreatedTo = &q.CreatedTo
+	}
+
+	if q.Tag != "" {
+		tag, err := normalizeTag(q.Tag)
+		if err != nil {
+			return file.URLQuery{}, fmt.Errorf("%w: %v", ErrInvalidURLsQuery, err)
+		}
+		storeQuery.Tag = tag
+	}
+
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return file.URLQuery{}, fmt.Errorf("%w: malformed cursor", ErrInvalidURLsQuery)
+		}
+		storeQuery.After = &after
+	}
+	return storeQuery, nil
+}
+
+// encodeCursor makes an opaque cursor of the position.
+func encodeCursor(pos file.URLPosition) string {
+	data, _ := json.Marshal(pos)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor returns the position of the cursor made by encodeCursor.
+func decodeCursor(cursor string) (file.URLPosition, error) {
+	var pos file.URLPosition
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pos, err
+	}
+	err = json.Unmarshal(data, &pos)
+	return pos, err
 }
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestGetUserURLs(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080"},
+	}
+	for _, alias := range []string{"abc", "def", "ghi"} {
+		_, err := s.CreateShortURL(ctx, "https://"+alias+".ru", "user1", CreateOptions{Alias: alias})
+		require.NoError(t, err)
+	}
+
+	page, err := s.GetUserURLs(ctx, "user1", URLsQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Records, 2)
+	assert.Equal(t, "http://localhost:8080/abc", page.Records[0].ShortURL)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = s.GetUserURLs(ctx, "user1", URLsQuery{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, "http://localhost:8080/ghi", page.Records[0].ShortURL)
+	assert.Empty(t, page.NextCursor)
+
+	for name, q := range map[string]URLsQuery{
+		"limit":  {Limit: MaxURLsLimit + 1},
+		"cursor": {Cursor: "not a cursor"},
+		"sort":   {Sort: "alias"},
+		"status": {Status: "expired"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.GetUserURLs(ctx, "user1", q)
+			assert.ErrorIs(t, err, ErrInvalidURLsQuery)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidURL is returned when an original URL cannot be parsed or breaks the validation rules.
+// The returned error wraps it together with the reason.
+var ErrInvalidURL = errors.New("invalid URL")
+
+// defaultSchemes are the schemes allowed if the configuration does not list any.
+var defaultSchemes = []string{"http", "https"}
+
+// defaultPorts holds the ports dropped from the host of URLs with the corresponding scheme.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ftp":   "21",
+}
+
+// hostProfile converts internationalized host names to punycode.
+// Unlike idna.Lookup it accepts underscores, which are used in real host names.
+var hostProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// NormalizeURL validates an original URL and converts it to the canonical form the short URL redirects to:
+// the scheme and the host are lowercased, an internationalized host is converted to punycode
+// and the default port of the scheme is removed. The rest of the URL is only escaped where required.
+// The URL must be absolute, have one of the schemes allowed by the configuration, http and https by default,
+// have a host unless its scheme has none, like mailto, and fit into the maximum length from the configuration.
+//
+// Parameters:
+// - rawURL: The original URL, surrounding white space is ignored.
+//
+// Returns:
+// - The normalized URL.
+// - ErrEmptyURL if the URL is empty, or an error wrapping ErrInvalidURL with the reason.
+func (s *ShortenerService) NormalizeURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", ErrEmptyURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: cannot be parsed", ErrInvalidURL)
+	}
+	if u.Scheme == "" {
+		return "", fmt.Errorf("%w: must be absolute with a scheme like \"https://\"", ErrInvalidURL)
+	}
+	// The scheme is already lowercased by url.Parse.
+	if !slices.Contains(s.allowedSchemes(), u.Scheme) {
+		return "", fmt.Errorf("%w: scheme %q is not allowed", ErrInvalidURL, u.Scheme)
+	}
+
+	// Schemes with a default port always have a host, others like mailto may have none.
+	_, needsHost := defaultPorts[u.Scheme]
+	switch {
+	case u.Opaque != "" && !needsHost:
+	case u.Host == "":
+		return "", fmt.Errorf("%w: must have a host", ErrInvalidURL)
+	default:
+		if u.Host, err = normalizeHost(u.Scheme, u.Hostname(), u.Port()); err != nil {
+			return "", err
+		}
+	}
+
+	normalized := u.String()
+	if maxLength := s.Cfg.MaxURLLength; maxLength > 0 && len(normalized) > maxLength {
+		return "", fmt.Errorf("%w: must be at most %d bytes long", ErrInvalidURL, maxLength)
+	}
+	return normalized, nil
+}
+
+// normalizeHost lowercases the host name, converts it to punycode
+// and joins it with the port unless the port is the default one of the scheme.
+func normalizeHost(scheme, host, port string) (string, error) {
+	if host == "" {
+		return "", fmt.Errorf("%w: must have a host", ErrInvalidURL)
+	}
+	if strings.Contains(host, ":") {
+		// IPv6 literals are only lowercased.
+		host = strings.ToLower(host)
+	} else {
+		ascii, err := hostProfile.ToASCII(host)
+		if err != nil {
+			return "", fmt.Errorf("%w: host %q is not a valid domain name", ErrInvalidURL, host)
+		}
+		host = ascii
+	}
+
+	if port != "" && port != defaultPorts[scheme] {
+		return net.JoinHostPort(host, port), nil
+	}
+	if strings.Contains(host, ":") {
+		return "[" + host + "]", nil
+	}
+	return host, nil
+}
+
+// allowedSchemes returns the lowercased schemes from the configuration or the default ones.
+func (s *ShortenerService) allowedSchemes() []string {
+	var schemes []string
+	for _, scheme := range strings.Split(s.Cfg.AllowedSchemes, ",") {
+		if scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme != "" {
+			schemes = append(schemes, scheme)
+		}
+	}
+	if len(schemes) == 0 {
+		return defaultSchemes
+	}
+	return schemes
+}
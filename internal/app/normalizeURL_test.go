@@ -0,0 +1,58 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	s := &ShortenerService{Cfg: &config.Config{MaxURLLength: 64}}
+
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr error
+	}{
+		{name: "unchanged", url: "https://example.com/a?b=c#d", want: "https://example.com/a?b=c#d"},
+		{name: "case of scheme and host", url: " HTTPS://Example.COM/Path ", want: "https://example.com/Path"},
+		{name: "default port", url: "http://example.com:80/", want: "http://example.com/"},
+		{name: "other port", url: "https://example.com:8443/", want: "https://example.com:8443/"},
+		{name: "IDN", url: "https://пример.рф/путь", want: "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C"},
+		{name: "IPv6", url: "http://[2001:DB8::1]:80/", want: "http://[2001:db8::1]/"},
+		{name: "empty", url: "  ", wantErr: ErrEmptyURL},
+		{name: "relative", url: "/path", wantErr: ErrInvalidURL},
+		{name: "no host", url: "https:///path", wantErr: ErrInvalidURL},
+		{name: "opaque http", url: "http:example.com", wantErr: ErrInvalidURL},
+		{name: "javascript", url: "javascript:alert(1)", wantErr: ErrInvalidURL},
+		{name: "garbage", url: "http://exa mple.com", wantErr: ErrInvalidURL},
+		{name: "too long", url: "https://example.com/" + strings.Repeat("a", 64), wantErr: ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.NormalizeURL(tt.url)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("allowed schemes", func(t *testing.T) {
+		s := &ShortenerService{Cfg: &config.Config{AllowedSchemes: "HTTPS, mailto"}}
+
+		_, err := s.NormalizeURL("http://example.com")
+		assert.ErrorIs(t, err, ErrInvalidURL)
+
+		got, err := s.NormalizeURL("mailto:user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "mailto:user@example.com", got)
+	})
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxPasswordLength is the maximum length of a link password in bytes, bcrypt ignores the rest.
+const maxPasswordLength = 72
+
+var (
+	// ErrInvalidPassword is returned when the password of a new short URL breaks the validation rules.
+	// The returned error wraps it together with the reason.
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrPasswordRequired is returned when a password-protected short URL is followed without a password.
+	ErrPasswordRequired = errors.New("password required")
+	// ErrWrongPassword is returned when a password-protected short URL is followed with a wrong password.
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrTooManyAttempts is returned when a password-protected short URL is locked
+	// after too many wrong passwords.
+	ErrTooManyAttempts = errors.New("too many wrong passwords, try again later")
+)
+
+// validatePassword checks the length of the password of a new short URL, an empty password means none.
+func validatePassword(password string) error {
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidPassword, maxPasswordLength)
+	}
+	return nil
+}
+
+// hashPassword returns the salted bcrypt hash of the password, or an empty string for no password.
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// AttemptLimiter limits wrong passwords of every password-protected short URL.
+// After the maximum number of wrong passwords within the window the short URL is locked
+// until the window started by the first of them passes. The limits are kept in memory of the replica.
+type AttemptLimiter struct {
+	max    int
+	window time.Duration
+
+	mu        sync.Mutex
+	attempts  map[string]*attemptWindow
+	lastSweep time.Time
+}
+
+// attemptWindow counts wrong passwords of a short URL since the first of them.
+type attemptWindow struct {
+	start time.Time
+	count int
+}
+
+// NewAttemptLimiter creates a limiter of wrong passwords.
+//
+// Parameters:
+// - maxAttempts: The number of wrong passwords a short URL is locked after.
+// - window: The time wrong passwords are counted within.
+//
+// Returns:
+// - A pointer to an AttemptLimiter instance.
+func NewAttemptLimiter(maxAttempts int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		max:      maxAttempts,
+		window:   window,
+		attempts: make(map[string]*attemptWindow),
+	}
+}
+
+// Reserve counts a password check for the short URL at the given time as a wrong password
+// before the password is compared, so concurrent checks cannot exceed the limit.
+// It reports false if the short URL is locked, the attempt is not counted then.
+// The returned refund function takes back the reserved attempt and is called after a correct password,
+// the wrong passwords of other checks within the window stay counted.
+func (l *AttemptLimiter) Reserve(shortURL string, now time.Time) (refund func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget the passed windows once per window, so the limiter does not grow without bound.
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, w := range l.attempts {
+			if now.Sub(w.start) >= l.window {
+				delete(l.attempts, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.attempts[shortURL]
+	switch {
+	case !ok || now.Sub(w.start) >= l.window:
+		w = &attemptWindow{start: now, count: 1}
+		l.attempts[shortURL] = w
+	case w.count < l.max:
+		w.count++
+	default:
+		return nil, false
+	}
+	return func() { l.refund(shortURL, w) }, true
+}
+
+// refund takes back an attempt reserved in the window of the short URL.
+// Nothing is taken back if the window has been replaced by a new one since.
+func (l *AttemptLimiter) refund(shortURL string, w *attemptWindow) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.attempts[shortURL] == w && w.count > 0 {
+		w.count--
+	}
+}
+
+// checkPassword checks the password of a protected short URL against its hash,
+// counting wrong passwords with the limiter of the service if it is set.
+// The attempt is reserved before the comparison and refunded if the password is correct.
+func (s *ShortenerService) checkPassword(shortURL, hash, password string) error {
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	refund := func() {}
+	if s.Attempts != nil {
+		var ok bool
+		if refund, ok = s.Attempts.Reserve(shortURL, time.Now()); !ok {
+			return ErrTooManyAttempts
+		}
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return ErrWrongPassword
+	}
+	refund()
+	return nil
+}
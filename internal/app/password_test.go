@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestProtectedRedirect(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store:    memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:      &config.Config{BaseURL: "http://localhost:8080", PermanentRedirectMaxAge: config.Duration(time.Hour)},
+		Attempts: NewAttemptLimiter(2, time.Minute),
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{
+		Alias:        "abc",
+		Password:     "secret",
+		RedirectCode: http.StatusPermanentRedirect,
+	})
+	require.NoError(t, err)
+
+	rec, err := s.Store.GetURLRecord(ctx, "http://localhost:8080/abc")
+	require.NoError(t, err)
+	assert.NotContains(t, rec.PasswordHash, "secret")
+
+	_, err = s.GetRedirect(ctx, "abc", RedirectRequest{})
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+
+	redirect, err := s.GetRedirect(ctx, "abc", RedirectRequest{Password: "secret"})
+	require.NoError(t, err)
+	// Protected redirects are not cached even if they are permanent.
+	assert.Equal(t, Redirect{URL: "https://a.ru", Code: http.StatusPermanentRedirect}, redirect)
+
+	_, err = s.GetRedirect(ctx, "abc", RedirectRequest{Password: "wrong"})
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	_, err = s.GetRedirect(ctx, "abc", RedirectRequest{Password: "wrong"})
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	// The short URL is locked even for the correct password.
+	_, err = s.GetRedirect(ctx, "abc", RedirectRequest{Password: "secret"})
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+}
+
+func TestAttemptLimiter(t *testing.T) {
+	l := NewAttemptLimiter(2, time.Minute)
+	now := time.Now()
+
+	_, ok := l.Reserve("a", now)
+	assert.True(t, ok)
+	_, ok = l.Reserve("a", now.Add(time.Second))
+	assert.True(t, ok)
+	_, ok = l.Reserve("a", now.Add(time.Second))
+	assert.False(t, ok)
+	_, ok = l.Reserve("b", now.Add(time.Second))
+	assert.True(t, ok)
+
+	// The lock is lifted once the window of the first wrong password passes.
+	_, ok = l.Reserve("a", now.Add(time.Minute))
+	assert.True(t, ok)
+
+	// A refund after a correct password takes back only its own attempt,
+	// the wrong password of another check stays counted.
+	refund, ok := l.Reserve("b", now.Add(time.Second))
+	require.True(t, ok)
+	refund()
+	_, ok = l.Reserve("b", now.Add(time.Second))
+	assert.True(t, ok)
+	_, ok = l.Reserve("b", now.Add(time.Second))
+	assert.False(t, ok)
+
+	// A refund does not touch the window started after the reservation.
+	refund, ok = l.Reserve("c", now)
+	require.True(t, ok)
+	_, ok = l.Reserve("c", now.Add(time.Minute))
+	require.True(t, ok)
+	_, ok = l.Reserve("c", now.Add(time.Minute))
+	require.True(t, ok)
+	refund()
+	_, ok = l.Reserve("c", now.Add(time.Minute))
+	assert.False(t, ok)
+}
+
+func TestConcurrentWrongPasswords(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store:    memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:      &config.Config{BaseURL: "http://localhost:8080"},
+		Attempts: NewAttemptLimiter(3, time.Minute),
+	}
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "abc", Password: "secret"})
+	require.NoError(t, err)
+
+	// Parallel guesses never get more password checks than the limit.
+	var wrong, locked atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.GetRedirect(ctx, "abc", RedirectRequest{Password: "wrong"})
+			switch {
+			case errors.Is(err, ErrWrongPassword):
+				wrong.Add(1)
+			case errors.Is(err, ErrTooManyAttempts):
+				locked.Add(1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.EqualValues(t, 3, wrong.Load())
+	assert.EqualValues(t, 17, locked.Load())
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+)
+
+// RecordClick queues a click event for the short URL ID without waiting for the storage.
+// The client IP address is anonymized before it is stored.
+// Nothing is recorded if click tracking is disabled.
+func (s *ShortenerService) RecordClick(shortID, referrer, userAgent, clientIP string) {
+	if s.Clicks == nil {
+		return
+	}
+
+	s.Clicks.Record(clicks.Click{
+		ShortURL:  s.Cfg.BaseURL + "/" + shortID,
+		Time:      time.Now().UTC(),
+		Referrer:  referrer,
+		UserAgent: userAgent,
+		IP:        clicks.AnonymizeIP(clientIP),
+	})
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// RestoreURLs restores deleted short URL IDs of the user deleted within the retention from the configuration.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - userID: The user ID the short URLs belong to.
+// - ids: The IDs of the short URLs to be restored.
+//
+// Returns:
+// - The IDs of the restored short URLs in the order of the storage, unknown, foreign, not deleted
+// and purged or deleted too long ago IDs are not restored.
+// - An error if the storage fails.
+func (s *ShortenerService) RestoreURLs(ctx context.Context, userID string, ids []string) ([]string, error) {
+	// Prepend the base URL to each ID to form the complete short URLs.
+	shortURLs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		shortURLs = append(shortURLs, s.Cfg.BaseURL+"/"+id)
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	deletedSince := time.Now().Add(-time.Duration(s.Cfg.DeleteRetention))
+	restored, err := s.Store.RestoreURLs(ctx, shortURLs, userID, deletedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	restoredIDs := make([]string, 0, len(restored))
+	for _, shortURL := range restored {
+		restoredIDs = append(restoredIDs, strings.TrimPrefix(shortURL, s.Cfg.BaseURL+"/"))
+	}
+	return restoredIDs, nil
+}
+
+// PurgeDeleted removes short URLs deleted longer ago than the retention from the configuration
+// together with their analytics.
+//
+// Returns:
+// - A number of removed short URLs.
+// - An error if the removal fails.
+func (s *ShortenerService) PurgeDeleted(ctx context.Context) (int, error) {
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	return s.Store.PurgeDeleted(ctx, time.Now().Add(-time.Duration(s.Cfg.DeleteRetention)))
+}
+
+// RunDeletedPurger purges deleted short URLs past the retention at the interval from the configuration
+// until the context is done. A non-positive interval disables purging.
+func (s *ShortenerService) RunDeletedPurger(ctx context.Context) {
+	if s.Cfg.DeletePurgeInterval <= 0 {
+		logging.Sugar.Warnw("Deleted URLs purging is disabled", "interval", time.Duration(s.Cfg.DeletePurgeInterval))
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.Cfg.DeletePurgeInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.PurgeDeleted(ctx)
+			if err != nil {
+				logging.Sugar.Errorw("Failed to purge deleted URLs", "error", err)
+			} else if n > 0 {
+				logging.Sugar.Infow("Purged deleted URLs", "count", n, "retention", time.Duration(s.Cfg.DeleteRetention))
+			}
+		}
+	}
+}
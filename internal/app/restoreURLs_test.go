@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestRestoreURLs(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: NewCachedStore(memory.NewMemoryStore(config.DedupGlobal), 10, time.Minute, time.Minute, 0),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080", DeleteRetention: config.Duration(time.Hour)},
+	}
+
+	for _, alias := range []string{"abc", "def"} {
+		_, err := s.CreateShortURL(ctx, "https://"+alias+".ru", "user1", CreateOptions{Alias: alias})
+		require.NoError(t, err)
+	}
+	require.NoError(t, s.Store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/abc", "http://localhost:8080/def"}, "user1"))
+	_, err := s.GetShortURL(ctx, "abc")
+	assert.ErrorIs(t, err, ErrURLDeleted)
+
+	restored, err := s.RestoreURLs(ctx, "user2", []string{"abc"})
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+
+	restored, err = s.RestoreURLs(ctx, "user1", []string{"abc", "xyz"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc"}, restored)
+
+	// The cached redirect follows the restoration.
+	originalURL, err := s.GetShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://abc.ru", originalURL)
+
+	// Nothing is deleted longer than the retention ago yet.
+	n, err := s.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	s.Cfg.DeleteRetention = 0
+	n, err = s.PurgeDeleted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = s.GetShortURL(ctx, "def")
+	assert.ErrorIs(t, err, ErrURLNotFound)
+}
+
+func TestRunDeletedPurgerDisabled(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		s := &ShortenerService{
+			Store: memory.NewMemoryStore(config.DedupGlobal),
+			Cfg:   &config.Config{BaseURL: "http://localhost:8080", DeletePurgeInterval: config.Duration(interval)},
+		}
+
+		// The purger returns at once instead of running until the context is done.
+		done := make(chan struct{})
+		go func() {
+			s.RunDeletedPurger(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("purger with interval %v is running", interval)
+		}
+	}
+}
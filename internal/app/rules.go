@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// Device families redirect rules are matched on, see DeviceFamily.
+const (
+	DeviceIOS     = "ios"
+	DeviceAndroid = "android"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// Redirect rule limits.
+const (
+	maxURLRules       = 20
+	maxLanguageLength = 35
+	maxRuleQuery      = 10
+)
+
+// ErrInvalidRules is returned when the redirect rules of a short URL break the validation rules.
+// The returned error wraps it together with the reason.
+var ErrInvalidRules = errors.New("invalid redirect rules")
+
+// botMarkers are the lowercased substrings of the User-Agent headers of crawlers, link previews and checkers.
+var botMarkers = []string{"bot", "crawl", "spider", "slurp", "facebookexternalhit", "preview", "monitor", "curl", "wget"}
+
+// DeviceFamily classifies a User-Agent header as one of the Device* families.
+// Crawlers and other automated clients are bots, iPhone, iPad and iPod browsers are iOS ones.
+// Anything else, including an empty User-Agent, is a desktop.
+func DeviceFamily(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			return DeviceBot
+		}
+	}
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return DeviceIOS
+	case strings.Contains(ua, "android"):
+		return DeviceAndroid
+	}
+	return DeviceDesktop
+}
+
+// PreferredLanguage returns the lowercased language tag of an Accept-Language header with the highest quality,
+// the first one of equally preferred tags. Wildcards and tags with zero quality are ignored.
+// It returns an empty string if there is no such tag.
+func PreferredLanguage(acceptLanguage string) string {
+	preferred, best := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > best {
+			preferred, best = tag, q
+		}
+	}
+	return preferred
+}
+
+// NormalizeRules checks the redirect rules against the validation rules and returns them in the canonical form.
+// Every rule must have a target and at least one condition: a device family, a language tag
+// of letters, digits and '-' or query parameters. Devices and languages are lowercased
+// and targets are normalized the same way original URLs are, see NormalizeURL.
+// A short URL can have at most 20 rules with at most 10 query parameters each.
+//
+// Returns:
+// - The rules in the canonical form, in the given order.
+// - An error wrapping ErrInvalidRules with the reason if a rule is invalid or there are too many rules.
+func (s *ShortenerService) NormalizeRules(rules []file.RedirectRule) ([]file.RedirectRule, error) {
+	if len(rules) > maxURLRules {
+		return nil, fmt.Errorf("%w: a short URL can have at most %d rules", ErrInvalidRules, maxURLRules)
+	}
+	normalized := make([]file.RedirectRule, 0, len(rules))
+	for i, rule := range rules {
+		rule, err := s.normalizeRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidRules, i+1, err)
+		}
+		normalized = append(normalized, rule)
+	}
+	return normalized, nil
+}
+
+// normalizeRule checks a single redirect rule and returns it in the canonical form.
+// The returned error describes the reason without wrapping ErrInvalidRules.
+func (s *ShortenerService) normalizeRule(rule file.RedirectRule) (file.RedirectRule, error) {
+	rule.Device = strings.ToLower(strings.TrimSpace(rule.Device))
+	switch rule.Device {
+	case "", DeviceIOS, DeviceAndroid, DeviceDesktop, DeviceBot:
+	default:
+		return rule, fmt.Errorf("device must be one of %q, %q, %q or %q", DeviceIOS, DeviceAndroid, DeviceDesktop, DeviceBot)
+	}
+
+	rule.Language = strings.ToLower(strings.TrimSpace(rule.Language))
+	if len(rule.Language) > maxLanguageLength {
+		return rule, fmt.Errorf("language must be at most %d characters", maxLanguageLength)
+	}
+	for _, c := range rule.Language {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return rule, fmt.Errorf("language %q must be a tag like \"de\" or \"pt-br\"", rule.Language)
+		}
+	}
+
+	if len(rule.Query) > maxRuleQuery {
+		return rule, fmt.Errorf("query can have at most %d parameters", maxRuleQuery)
+	}
+	for name := range rule.Query {
+		if name == "" {
+			return rule, errors.New("query parameter name is empty")
+		}
+	}
+	if len(rule.Query) == 0 {
+		rule.Query = nil
+	}
+
+	if rule.Device == "" && rule.Language == "" && rule.Query == nil {
+		return rule, errors.New("rule must have a device, a language or query parameters, the original URL is the default")
+	}
+
+	target, err := s.NormalizeURL(rule.Target)
+	if err != nil {
+		return rule, fmt.Errorf("target: %w", err)
+	}
+	rule.Target = target
+	return rule, nil
+}
+
+// matchRule returns the target of the first rule the request matches, or an empty string if it matches none.
+func matchRule(rules []file.RedirectRule, req RedirectRequest) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	device := DeviceFamily(req.UserAgent)
+	language := PreferredLanguage(req.AcceptLanguage)
+	for _, rule := range rules {
+		switch {
+		case rule.Device != "" && rule.Device != device,
+			rule.Language != "" && language != rule.Language && !strings.HasPrefix(language, rule.Language+"-"),
+			!matchQuery(rule.Query, req.Query):
+			continue
+		}
+		return rule.Target
+	}
+	return ""
+}
+
+// matchQuery reports whether the query has all parameters of the rule,
+// a parameter with an empty value in the rule matches any value.
+func matchQuery(want map[string]string, query url.Values) bool {
+	for name, value := range want {
+		values, ok := query[name]
+		if !ok || value != "" && !slices.Contains(values, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetURLRules replaces the redirect rules of the short URL ID owned by the user.
+// The rules are checked in order on every redirect and the first one matching the visitor
+// selects the target, visitors matching none are redirected to the original URL. No rules remove them all.
+//
+// Returns:
+// - The rules in the canonical form, see NormalizeRules.
+// - ErrInvalidRules if the rules are invalid, ErrURLNotFound if the short URL does not exist
+// or belongs to another user, or an error if the storage fails.
+func (s *ShortenerService) SetURLRules(ctx context.Context, userID, shortID string, rules []file.RedirectRule) ([]file.RedirectRule, error) {
+	rules, err := s.NormalizeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	err = s.Store.SetRules(ctx, s.Cfg.BaseURL+"/"+shortID, userID, rules)
+	// Do not reveal that other users' short URLs exist.
+	if errors.Is(err, os.ErrProcessDone) || errors.Is(err, database.ErrorNotOwner) {
+		return nil, ErrURLNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetURLRules returns the redirect rules of the short URL ID owned by the user
+// together with its original URL, the default target.
+//
+// Returns:
+// - The original URL and the rules in the order they are checked.
+// - ErrURLNotFound if the short URL does not exist or belongs to another user,
+// or an error if the storage fails.
+func (s *ShortenerService) GetURLRules(ctx context.Context, userID, shortID string) (string, []file.RedirectRule, error) {
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+
+	rec, err := s.Store.GetURLRecord(ctx, s.Cfg.BaseURL+"/"+shortID)
+	if errors.Is(err, os.ErrProcessDone) {
+		return "", nil, ErrURLNotFound
+	} else if err != nil {
+		return "", nil, err
+	}
+	// Do not reveal that other users' short URLs exist.
+	if rec.UserUUID != userID {
+		return "", nil, ErrURLNotFound
+	}
+	return rec.OriginalURL, rec.Rules, nil
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestDeviceFamily(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15", DeviceIOS},
+		{"Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X)", DeviceIOS},
+		{"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 Mobile", DeviceAndroid},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", DeviceBot},
+		{"Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X) (compatible; Googlebot/2.1)", DeviceBot},
+		{"curl/8.5.0", DeviceBot},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36", DeviceDesktop},
+		{"", DeviceDesktop},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DeviceFamily(tt.userAgent), tt.userAgent)
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"de-DE,de;q=0.9,en;q=0.8", "de-de"},
+		{"en;q=0.5, fr", "fr"},
+		{"*, ru;q=0.7", "ru"},
+		{"es;q=0, it;q=0.1", "it"},
+		{"en;q=abc", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PreferredLanguage(tt.acceptLanguage), tt.acceptLanguage)
+	}
+}
+
+func TestNormalizeRules(t *testing.T) {
+	s := &ShortenerService{Cfg: &config.Config{}}
+
+	rules, err := s.NormalizeRules([]file.RedirectRule{
+		{Device: " iOS ", Language: "PT-BR", Query: map[string]string{}, Target: "HTTPS://Example.com/app"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []file.RedirectRule{{Device: DeviceIOS, Language: "pt-br", Target: "https://example.com/app"}}, rules)
+
+	invalid := [][]file.RedirectRule{
+		{{Target: "https://example.com"}},
+		{{Device: "tablet", Target: "https://example.com"}},
+		{{Language: "en_US", Target: "https://example.com"}},
+		{{Query: map[string]string{"": "x"}, Target: "https://example.com"}},
+		{{Device: DeviceBot, Target: "javascript:alert(1)"}},
+		{{Device: DeviceBot}},
+		make([]file.RedirectRule, maxURLRules+1),
+	}
+	for _, rules := range invalid {
+		_, err := s.NormalizeRules(rules)
+		assert.ErrorIs(t, err, ErrInvalidRules)
+	}
+}
+
+func TestRedirectRules(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: memory.NewMemoryStore(config.DedupGlobal),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080", PermanentRedirectMaxAge: config.Duration(24 * time.Hour)},
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "app", RedirectCode: http.StatusMovedPermanently})
+	require.NoError(t, err)
+
+	// Only the owner manages the rules.
+	_, err = s.SetURLRules(ctx, "user2", "app", nil)
+	assert.ErrorIs(t, err, ErrURLNotFound)
+	_, _, err = s.GetURLRules(ctx, "user2", "app")
+	assert.ErrorIs(t, err, ErrURLNotFound)
+
+	_, err = s.SetURLRules(ctx, "user1", "app", []file.RedirectRule{
+		{Device: DeviceIOS, Target: "https://apps.apple.com/app"},
+		{Language: "de", Target: "https://a.de"},
+		{Query: map[string]string{"utm_source": "mail", "ref": ""}, Target: "https://a.ru/mail"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		req  RedirectRequest
+		want string
+	}{
+		{"ios", RedirectRequest{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)", AcceptLanguage: "de"}, "https://apps.apple.com/app"},
+		{"language prefix", RedirectRequest{AcceptLanguage: "de-AT,en;q=0.5"}, "https://a.de"},
+		{"less preferred language", RedirectRequest{AcceptLanguage: "en,de;q=0.5"}, "https://a.ru"},
+		{"query", RedirectRequest{Query: url.Values{"utm_source": {"mail"}, "ref": {"x"}}}, "https://a.ru/mail"},
+		{"missing query parameter", RedirectRequest{Query: url.Values{"utm_source": {"mail"}}}, "https://a.ru"},
+		{"default", RedirectRequest{}, "https://a.ru"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redirect, err := s.GetRedirect(ctx, "app", tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, redirect.URL)
+			// Redirects depending on the visitor are never cached.
+			assert.Zero(t, redirect.MaxAge)
+		})
+	}
+
+	originalURL, rules, err := s.GetURLRules(ctx, "user1", "app")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", originalURL)
+	assert.Len(t, rules, 3)
+	rec, err := s.Store.GetURLRecord(ctx, "http://localhost:8080/app")
+	require.NoError(t, err)
+	assert.NotNil(t, rec.UpdatedAt)
+
+	// No rules remove them all and the permanent redirect is cached again.
+	_, err = s.SetURLRules(ctx, "user1", "app", nil)
+	require.NoError(t, err)
+	redirect, err := s.GetRedirect(ctx, "app", RedirectRequest{AcceptLanguage: "de"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", redirect.URL)
+	assert.Equal(t, 24*time.Hour, redirect.MaxAge)
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+)
+
+// Tag limits.
+const (
+	maxTagLength = 64
+	maxURLTags   = 20
+)
+
+// ErrInvalidTags is returned when the tags of a short URL break the validation rules.
+// The returned error wraps it together with the reason.
+var ErrInvalidTags = errors.New("invalid tags")
+
+// NormalizeTags checks the tags against the validation rules and returns them in the canonical form.
+// Tags are trimmed and lowercased, they must be 1 to 64 characters long and consist of letters, digits,
+// '-', '_', '.' and '/'. Slash-separated tags like "team/marketing" can be used as folders.
+// A short URL can have at most 20 unique tags.
+//
+// Returns:
+// - The sorted unique tags.
+// - An error wrapping ErrInvalidTags with the reason if a tag is invalid or there are too many tags.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+	if len(normalized) > maxURLTags {
+		return nil, fmt.Errorf("%w: a short URL can have at most %d tags", ErrInvalidTags, maxURLTags)
+	}
+	return normalized, nil
+}
+
+// normalizeTag checks a single tag and returns it in the canonical form.
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if n := utf8.RuneCountInString(tag); n == 0 || n > maxTagLength {
+		return "", fmt.Errorf("%w: length must be between 1 and %d characters", ErrInvalidTags, maxTagLength)
+	}
+	for _, c := range tag {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune("-_./", c) {
+			return "", fmt.Errorf("%w: character %q is not allowed, use letters, digits, '-', '_', '.' and '/'", ErrInvalidTags, c)
+		}
+	}
+	if strings.HasPrefix(tag, "/") || strings.HasSuffix(tag, "/") || strings.Contains(tag, "//") {
+		return "", fmt.Errorf("%w: %q has an empty folder", ErrInvalidTags, tag)
+	}
+	return tag, nil
+}
+
+// SetURLTags replaces the tags of the short URL ID owned by the user.
+//
+// Returns:
+// - The tags of the short URL in the canonical form.
+// - ErrInvalidTags if the tags are invalid, ErrURLNotFound if the short URL does not exist
+// or belongs to another user, or an error if the storage fails.
+func (s *ShortenerService) SetURLTags(ctx context.Context, userID, shortID string, tags []string) ([]string, error) {
+	tags, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	err = s.Store.SetTags(ctx, s.Cfg.BaseURL+"/"+shortID, userID, tags)
+	// Do not reveal that other users' short URLs exist.
+	if errors.Is(err, os.ErrProcessDone) || errors.Is(err, database.ErrorNotOwner) {
+		return nil, ErrURLNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetTagStats returns the number of short URLs of the user and their clicks by tags.
+//
+// Returns:
+// - The statistics of every tag of the user ordered by tag.
+// - An error if the storage fails.
+func (s *ShortenerService) GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error) {
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+
+	return s.Store.GetTagStats(ctx, userID)
+}
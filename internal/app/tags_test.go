@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "canonical form", tags: []string{" Docs ", "team/Marketing", "docs"}, want: []string{"docs", "team/marketing"}},
+		{name: "unicode", tags: []string{"новости"}, want: []string{"новости"}},
+		{name: "empty", tags: []string{" "}, wantErr: true},
+		{name: "too long", tags: []string{strings.Repeat("a", maxTagLength+1)}, wantErr: true},
+		{name: "space inside", tags: []string{"two words"}, wantErr: true},
+		{name: "empty folder", tags: []string{"team//marketing"}, wantErr: true},
+		{name: "too many", tags: strings.Split("a b c d e f g h i j k l m n o p q r s t u", " "), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTags)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestURLTags(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewMemoryStore(config.DedupGlobal)
+	s := &ShortenerService{
+		Store: store,
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080"},
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "abc", Tags: []string{"News"}})
+	require.NoError(t, err)
+	_, err = s.CreateShortURL(ctx, "https://d.ru", "user1", CreateOptions{Alias: "def"})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveClicks(ctx, []clicks.Click{
+		{ShortURL: "http://localhost:8080/abc", Time: time.Now()},
+		{ShortURL: "http://localhost:8080/def", Time: time.Now()},
+	}))
+
+	tags, err := s.SetURLTags(ctx, "user1", "def", []string{"news", "docs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs", "news"}, tags)
+
+	_, err = s.SetURLTags(ctx, "user2", "def", []string{"mine"})
+	assert.ErrorIs(t, err, ErrURLNotFound)
+
+	page, err := s.GetUserURLs(ctx, "user1", URLsQuery{Tag: "DOCS"})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	assert.Equal(t, "http://localhost:8080/def", page.Records[0].ShortURL)
+	assert.NotNil(t, page.Records[0].UpdatedAt)
+
+	stats, err := s.GetTagStats(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, []clicks.TagStats{
+		{Tag: "docs", URLs: 1, Clicks: 1},
+		{Tag: "news", URLs: 2, Clicks: 2},
+	}, stats)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// UpdateURL changes the original URL the user's short URL ID redirects to
+// and records the change in the history of the short URL.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - shortID: The ID of the short URL.
+// - originalURL: The new original URL.
+//
+// Returns:
+// - The recorded revision.
+// - ErrEmptyURL if the original URL is empty, ErrInvalidURL if it is invalid, ErrURLNotFound if the short URL does not exist
+// or belongs to another user, ErrURLDeleted if it is deleted, database.ErrorDuplicate
+// if the new original URL is already shortened, or an error if the storage fails.
+func (s *ShortenerService) UpdateURL(ctx context.Context, userID, shortID, originalURL string) (*file.URLRevision, error) {
+	originalURL, err := s.NormalizeURL(originalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := s.withWriteTimeout(ctx)
+	defer cancel()
+
+	rev, err := s.Store.UpdateOriginalURL(ctx, s.Cfg.BaseURL+"/"+shortID, userID, originalURL)
+	switch {
+	// Do not reveal that other users' short URLs exist.
+	case errors.Is(err, os.ErrProcessDone), errors.Is(err, database.ErrorNotOwner):
+		return nil, ErrURLNotFound
+	case errors.Is(err, database.ErrorDeleted):
+		return nil, ErrURLDeleted
+	case err != nil:
+		return nil, err
+	}
+	return rev, nil
+}
+
+// GetURLHistory returns the changes of the original URL of the user's short URL ID.
+//
+// Returns:
+// - The revisions in the order they were made, none if the original URL has never changed.
+// - ErrURLNotFound if the short URL does not exist or belongs to another user,
+// or an error if the query fails.
+func (s *ShortenerService) GetURLHistory(ctx context.Context, userID, shortID string) ([]file.URLRevision, error) {
+	ctx, cancel := s.withReadTimeout(ctx)
+	defer cancel()
+
+	revisions, err := s.Store.GetURLHistory(ctx, s.Cfg.BaseURL+"/"+shortID, userID)
+	if errors.Is(err, os.ErrProcessDone) || errors.Is(err, database.ErrorNotOwner) {
+		return nil, ErrURLNotFound
+	}
+	return revisions, err
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func TestUpdateURL(t *testing.T) {
+	ctx := context.Background()
+	s := &ShortenerService{
+		Store: NewCachedStore(memory.NewMemoryStore(config.DedupGlobal), 10, time.Minute, time.Minute, 0),
+		Cfg:   &config.Config{BaseURL: "http://localhost:8080"},
+	}
+
+	_, err := s.CreateShortURL(ctx, "https://a.ru", "user1", CreateOptions{Alias: "abc"})
+	require.NoError(t, err)
+	originalURL, err := s.GetShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", originalURL)
+
+	rev, err := s.UpdateURL(ctx, "user1", "abc", "https://b.ru")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/abc", rev.ShortURL)
+	assert.Equal(t, "user1", rev.UserUUID)
+
+	// The cached redirect follows the change.
+	originalURL, err = s.GetShortURL(ctx, "abc")
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.ru", originalURL)
+
+	history, err := s.GetURLHistory(ctx, "user1", "abc")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "https://a.ru", history[0].OldURL)
+
+	// Other users' short URLs are reported as unknown.
+	_, err = s.UpdateURL(ctx, "user2", "abc", "https://c.ru")
+	assert.ErrorIs(t, err, ErrURLNotFound)
+	_, err = s.GetURLHistory(ctx, "user2", "abc")
+	assert.ErrorIs(t, err, ErrURLNotFound)
+	_, err = s.UpdateURL(ctx, "user1", "abc", "")
+	assert.ErrorIs(t, err, ErrEmptyURL)
+}
@@ -3,23 +3,31 @@
 package app
 
 import (
-	"crypto/rand"
-	"encoding/base64"
+	"context"
+	"time"
 
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
 	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
-)
-
-var (
-	// Counter helps to store URLs UUIDs.
-	// It increments every time adding a new URL to the storage.
-	Counter = 1
+	"github.com/KirillZiborov/lnkshortener/internal/idgen"
 )
 
 // ShortenerService is the facade providing business logic for creating short URLs.
 type ShortenerService struct {
 	Store URLStore
 	Cfg   *config.Config
+	// Clicks records redirects asynchronously, clicks are not tracked if it is nil.
+	Clicks *clicks.Recorder
+	// IDs generates short URL IDs, a generator is created from Cfg if it is nil.
+	IDs idgen.Generator
+	// Deletes processes deletion jobs, deletions are rejected if it is nil.
+	Deletes *deletion.Queue
+	// Attempts limits wrong passwords of protected short URLs, they are not limited if it is nil.
+	Attempts *AttemptLimiter
+	// InactivePage is the HTML page served for short URLs which are not active yet
+	// with the config.InactiveShowPage response, a built-in page is served if it is empty.
+	InactivePage []byte
 }
 
 // URLStore defines the interface for URL storage operations.
@@ -29,66 +37,304 @@ type URLStore interface {
 	// If the original URL already exists, it returns the existing short URL and an error indicating duplication.
 	//
 	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
 	// - urlRecord: A pointer to the URLRecord to be saved.
 	//
 	// Returns:
 	// - The short URL string if the insertion is successful.
 	// - An error if the insertion fails or if the URL already exists.
-	SaveURLRecord(urlRecord *file.URLRecord) (string, error)
+	SaveURLRecord(ctx context.Context, urlRecord *file.URLRecord) (string, error)
+
+	// SaveURLRecords saves a batch of URLRecords in a single storage operation.
+	// Every record is checked the same way SaveURLRecord checks it.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - records: The URLRecords to be saved.
+	// - atomic: Whether nothing is saved if any short URL is already used.
+	//
+	// Returns:
+	// - The result of every record in the order of the records: the saved short URL,
+	// or the existing short URL with database.ErrorDuplicate, or database.ErrorShortURLTaken.
+	// - database.ErrorBatchRejected together with the results if the atomic batch is not saved,
+	// or another error if the storage operation fails.
+	SaveURLRecords(ctx context.Context, records []*file.URLRecord, atomic bool) ([]file.SaveResult, error)
 
-	// GetOriginalURL retrieves the original URL and its deletion status based on the provided short URL.
+	// GetURLRecord retrieves the URL record based on the provided short URL.
 	//
 	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
 	// - shortURL: The short URL identifier to look up.
 	//
 	// Returns:
-	// - The corresponding original URL string if found.
-	// - A boolean indicating whether the URL has been marked as deleted.
+	// - The URL record with the original URL, its deletion status and expiration time if found.
 	// - An error if the short URL does not exist or if the query fails.
-	GetOriginalURL(shortURL string) (string, bool, error)
+	GetURLRecord(ctx context.Context, shortURL string) (*file.URLRecord, error)
 
-	// GetUserURLs retrieves all URL records associated with a specific user ID.
+	// GetUserURLs retrieves a page of the URL records associated with a specific user ID
+	// which match the filters of the query, ordered by creation time.
 	//
 	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
 	// - userID: The user ID whose URLs are to be retrieved.
+	// - q: The filters, the order and the position of the page.
 	//
 	// Returns:
-	// - A slice of URLRecord containing the user's URLs.
+	// - The page of the user's URLs with the total number of matching URLs and the position of the next page.
 	// - An error if the query fails.
-	GetUserURLs(userID string) ([]file.URLRecord, error)
+	GetUserURLs(ctx context.Context, userID string, q file.URLQuery) (file.URLPage, error)
 
-	// BatchUpdateDeleteFlag marks multiple URL records as deleted based on the provided URL ID and user ID.
+	// BatchUpdateDeleteFlag marks multiple URL records as deleted in a single storage operation.
+	// Unknown short URLs and the ones belonging to other users are ignored.
 	//
 	// Parameters:
-	// - urlID: The UUID of the URL record to be marked as deleted.
-	// - userID: The user ID associated with the URL record.
+	// - ctx: The context for managing cancellation and timeouts.
+	// - urlIDs: The short URLs of the records to be marked as deleted.
+	// - userID: The user ID associated with the URL records.
 	//
 	// Returns:
 	// - An error if the update operation fails.
-	BatchUpdateDeleteFlag(urlID string, userID string) error
+	BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error
+
+	// RestoreURLs clears the deletion mark of multiple URL records in a single storage operation.
+	// Records deleted before deletedSince, unknown short URLs and the ones belonging to other users are skipped.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - urlIDs: The short URLs of the records to be restored.
+	// - userID: The user ID associated with the URL records.
+	// - deletedSince: The earliest deletion time of the records that can be restored.
+	//
+	// Returns:
+	// - The short URLs of the restored records.
+	// - An error if the update operation fails.
+	RestoreURLs(ctx context.Context, urlIDs []string, userID string, deletedSince time.Time) ([]string, error)
+
+	// PurgeDeleted removes URL records deleted before the given time from the storage
+	// together with their clicks and revisions.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - deletedBefore: The time the records must have been deleted before.
+	//
+	// Returns:
+	// - A number of removed URL records.
+	// - An error if the removal fails.
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
+
+	// UpdateOriginalURL changes the original URL of the user's short URL and records the revision.
+	// Ownership is checked by the storage.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL to be changed.
+	// - userID: The user ID changing the short URL.
+	// - originalURL: The new original URL.
+	//
+	// Returns:
+	// - The recorded revision.
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorNotOwner if it belongs to another user,
+	// database.ErrorDeleted if it is deleted, database.ErrorDuplicate if the new original URL is already shortened,
+	// or an error if the update fails.
+	UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*file.URLRevision, error)
+
+	// GetURLHistory retrieves the revisions of the user's short URL.
+	// Ownership is checked by the storage.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL to look up.
+	// - userID: The user ID requesting the history.
+	//
+	// Returns:
+	// - The revisions in the order they were made.
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorNotOwner if it belongs to another user,
+	// or an error if the query fails.
+	GetURLHistory(ctx context.Context, shortURL, userID string) ([]file.URLRevision, error)
 
 	// GetURLsCount counts shortened URLs.
 	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	//
 	// Returns:
 	// - A number of shortened URLs.
 	// - An error if the query fails.
-	GetURLsCount() (int, error)
+	GetURLsCount(ctx context.Context) (int, error)
 
 	// GetUsersCount counts unique users.
 	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	//
 	// Returns:
 	// - A number of unique users.
 	// - An error if the query fails.
-	GetUsersCount() (int, error)
+	GetUsersCount(ctx context.Context) (int, error)
+
+	// DeleteExpired removes URL records expired by the given time from the storage.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - now: The time to check expiration against.
+	// - policy: One of config.Expired* values, whether the records are deleted or archived.
+	//
+	// Returns:
+	// - A number of removed URL records.
+	// - An error if the removal fails.
+	DeleteExpired(ctx context.Context, now time.Time, policy string) (int, error)
+
+	// SaveClicks stores a batch of click events.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - events: The click events to be saved.
+	//
+	// Returns:
+	// - An error if the insertion fails.
+	SaveClicks(ctx context.Context, events []clicks.Click) error
+
+	// GetClickStats aggregates the clicks of a short URL.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - q: The short URL, the period and the bucket size to aggregate clicks by.
+	//
+	// Returns:
+	// - The total number of clicks and non-empty buckets in ascending order.
+	// - An error if the query fails.
+	GetClickStats(ctx context.Context, q clicks.Query) (clicks.Stats, error)
+
+	// SetTags replaces the tags of the user's short URL.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL to be tagged.
+	// - userID: The user ID tagging the short URL, it must own the short URL.
+	// - tags: The new normalized tags of the short URL.
+	//
+	// Returns:
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorNotOwner if it belongs
+	// to another user, or an error if the storage fails.
+	SetTags(ctx context.Context, shortURL, userID string, tags []string) error
+
+	// GetTagStats counts the short URLs of the user and their clicks by tags.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - userID: The user ID whose tags are counted.
+	//
+	// Returns:
+	// - The statistics of every tag of the user ordered by tag.
+	// - An error if the query fails.
+	GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error)
+
+	// SetActiveWindow changes the activation window of the user's short URL.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL to be changed.
+	// - userID: The user ID changing the short URL, it must own the short URL.
+	// - notBefore: The time the short URL becomes active at, nil if it is active right away.
+	// - notAfter: The time the short URL stops being active at, nil if it stays active.
+	//
+	// Returns:
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorNotOwner if it belongs
+	// to another user, or an error if the storage fails.
+	SetActiveWindow(ctx context.Context, shortURL, userID string, notBefore, notAfter *time.Time) error
+
+	// SetRules replaces the redirect rules of the user's short URL.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL to be changed.
+	// - userID: The user ID changing the short URL, it must own the short URL.
+	// - rules: The new validated redirect rules of the short URL.
+	//
+	// Returns:
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorNotOwner if it belongs
+	// to another user, or an error if the storage fails.
+	SetRules(ctx context.Context, shortURL, userID string, rules []file.RedirectRule) error
+
+	// UseClick atomically counts a redirect of the short URL against its click limit.
+	// Redirects of short URLs without a limit are not counted.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - shortURL: The short URL being followed.
+	//
+	// Returns:
+	// - os.ErrProcessDone if the short URL does not exist, database.ErrorExhausted if the limit
+	// is already reached, or an error if the storage fails.
+	UseClick(ctx context.Context, shortURL string) error
+
+	// NextSequence returns the next number of the sequence used to generate short URL IDs.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	//
+	// Returns:
+	// - The next number of the sequence, numbers are never reused.
+	// - An error if the query fails.
+	NextSequence(ctx context.Context) (int64, error)
+
+	// SaveDeleteJob saves a new deletion job with its items.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - job: A pointer to the job to be saved, it is assigned an ID.
+	//
+	// Returns:
+	// - An error if the insertion fails.
+	SaveDeleteJob(ctx context.Context, job *deletion.Job) error
+
+	// UpdateDeleteJobItems saves the new statuses of deletion job items in a single storage operation.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - jobID: The ID of the job the items belong to.
+	// - items: The items with their new statuses, they are matched by the short URL.
+	//
+	// Returns:
+	// - An error if the update fails.
+	UpdateDeleteJobItems(ctx context.Context, jobID string, items []deletion.Item) error
+
+	// GetDeleteJob retrieves a deletion job with its items.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	// - id: The ID of the job.
+	//
+	// Returns:
+	// - The job if found.
+	// - An error if the job does not exist or if the query fails.
+	GetDeleteJob(ctx context.Context, id string) (*deletion.Job, error)
+
+	// PendingDeleteJobs retrieves the deletion jobs having pending items.
+	//
+	// Parameters:
+	// - ctx: The context for managing cancellation and timeouts.
+	//
+	// Returns:
+	// - The pending jobs in creation order.
+	// - An error if the query fails.
+	PendingDeleteJobs(ctx context.Context) ([]deletion.Job, error)
+}
+
+// withReadTimeout derives a context for a single storage read limited by the configured timeout.
+func (s *ShortenerService) withReadTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, time.Duration(s.Cfg.StorageReadTimeout))
+}
+
+// withWriteTimeout derives a context for a single storage write limited by the configured timeout.
+func (s *ShortenerService) withWriteTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, time.Duration(s.Cfg.StorageWriteTimeout))
 }
 
-// generateID is a helper function to generate a shortened URL.
-// It generates and returns 8-character string.
-func generateID() string {
-	b := make([]byte, 8)
-	_, err := rand.Read(b)
-	if err != nil {
-		panic(err)
+// withTimeout derives a context limited by the timeout, zero timeout means no additional limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	return base64.URLEncoding.EncodeToString(b)
+	return context.WithTimeout(ctx, timeout)
 }
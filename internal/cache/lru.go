@@ -0,0 +1,136 @@
+// Package cache provides a size-bounded least recently used cache with expiring entries.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a cache keeping at most a fixed number of entries.
+// Adding an entry to the full cache evicts the least recently used one,
+// and every entry expires after its own time to live.
+// It is safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[K]*list.Element
+	now   func() time.Time
+}
+
+// entry is a cached value with its key and expiration time.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU creates a cache keeping at most size entries.
+//
+// Parameters:
+// - size: The maximum number of entries, at least one entry is kept.
+//
+// Returns:
+// - A pointer to an empty LRU instance.
+func NewLRU[K comparable, V any](size int) *LRU[K, V] {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU[K, V]{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[K]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// Get returns the value cached by the key and marks it as recently used.
+// Expired entries are removed instead.
+//
+// Parameters:
+// - key: The key of the value.
+//
+// Returns:
+// - The cached value and true if the key is found and not expired, the zero value and false otherwise.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if !c.now().Before(e.expiresAt) {
+		c.removeElement(elem)
+		var zero V
+		return zero, false
+	}
+	c.ll.MoveToFront(elem)
+	return e.value, true
+}
+
+// Add caches the value by the key for the given time, replacing the previous value of the key.
+// The least recently used entry is evicted if the cache is full.
+//
+// Parameters:
+// - key: The key of the value.
+// - value: The value to be cached.
+// - ttl: The time the value expires after, nothing is cached if it is not positive.
+func (c *LRU[K, V]) Add(key K, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.ll.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	if c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Remove removes the entries of the keys, unknown keys are ignored.
+func (c *LRU[K, V]) Remove(keys ...K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.removeElement(elem)
+		}
+	}
+}
+
+// Purge removes all entries.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[K]*list.Element)
+}
+
+// Len returns the number of cached entries including the expired ones not removed yet.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// removeElement removes the list element and its key, the caller must hold the lock.
+func (c *LRU[K, V]) removeElement(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.items, elem.Value.(*entry[K, V]).key)
+}
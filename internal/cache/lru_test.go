@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	c := NewLRU[string, int](2)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1, time.Minute)
+	c.Add("b", 2, time.Minute)
+
+	// Reading a marks b as the least recently used entry.
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.Add("c", 3, time.Minute)
+	_, ok = c.Get("b")
+	assert.False(t, ok, "least recently used entry must be evicted")
+	assert.Equal(t, 2, c.Len())
+
+	// Entries expire after their own TTL.
+	c.Add("d", 4, 2*time.Minute)
+	now = now.Add(time.Minute)
+	_, ok = c.Get("c")
+	assert.False(t, ok, "expired entry must not be returned")
+	v, ok = c.Get("d")
+	assert.True(t, ok)
+	assert.Equal(t, 4, v)
+
+	c.Remove("d", "unknown")
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+	assert.Zero(t, c.Len())
+
+	c.Add("d", 4, time.Minute)
+	c.Purge()
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+	assert.Zero(t, c.Len())
+
+	// Non-positive TTL caches nothing.
+	c.Add("e", 5, 0)
+	_, ok = c.Get("e")
+	assert.False(t, ok)
+}
@@ -0,0 +1,27 @@
+package clicks
+
+import (
+	"net"
+	"strings"
+)
+
+// Sizes of the network part kept by AnonymizeIP.
+const (
+	ipv4KeptBits = 24 // ipv4KeptBits keeps the first 3 bytes of an IPv4 address.
+	ipv6KeptBits = 48 // ipv6KeptBits keeps the first 6 bytes of an IPv6 address.
+)
+
+// AnonymizeIP zeroes the host part of the IP address, so it no longer identifies a user
+// but still tells the network the click came from: the last byte of IPv4 addresses
+// and the last 10 bytes of IPv6 addresses are zeroed.
+// It returns an empty string if the address cannot be parsed.
+func AnonymizeIP(addr string) string {
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return ""
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(ipv4KeptBits, 32)).String()
+	}
+	return ip.Mask(net.CIDRMask(ipv6KeptBits, 128)).String()
+}
@@ -0,0 +1,90 @@
+// Package clicks provides click tracking for short URLs.
+// It defines click events and their aggregated statistics,
+// and a Recorder buffering click events and persisting them asynchronously,
+// so redirects never wait for the storage.
+package clicks
+
+import (
+	"sort"
+	"time"
+)
+
+// Click is a single redirect through a short URL.
+type Click struct {
+	ShortURL  string    `json:"short_url"`            // ShortURL is the short URL that was followed.
+	Time      time.Time `json:"time"`                 // Time is the time of the redirect.
+	Referrer  string    `json:"referrer,omitempty"`   // Referrer is the Referer header of the request.
+	UserAgent string    `json:"user_agent,omitempty"` // UserAgent is the User-Agent header of the request.
+	IP        string    `json:"ip,omitempty"`         // IP is the anonymized client IP address.
+}
+
+// Bucket sizes supported by the statistics.
+const (
+	BucketHour = "hour" // BucketHour counts clicks per hour.
+	BucketDay  = "day"  // BucketDay counts clicks per day.
+)
+
+// Bucket holds a number of clicks in a time interval.
+type Bucket struct {
+	Start  time.Time `json:"start"`  // Start is the beginning of the interval in UTC.
+	Clicks int       `json:"clicks"` // Clicks is a number of clicks in the interval.
+}
+
+// Stats holds click statistics of a short URL.
+type Stats struct {
+	Total   int      `json:"total"`   // Total is a number of clicks in the requested period.
+	Buckets []Bucket `json:"buckets"` // Buckets holds non-empty intervals in ascending order.
+}
+
+// TagStats holds aggregated statistics of the short URLs of a user tagged with the same tag.
+type TagStats struct {
+	Tag    string `json:"tag"`    // Tag is the tag of the short URLs.
+	URLs   int    `json:"urls"`   // URLs is a number of short URLs tagged with the tag.
+	Clicks int    `json:"clicks"` // Clicks is a number of clicks of all short URLs tagged with the tag.
+}
+
+// Query selects the clicks of a short URL to aggregate.
+type Query struct {
+	ShortURL string    // ShortURL is the short URL to aggregate clicks of.
+	From     time.Time // From is the inclusive beginning of the period, zero means no limit.
+	To       time.Time // To is the exclusive end of the period, zero means no limit.
+	Bucket   string    // Bucket is the bucket size, one of Bucket* values.
+}
+
+// Matches reports whether the click falls into the queried period.
+func (q Query) Matches(c Click) bool {
+	return c.ShortURL == q.ShortURL &&
+		(q.From.IsZero() || !c.Time.Before(q.From)) &&
+		(q.To.IsZero() || c.Time.Before(q.To))
+}
+
+// BucketStart returns the beginning of the bucket the time falls into.
+func BucketStart(t time.Time, bucket string) time.Time {
+	t = t.UTC()
+	if bucket == BucketHour {
+		return t.Truncate(time.Hour)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Aggregate counts the clicks matching the query by buckets.
+// It is used by the stores keeping clicks in memory.
+func Aggregate(all []Click, q Query) Stats {
+	counts := make(map[time.Time]int)
+	stats := Stats{Buckets: []Bucket{}}
+	for _, c := range all {
+		if !q.Matches(c) {
+			continue
+		}
+		stats.Total++
+		counts[BucketStart(c.Time, q.Bucket)]++
+	}
+
+	for start, n := range counts {
+		stats.Buckets = append(stats.Buckets, Bucket{Start: start, Clicks: n})
+	}
+	sort.Slice(stats.Buckets, func(i, j int) bool {
+		return stats.Buckets[i].Start.Before(stats.Buckets[j].Start)
+	})
+	return stats
+}
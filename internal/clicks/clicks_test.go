@@ -0,0 +1,85 @@
+package clicks
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "IPv4", ip: "192.168.1.42", want: "192.168.1.0"},
+		{name: "IPv6", ip: "2001:db8:85a3:1234:5678:8a2e:370:7334", want: "2001:db8:85a3::"},
+		{name: "IPv4-mapped IPv6", ip: "::ffff:10.0.0.7", want: "10.0.0.0"},
+		{name: "invalid", ip: "not an ip", want: ""},
+		{name: "empty", ip: "", want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, AnonymizeIP(tc.ip))
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	base := time.Date(2024, 3, 10, 10, 30, 0, 0, time.UTC)
+	all := []Click{
+		{ShortURL: "a", Time: base},
+		{ShortURL: "a", Time: base.Add(10 * time.Minute)},
+		{ShortURL: "a", Time: base.Add(time.Hour)},
+		{ShortURL: "a", Time: base.Add(24 * time.Hour)},
+		{ShortURL: "b", Time: base},
+	}
+
+	t.Run("by hour", func(t *testing.T) {
+		stats := Aggregate(all, Query{ShortURL: "a", Bucket: BucketHour})
+		assert.Equal(t, 4, stats.Total)
+		require.Len(t, stats.Buckets, 3)
+		assert.Equal(t, Bucket{Start: base.Truncate(time.Hour), Clicks: 2}, stats.Buckets[0])
+	})
+
+	t.Run("by day within period", func(t *testing.T) {
+		stats := Aggregate(all, Query{ShortURL: "a", Bucket: BucketDay, From: base.Add(time.Minute), To: base.Add(2 * time.Hour)})
+		assert.Equal(t, 2, stats.Total)
+		require.Len(t, stats.Buckets, 1)
+		assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), stats.Buckets[0].Start)
+	})
+}
+
+type saverMock struct {
+	mu    sync.Mutex
+	saved []Click
+}
+
+func (s *saverMock) SaveClicks(ctx context.Context, events []Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, events...)
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+
+	saver := &saverMock{}
+	recorder := NewRecorder(saver, 2, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		recorder.Record(Click{ShortURL: "a", Time: time.Now()})
+	}
+	// The buffered clicks are saved on close.
+	recorder.Close()
+
+	assert.Len(t, saver.saved, 3-int(recorder.Dropped()))
+}
@@ -0,0 +1,128 @@
+package clicks
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// Saver persists click events.
+type Saver interface {
+	// SaveClicks stores a batch of click events.
+	// The slice is reused after the call, so it must not be retained.
+	SaveClicks(ctx context.Context, clicks []Click) error
+}
+
+// saveTimeout limits saving a single batch of clicks.
+const saveTimeout = 10 * time.Second
+
+// Recorder buffers click events and saves them in batches in the background.
+// A batch is saved when it is full or when the flush interval passes.
+// If the buffer is full, new clicks are dropped instead of slowing down redirects.
+type Recorder struct {
+	saver         Saver
+	events        chan Click
+	batchSize     int
+	flushInterval time.Duration
+
+	dropped atomic.Int64 // dropped counts clicks dropped because of the full buffer.
+
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewRecorder creates a Recorder and starts saving clicks in the background.
+//
+// Parameters:
+// - saver: The storage to save clicks to.
+// - bufferSize: The number of clicks waiting to be saved, it is the maximum batch size as well.
+// - flushInterval: The maximum time a click waits in the buffer.
+//
+// Returns:
+// - A pointer to a running Recorder, it must be closed to save the buffered clicks.
+func NewRecorder(saver Saver, bufferSize int, flushInterval time.Duration) *Recorder {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	if flushInterval <= 0 {
+		flushInterval = time.Second
+	}
+	r := &Recorder{
+		saver:         saver,
+		events:        make(chan Click, bufferSize),
+		batchSize:     bufferSize,
+		flushInterval: flushInterval,
+	}
+
+	r.wg.Add(1)
+	go r.run()
+
+	return r
+}
+
+// Record queues the click without blocking. The click is dropped if the buffer is full.
+func (r *Recorder) Record(c Click) {
+	select {
+	case r.events <- c:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of clicks dropped because of the full buffer.
+func (r *Recorder) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+// Close stops accepting clicks, saves the buffered ones and waits for it to finish.
+// Record must not be called after Close.
+func (r *Recorder) Close() {
+	r.closeOnce.Do(func() {
+		close(r.events)
+	})
+	r.wg.Wait()
+}
+
+// run collects clicks into batches and saves them until the recorder is closed.
+func (r *Recorder) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Click, 0, r.batchSize)
+	for {
+		select {
+		case c, ok := <-r.events:
+			if !ok {
+				r.save(batch)
+				return
+			}
+			batch = append(batch, c)
+			if len(batch) >= r.batchSize {
+				r.save(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.save(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// save stores the batch and logs failures, clicks of a failed batch are lost.
+func (r *Recorder) save(batch []Click) {
+	if len(batch) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+
+	if err := r.saver.SaveClicks(ctx, batch); err != nil {
+		logging.Sugar.Errorw("Failed to save clicks", "count", len(batch), "error", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/KirillZiborov/lnkshortener/internal/logging"
 )
@@ -41,8 +43,166 @@ type Config struct {
 	// Example: "localhost:9090".
 	// If empty, gRPC is disabled.
 	GRPCAddress string `json:"grpc_address"`
+	// StorageType selects the URL storage backend: "memory", "file" or "database".
+	// If not set, it is derived from DBPath: "database" if DBPath is set and "file" otherwise.
+	StorageType string `json:"storage_type"`
+	// FileCompactInterval defines how often the file storage log is compacted.
+	// Example: "10m"
+	FileCompactInterval Duration `json:"file_compact_interval"`
+	// StorageReadTimeout limits every read operation on the storage.
+	// Zero means no limit besides the request deadline.
+	// Example: "5s"
+	StorageReadTimeout Duration `json:"storage_read_timeout"`
+	// StorageWriteTimeout limits every write operation on the storage.
+	// Zero means no limit besides the request deadline.
+	// Example: "10s"
+	StorageWriteTimeout Duration `json:"storage_write_timeout"`
+	// DedupPolicy defines which shortened original URLs are considered duplicates:
+	// "global" - any user's, "per-user" - the same user's, "none" - duplicates are allowed.
+	DedupPolicy string `json:"dedup_policy"`
+	// DefaultTTL is the lifetime of short URLs created without an explicit expiry.
+	// Zero means short URLs never expire by default.
+	// Example: "720h"
+	DefaultTTL Duration `json:"default_ttl"`
+	// ExpiredSweepInterval defines how often expired short URLs are removed from the storage.
+	// A non-positive interval disables sweeping.
+	// Example: "1m"
+	ExpiredSweepInterval Duration `json:"expired_sweep_interval"`
+	// ExpiredPolicy defines what happens to expired short URLs when they are swept:
+	// "purge" - they are deleted, "archive" - they are moved to the archive.
+	ExpiredPolicy string `json:"expired_policy"`
+	// ClickBufferSize is the number of click events waiting to be saved.
+	// Clicks are dropped while the buffer is full.
+	ClickBufferSize int `json:"click_buffer_size"`
+	// ClickFlushInterval is the maximum time a click event waits in the buffer.
+	// Example: "5s"
+	ClickFlushInterval Duration `json:"click_flush_interval"`
+	// IDGenerator selects how short URL IDs are generated:
+	// "random" - random base62 IDs, "sequence" - obfuscated numbers of a storage sequence.
+	IDGenerator string `json:"id_generator"`
+	// IDLength is the length of random IDs and the minimum length of sequence IDs.
+	IDLength int `json:"id_length"`
+	// IDSalt makes sequence IDs differ between installations, it should be kept secret.
+	IDSalt string `json:"id_salt"`
+	// IDMaxAttempts is the number of IDs generated before giving up if they are already taken.
+	IDMaxAttempts int `json:"id_max_attempts"`
+	// DeleteWorkers is the number of deletion jobs processed concurrently.
+	DeleteWorkers int `json:"delete_workers"`
+	// DeleteMaxAttempts is the number of attempts to delete a short URL before it is marked as failed.
+	DeleteMaxAttempts int `json:"delete_max_attempts"`
+	// DeleteRetryBackoff is the delay before the first retry of a failed deletion,
+	// it doubles with every next retry.
+	// Example: "1s"
+	DeleteRetryBackoff Duration `json:"delete_retry_backoff"`
+	// DeleteDrainTimeout limits waiting for running deletion jobs on shutdown.
+	// Unfinished jobs are resumed on the next start.
+	// Example: "30s"
+	DeleteDrainTimeout Duration `json:"delete_drain_timeout"`
+	// DeleteFlushInterval is the window deletions of different jobs are coalesced within
+	// before the storage is updated. Zero means every job is deleted right away.
+	// Example: "50ms"
+	DeleteFlushInterval Duration `json:"delete_flush_interval"`
+	// DeleteBatchSize is the number of coalesced short URLs the storage is updated with
+	// before the flush interval passes.
+	DeleteBatchSize int `json:"delete_batch_size"`
+	// DeleteRetention defines how long deleted short URLs can be restored.
+	// After that they are purged from the storage together with their analytics.
+	// Example: "720h"
+	DeleteRetention Duration `json:"delete_retention"`
+	// DeletePurgeInterval defines how often deleted short URLs past the retention are purged.
+	// A non-positive interval disables purging.
+	// Example: "1h"
+	DeletePurgeInterval Duration `json:"delete_purge_interval"`
+	// RedirectCacheSize is the maximum number of short URLs kept in the redirect cache.
+	// A negative value disables the cache.
+	RedirectCacheSize int `json:"redirect_cache_size"`
+	// RedirectCacheTTL defines how long a found short URL is served from the redirect cache.
+	// Example: "1m"
+	RedirectCacheTTL Duration `json:"redirect_cache_ttl"`
+	// RedirectCacheNegativeTTL defines how long an unknown short URL is remembered as not found.
+	// Example: "10s"
+	RedirectCacheNegativeTTL Duration `json:"redirect_cache_negative_ttl"`
+	// AllowedSchemes is a comma-separated list of the schemes original URLs may have.
+	// Example: "http,https"
+	AllowedSchemes string `json:"allowed_schemes"`
+	// MaxURLLength is the maximum length of a normalized original URL in bytes.
+	MaxURLLength int `json:"max_url_length"`
+	// RedirectCode is the HTTP status code of redirects of short URLs created without their own:
+	// 301, 302, 307 or 308.
+	RedirectCode int `json:"redirect_code"`
+	// PermanentRedirectMaxAge defines how long clients may cache permanent (301 and 308) redirects.
+	// Example: "720h"
+	PermanentRedirectMaxAge Duration `json:"permanent_redirect_max_age"`
+	// PasswordMaxAttempts is the number of wrong passwords a protected short URL is locked after.
+	PasswordMaxAttempts int `json:"password_max_attempts"`
+	// PasswordAttemptWindow defines how long wrong passwords of a protected short URL are counted
+	// and how long it stays locked.
+	// Example: "15m"
+	PasswordAttemptWindow Duration `json:"password_attempt_window"`
+	// InactiveResponse defines how short URLs which are not active yet are answered:
+	// "not-found", "page" or "redirect".
+	InactiveResponse string `json:"inactive_response"`
+	// InactivePage is the path to the HTML page served for short URLs which are not active yet
+	// with the "page" response. If empty, a built-in page is served.
+	// Example: "inactive.html"
+	InactivePage string `json:"inactive_page"`
+	// InactiveFallbackURL is the URL short URLs which are not active yet redirect to
+	// with the "redirect" response.
+	// Example: "https://example.com/coming-soon"
+	InactiveFallbackURL string `json:"inactive_fallback_url"`
 }
 
+// Duration is a time.Duration read from the configuration file as a string.
+// Example: "1m30s"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string like "1m30s".
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Storage types supported by the StorageType setting.
+const (
+	StorageMemory   = "memory"   // StorageMemory keeps URLs in memory only.
+	StorageFile     = "file"     // StorageFile keeps URLs in the file at FilePath.
+	StorageDatabase = "database" // StorageDatabase keeps URLs in the PostgreSQL database at DBPath.
+)
+
+// Deduplication policies supported by the DedupPolicy setting.
+const (
+	DedupGlobal  = "global"   // DedupGlobal returns the existing short URL shortened by any user.
+	DedupPerUser = "per-user" // DedupPerUser returns the existing short URL shortened by the same user.
+	DedupNone    = "none"     // DedupNone creates a new short URL every time.
+)
+
+// Expired URL policies supported by the ExpiredPolicy setting.
+const (
+	ExpiredPurge   = "purge"   // ExpiredPurge deletes expired URLs.
+	ExpiredArchive = "archive" // ExpiredArchive moves expired URLs to the archive.
+)
+
+// Responses to short URLs which are not active yet supported by the InactiveResponse setting.
+const (
+	InactiveNotFound = "not-found" // InactiveNotFound answers as if the short URL did not exist.
+	InactiveShowPage = "page"      // InactiveShowPage serves the page at InactivePage or a built-in one.
+	InactiveRedirect = "redirect"  // InactiveRedirect redirects to InactiveFallbackURL.
+)
+
+// ID generators supported by the IDGenerator setting.
+const (
+	IDRandom   = "random"   // IDRandom generates random base62 IDs.
+	IDSequence = "sequence" // IDSequence encodes numbers of a storage sequence.
+)
+
 // NewConfig initializes and returns a new coniguration instance.
 // It parses command-line flags and overrides them with environment variables if they are set.
 // The priority is:
@@ -60,6 +220,40 @@ type Config struct {
 //	ENABLE_HTTPS         Overrides the -s flag.
 //	TRUSTED_SUBNET       Overrides the -t flag.
 //	GRPC_ADDRESS       	 Overrides the -g flag.
+//	STORAGE_TYPE         Overrides the -storage flag.
+//	FILE_COMPACT_INTERVAL  Overrides the -file-compact-interval flag.
+//	STORAGE_READ_TIMEOUT   Overrides the -storage-read-timeout flag.
+//	STORAGE_WRITE_TIMEOUT  Overrides the -storage-write-timeout flag.
+//	DEDUP_POLICY         Overrides the -dedup flag.
+//	DEFAULT_TTL          Overrides the -default-ttl flag.
+//	EXPIRED_SWEEP_INTERVAL Overrides the -expired-sweep-interval flag.
+//	EXPIRED_POLICY       Overrides the -expired-policy flag.
+//	CLICK_BUFFER_SIZE    Overrides the -click-buffer-size flag.
+//	CLICK_FLUSH_INTERVAL Overrides the -click-flush-interval flag.
+//	ID_GENERATOR         Overrides the -id-generator flag.
+//	ID_LENGTH            Overrides the -id-length flag.
+//	ID_SALT              Overrides the -id-salt flag.
+//	ID_MAX_ATTEMPTS      Overrides the -id-max-attempts flag.
+//	DELETE_WORKERS       Overrides the -delete-workers flag.
+//	DELETE_MAX_ATTEMPTS  Overrides the -delete-max-attempts flag.
+//	DELETE_RETRY_BACKOFF Overrides the -delete-retry-backoff flag.
+//	DELETE_DRAIN_TIMEOUT Overrides the -delete-drain-timeout flag.
+//	DELETE_FLUSH_INTERVAL Overrides the -delete-flush-interval flag.
+//	DELETE_BATCH_SIZE    Overrides the -delete-batch-size flag.
+//	DELETE_RETENTION     Overrides the -delete-retention flag.
+//	DELETE_PURGE_INTERVAL Overrides the -delete-purge-interval flag.
+//	REDIRECT_CACHE_SIZE  Overrides the -redirect-cache-size flag.
+//	REDIRECT_CACHE_TTL   Overrides the -redirect-cache-ttl flag.
+//	REDIRECT_CACHE_NEGATIVE_TTL Overrides the -redirect-cache-negative-ttl flag.
+//	ALLOWED_SCHEMES      Overrides the -allowed-schemes flag.
+//	MAX_URL_LENGTH       Overrides the -max-url-length flag.
+//	REDIRECT_CODE        Overrides the -redirect-code flag.
+//	PERMANENT_REDIRECT_MAX_AGE Overrides the -permanent-redirect-max-age flag.
+//	PASSWORD_MAX_ATTEMPTS Overrides the -password-max-attempts flag.
+//	PASSWORD_ATTEMPT_WINDOW Overrides the -password-attempt-window flag.
+//	INACTIVE_RESPONSE    Overrides the -inactive-response flag.
+//	INACTIVE_PAGE        Overrides the -inactive-page flag.
+//	INACTIVE_FALLBACK_URL Overrides the -inactive-fallback-url flag.
 //
 // 2. Command-Line Flags:
 //
@@ -77,6 +271,74 @@ type Config struct {
 //	      Truted subnet address (default "")
 //	-g string
 //	      Address of the gRPC server (default "", gRPC disabled)
+//	-storage string
+//	      Storage type: memory, file or database (default "database" if -d is set, else "file")
+//	-file-compact-interval duration
+//	      File storage log compaction interval (default 10m)
+//	-storage-read-timeout duration
+//	      Timeout of a single storage read (default 5s)
+//	-storage-write-timeout duration
+//	      Timeout of a single storage write (default 10s)
+//	-dedup string
+//	      Deduplication policy: global, per-user or none (default "global")
+//	-default-ttl duration
+//	      Default lifetime of short URLs (default 0, never expire)
+//	-expired-sweep-interval duration
+//	      Interval between sweeps of expired URLs (default 1m)
+//	-expired-policy string
+//	      Expired URLs policy: purge or archive (default "purge")
+//	-click-buffer-size int
+//	      Number of buffered click events (default 1024)
+//	-click-flush-interval duration
+//	      Maximum time a click event waits to be saved (default 5s)
+//	-id-generator string
+//	      Short ID generator: random or sequence (default "random")
+//	-id-length int
+//	      Length of random IDs, minimum length of sequence IDs (default 8)
+//	-id-salt string
+//	      Salt of sequence IDs (default "")
+//	-id-max-attempts int
+//	      Number of IDs generated before giving up on collisions (default 5)
+//	-delete-workers int
+//	      Number of deletion jobs processed concurrently (default 5)
+//	-delete-max-attempts int
+//	      Number of attempts to delete a short URL (default 5)
+//	-delete-retry-backoff duration
+//	      Delay before the first retry of a failed deletion (default 1s)
+//	-delete-drain-timeout duration
+//	      Time to wait for running deletion jobs on shutdown (default 30s)
+//	-delete-flush-interval duration
+//	      Window deletions are coalesced within (default 50ms)
+//	-delete-batch-size int
+//	      Number of coalesced short URLs flushed early (default 1000)
+//	-delete-retention duration
+//	      Time deleted short URLs can be restored within (default 720h)
+//	-delete-purge-interval duration
+//	      Interval between purges of deleted short URLs (default 1h)
+//	-redirect-cache-size int
+//	      Number of short URLs kept in the redirect cache, negative disables it (default 10000)
+//	-redirect-cache-ttl duration
+//	      Time a found short URL is cached for (default 1m)
+//	-redirect-cache-negative-ttl duration
+//	      Time an unknown short URL is cached for (default 10s)
+//	-allowed-schemes string
+//	      Comma-separated schemes original URLs may have (default "http,https")
+//	-max-url-length int
+//	      Maximum length of an original URL in bytes (default 2048)
+//	-redirect-code int
+//	      Default redirect status code: 301, 302, 307 or 308 (default 307)
+//	-permanent-redirect-max-age duration
+//	      Time clients may cache permanent redirects for (default 720h)
+//	-password-max-attempts int
+//	      Number of wrong passwords a protected short URL is locked after (default 5)
+//	-password-attempt-window duration
+//	      Time wrong passwords are counted within (default 15m)
+//	-inactive-response string
+//	      Response to short URLs which are not active yet: not-found, page or redirect (default "not-found")
+//	-inactive-page string
+//	      HTML page served for short URLs which are not active yet
+//	-inactive-fallback-url string
+//	      URL short URLs which are not active yet redirect to
 //	-config string
 //	      Configuration file path
 //
@@ -96,6 +358,74 @@ type Config struct {
 //		  Analogue for environment variable TRUSTED_SUBNET and -t flag
 //	"grpc_address": string
 //		  Analogue for environment variable GRPC_ADDRESS and -g flag
+//	"storage_type": string
+//		  Analogue for environment variable STORAGE_TYPE and -storage flag
+//	"file_compact_interval": string
+//		  Analogue for environment variable FILE_COMPACT_INTERVAL and -file-compact-interval flag
+//	"storage_read_timeout": string
+//		  Analogue for environment variable STORAGE_READ_TIMEOUT and -storage-read-timeout flag
+//	"storage_write_timeout": string
+//		  Analogue for environment variable STORAGE_WRITE_TIMEOUT and -storage-write-timeout flag
+//	"dedup_policy": string
+//		  Analogue for environment variable DEDUP_POLICY and -dedup flag
+//	"default_ttl": string
+//		  Analogue for environment variable DEFAULT_TTL and -default-ttl flag
+//	"expired_sweep_interval": string
+//		  Analogue for environment variable EXPIRED_SWEEP_INTERVAL and -expired-sweep-interval flag
+//	"expired_policy": string
+//		  Analogue for environment variable EXPIRED_POLICY and -expired-policy flag
+//	"click_buffer_size": int
+//		  Analogue for environment variable CLICK_BUFFER_SIZE and -click-buffer-size flag
+//	"click_flush_interval": string
+//		  Analogue for environment variable CLICK_FLUSH_INTERVAL and -click-flush-interval flag
+//	"id_generator": string
+//		  Analogue for environment variable ID_GENERATOR and -id-generator flag
+//	"id_length": int
+//		  Analogue for environment variable ID_LENGTH and -id-length flag
+//	"id_salt": string
+//		  Analogue for environment variable ID_SALT and -id-salt flag
+//	"id_max_attempts": int
+//		  Analogue for environment variable ID_MAX_ATTEMPTS and -id-max-attempts flag
+//	"delete_workers": int
+//		  Analogue for environment variable DELETE_WORKERS and -delete-workers flag
+//	"delete_max_attempts": int
+//		  Analogue for environment variable DELETE_MAX_ATTEMPTS and -delete-max-attempts flag
+//	"delete_retry_backoff": string
+//		  Analogue for environment variable DELETE_RETRY_BACKOFF and -delete-retry-backoff flag
+//	"delete_drain_timeout": string
+//		  Analogue for environment variable DELETE_DRAIN_TIMEOUT and -delete-drain-timeout flag
+//	"delete_flush_interval": string
+//		  Analogue for environment variable DELETE_FLUSH_INTERVAL and -delete-flush-interval flag
+//	"delete_batch_size": int
+//		  Analogue for environment variable DELETE_BATCH_SIZE and -delete-batch-size flag
+//	"delete_retention": string
+//		  Analogue for environment variable DELETE_RETENTION and -delete-retention flag
+//	"delete_purge_interval": string
+//		  Analogue for environment variable DELETE_PURGE_INTERVAL and -delete-purge-interval flag
+//	"redirect_cache_size": int
+//		  Analogue for environment variable REDIRECT_CACHE_SIZE and -redirect-cache-size flag
+//	"redirect_cache_ttl": string
+//		  Analogue for environment variable REDIRECT_CACHE_TTL and -redirect-cache-ttl flag
+//	"redirect_cache_negative_ttl": string
+//		  Analogue for environment variable REDIRECT_CACHE_NEGATIVE_TTL and -redirect-cache-negative-ttl flag
+//	"allowed_schemes": string
+//		  Analogue for environment variable ALLOWED_SCHEMES and -allowed-schemes flag
+//	"max_url_length": int
+//		  Analogue for environment variable MAX_URL_LENGTH and -max-url-length flag
+//	"redirect_code": int
+//		  Analogue for environment variable REDIRECT_CODE and -redirect-code flag
+//	"permanent_redirect_max_age": string
+//		  Analogue for environment variable PERMANENT_REDIRECT_MAX_AGE and -permanent-redirect-max-age flag
+//	"password_max_attempts": int
+//		  Analogue for environment variable PASSWORD_MAX_ATTEMPTS and -password-max-attempts flag
+//	"password_attempt_window": string
+//		  Analogue for environment variable PASSWORD_ATTEMPT_WINDOW and -password-attempt-window flag
+//	"inactive_response": string
+//		  Analogue for environment variable INACTIVE_RESPONSE and -inactive-response flag
+//	"inactive_page": string
+//		  Analogue for environment variable INACTIVE_PAGE and -inactive-page flag
+//	"inactive_fallback_url": string
+//		  Analogue for environment variable INACTIVE_FALLBACK_URL and -inactive-fallback-url flag
 //
 // 4. Default Values:
 //
@@ -105,7 +435,41 @@ type Config struct {
 //	DBPath:      	"",
 //	EnableHTTPS: 	false,
 //	TrustedSubnet:  "",
-//	GRPCAddress:    ""
+//	GRPCAddress:    "",
+//	StorageType:    "database" if DBPath is set, else "file",
+//	FileCompactInterval: 10m,
+//	StorageReadTimeout:  5s,
+//	StorageWriteTimeout: 10s,
+//	DedupPolicy:    "global",
+//	DefaultTTL:     0,
+//	ExpiredSweepInterval: 1m,
+//	ExpiredPolicy:  "purge",
+//	ClickBufferSize:    1024,
+//	ClickFlushInterval: 5s,
+//	IDGenerator:    "random",
+//	IDLength:       8,
+//	IDSalt:         "",
+//	IDMaxAttempts:  5,
+//	DeleteWorkers:      5,
+//	DeleteMaxAttempts:  5,
+//	DeleteRetryBackoff: 1s,
+//	DeleteDrainTimeout: 30s,
+//	DeleteFlushInterval: 50ms,
+//	DeleteBatchSize:    1000,
+//	DeleteRetention:    720h,
+//	DeletePurgeInterval: 1h,
+//	RedirectCacheSize:  10000,
+//	RedirectCacheTTL:   1m,
+//	RedirectCacheNegativeTTL: 10s,
+//	AllowedSchemes: "http,https",
+//	MaxURLLength:   2048,
+//	RedirectCode:   307,
+//	PermanentRedirectMaxAge: 720h,
+//	PasswordMaxAttempts:   5,
+//	PasswordAttemptWindow: 15m,
+//	InactiveResponse:    "not-found",
+//	InactivePage:        "",
+//	InactiveFallbackURL: ""
 func NewConfig() *Config {
 	cfg := &Config{}
 	// Specify default configuration values.
@@ -117,6 +481,51 @@ func NewConfig() *Config {
 		EnableHTTPS:   false,
 		TrustedSubnet: "",
 		GRPCAddress:   "",
+		StorageType:   "",
+
+		FileCompactInterval: Duration(10 * time.Minute),
+		StorageReadTimeout:  Duration(5 * time.Second),
+		StorageWriteTimeout: Duration(10 * time.Second),
+		DedupPolicy:         DedupGlobal,
+
+		DefaultTTL:           0,
+		ExpiredSweepInterval: Duration(time.Minute),
+		ExpiredPolicy:        ExpiredPurge,
+
+		ClickBufferSize:    1024,
+		ClickFlushInterval: Duration(5 * time.Second),
+
+		IDGenerator:   IDRandom,
+		IDLength:      8,
+		IDSalt:        "",
+		IDMaxAttempts: 5,
+
+		DeleteWorkers:      5,
+		DeleteMaxAttempts:  5,
+		DeleteRetryBackoff: Duration(time.Second),
+		DeleteDrainTimeout: Duration(30 * time.Second),
+
+		DeleteFlushInterval: Duration(50 * time.Millisecond),
+		DeleteBatchSize:     1000,
+		DeleteRetention:     Duration(720 * time.Hour),
+		DeletePurgeInterval: Duration(time.Hour),
+
+		RedirectCacheSize:        10000,
+		RedirectCacheTTL:         Duration(time.Minute),
+		RedirectCacheNegativeTTL: Duration(10 * time.Second),
+
+		AllowedSchemes: "http,https",
+		MaxURLLength:   2048,
+
+		RedirectCode:            307,
+		PermanentRedirectMaxAge: Duration(720 * time.Hour),
+
+		PasswordMaxAttempts:   5,
+		PasswordAttemptWindow: Duration(15 * time.Minute),
+
+		InactiveResponse:    InactiveNotFound,
+		InactivePage:        "",
+		InactiveFallbackURL: "",
 	}
 
 	// Define command-line flags and associate them with Config fields.
@@ -127,6 +536,40 @@ func NewConfig() *Config {
 	flag.BoolVar(&cfg.EnableHTTPS, "s", false, "Connection type")
 	flag.StringVar(&cfg.TrustedSubnet, "t", "", "Trusted subnet CIDR")
 	flag.StringVar(&cfg.GRPCAddress, "g", "", "Address of the gRPC server")
+	flag.StringVar(&cfg.StorageType, "storage", "", "Storage type: memory, file or database")
+	flag.DurationVar((*time.Duration)(&cfg.FileCompactInterval), "file-compact-interval", 0, "File storage log compaction interval")
+	flag.DurationVar((*time.Duration)(&cfg.StorageReadTimeout), "storage-read-timeout", 0, "Timeout of a single storage read")
+	flag.DurationVar((*time.Duration)(&cfg.StorageWriteTimeout), "storage-write-timeout", 0, "Timeout of a single storage write")
+	flag.StringVar(&cfg.DedupPolicy, "dedup", "", "Deduplication policy: global, per-user or none")
+	flag.DurationVar((*time.Duration)(&cfg.DefaultTTL), "default-ttl", 0, "Default lifetime of short URLs")
+	flag.DurationVar((*time.Duration)(&cfg.ExpiredSweepInterval), "expired-sweep-interval", 0, "Interval between sweeps of expired URLs")
+	flag.StringVar(&cfg.ExpiredPolicy, "expired-policy", "", "Expired URLs policy: purge or archive")
+	flag.IntVar(&cfg.ClickBufferSize, "click-buffer-size", 0, "Number of buffered click events")
+	flag.DurationVar((*time.Duration)(&cfg.ClickFlushInterval), "click-flush-interval", 0, "Maximum time a click event waits to be saved")
+	flag.StringVar(&cfg.IDGenerator, "id-generator", "", "Short ID generator: random or sequence")
+	flag.IntVar(&cfg.IDLength, "id-length", 0, "Length of random IDs, minimum length of sequence IDs")
+	flag.StringVar(&cfg.IDSalt, "id-salt", "", "Salt of sequence IDs")
+	flag.IntVar(&cfg.IDMaxAttempts, "id-max-attempts", 0, "Number of IDs generated before giving up on collisions")
+	flag.IntVar(&cfg.DeleteWorkers, "delete-workers", 0, "Number of deletion jobs processed concurrently")
+	flag.IntVar(&cfg.DeleteMaxAttempts, "delete-max-attempts", 0, "Number of attempts to delete a short URL")
+	flag.DurationVar((*time.Duration)(&cfg.DeleteRetryBackoff), "delete-retry-backoff", 0, "Delay before the first retry of a failed deletion")
+	flag.DurationVar((*time.Duration)(&cfg.DeleteDrainTimeout), "delete-drain-timeout", 0, "Time to wait for running deletion jobs on shutdown")
+	flag.DurationVar((*time.Duration)(&cfg.DeleteFlushInterval), "delete-flush-interval", 0, "Window deletions are coalesced within")
+	flag.IntVar(&cfg.DeleteBatchSize, "delete-batch-size", 0, "Number of coalesced short URLs flushed early")
+	flag.DurationVar((*time.Duration)(&cfg.DeleteRetention), "delete-retention", 0, "Time deleted short URLs can be restored within")
+	flag.DurationVar((*time.Duration)(&cfg.DeletePurgeInterval), "delete-purge-interval", 0, "Interval between purges of deleted short URLs")
+	flag.IntVar(&cfg.RedirectCacheSize, "redirect-cache-size", 0, "Number of short URLs kept in the redirect cache, negative disables it")
+	flag.DurationVar((*time.Duration)(&cfg.RedirectCacheTTL), "redirect-cache-ttl", 0, "Time a found short URL is cached for")
+	flag.DurationVar((*time.Duration)(&cfg.RedirectCacheNegativeTTL), "redirect-cache-negative-ttl", 0, "Time an unknown short URL is cached for")
+	flag.StringVar(&cfg.AllowedSchemes, "allowed-schemes", "", "Comma-separated schemes original URLs may have")
+	flag.IntVar(&cfg.MaxURLLength, "max-url-length", 0, "Maximum length of an original URL in bytes")
+	flag.IntVar(&cfg.RedirectCode, "redirect-code", 0, "Default redirect status code: 301, 302, 307 or 308")
+	flag.DurationVar((*time.Duration)(&cfg.PermanentRedirectMaxAge), "permanent-redirect-max-age", 0, "Time clients may cache permanent redirects for")
+	flag.IntVar(&cfg.PasswordMaxAttempts, "password-max-attempts", 0, "Number of wrong passwords a protected short URL is locked after")
+	flag.DurationVar((*time.Duration)(&cfg.PasswordAttemptWindow), "password-attempt-window", 0, "Time wrong passwords are counted within")
+	flag.StringVar(&cfg.InactiveResponse, "inactive-response", "", "Response to short URLs which are not active yet: not-found, page or redirect")
+	flag.StringVar(&cfg.InactivePage, "inactive-page", "", "HTML page served for short URLs which are not active yet")
+	flag.StringVar(&cfg.InactiveFallbackURL, "inactive-fallback-url", "", "URL short URLs which are not active yet redirect to")
 
 	var configPath string
 	flag.StringVar(&configPath, "config", "", "Path to configuration file")
@@ -202,6 +645,252 @@ func NewConfig() *Config {
 		cfg.GRPCAddress = currentCfg.GRPCAddress
 	}
 
+	// Override StorageType with the STORAGE_TYPE environment variable if set.
+	if storageType := os.Getenv("STORAGE_TYPE"); storageType != "" {
+		cfg.StorageType = storageType
+	} else if cfg.StorageType == "" {
+		cfg.StorageType = currentCfg.StorageType
+	}
+	if cfg.StorageType == "" {
+		// Derive StorageType from DBPath if not set yet.
+		if cfg.DBPath != "" {
+			cfg.StorageType = StorageDatabase
+		} else {
+			cfg.StorageType = StorageFile
+		}
+	}
+
+	// Override FileCompactInterval with the FILE_COMPACT_INTERVAL environment variable if set.
+	if interval, err := time.ParseDuration(os.Getenv("FILE_COMPACT_INTERVAL")); err == nil {
+		cfg.FileCompactInterval = Duration(interval)
+	} else if cfg.FileCompactInterval == 0 {
+		cfg.FileCompactInterval = currentCfg.FileCompactInterval
+	}
+
+	// Override StorageReadTimeout with the STORAGE_READ_TIMEOUT environment variable if set.
+	if timeout, err := time.ParseDuration(os.Getenv("STORAGE_READ_TIMEOUT")); err == nil {
+		cfg.StorageReadTimeout = Duration(timeout)
+	} else if cfg.StorageReadTimeout == 0 {
+		cfg.StorageReadTimeout = currentCfg.StorageReadTimeout
+	}
+
+	// Override StorageWriteTimeout with the STORAGE_WRITE_TIMEOUT environment variable if set.
+	if timeout, err := time.ParseDuration(os.Getenv("STORAGE_WRITE_TIMEOUT")); err == nil {
+		cfg.StorageWriteTimeout = Duration(timeout)
+	} else if cfg.StorageWriteTimeout == 0 {
+		cfg.StorageWriteTimeout = currentCfg.StorageWriteTimeout
+	}
+
+	// Override DedupPolicy with the DEDUP_POLICY environment variable if set.
+	if dedupPolicy := os.Getenv("DEDUP_POLICY"); dedupPolicy != "" {
+		cfg.DedupPolicy = dedupPolicy
+	} else if cfg.DedupPolicy == "" {
+		cfg.DedupPolicy = currentCfg.DedupPolicy
+	}
+
+	// Override DefaultTTL with the DEFAULT_TTL environment variable if set.
+	if ttl, err := time.ParseDuration(os.Getenv("DEFAULT_TTL")); err == nil {
+		cfg.DefaultTTL = Duration(ttl)
+	} else if cfg.DefaultTTL == 0 {
+		cfg.DefaultTTL = currentCfg.DefaultTTL
+	}
+
+	// Override ExpiredSweepInterval with the EXPIRED_SWEEP_INTERVAL environment variable if set.
+	if interval, err := time.ParseDuration(os.Getenv("EXPIRED_SWEEP_INTERVAL")); err == nil {
+		cfg.ExpiredSweepInterval = Duration(interval)
+	} else if cfg.ExpiredSweepInterval == 0 {
+		cfg.ExpiredSweepInterval = currentCfg.ExpiredSweepInterval
+	}
+
+	// Override ExpiredPolicy with the EXPIRED_POLICY environment variable if set.
+	if expiredPolicy := os.Getenv("EXPIRED_POLICY"); expiredPolicy != "" {
+		cfg.ExpiredPolicy = expiredPolicy
+	} else if cfg.ExpiredPolicy == "" {
+		cfg.ExpiredPolicy = currentCfg.ExpiredPolicy
+	}
+
+	// Override ClickBufferSize with the CLICK_BUFFER_SIZE environment variable if set.
+	if size, err := strconv.Atoi(os.Getenv("CLICK_BUFFER_SIZE")); err == nil {
+		cfg.ClickBufferSize = size
+	} else if cfg.ClickBufferSize == 0 {
+		cfg.ClickBufferSize = currentCfg.ClickBufferSize
+	}
+
+	// Override ClickFlushInterval with the CLICK_FLUSH_INTERVAL environment variable if set.
+	if interval, err := time.ParseDuration(os.Getenv("CLICK_FLUSH_INTERVAL")); err == nil {
+		cfg.ClickFlushInterval = Duration(interval)
+	} else if cfg.ClickFlushInterval == 0 {
+		cfg.ClickFlushInterval = currentCfg.ClickFlushInterval
+	}
+
+	// Override IDGenerator with the ID_GENERATOR environment variable if set.
+	if idGenerator := os.Getenv("ID_GENERATOR"); idGenerator != "" {
+		cfg.IDGenerator = idGenerator
+	} else if cfg.IDGenerator == "" {
+		cfg.IDGenerator = currentCfg.IDGenerator
+	}
+
+	// Override IDLength with the ID_LENGTH environment variable if set.
+	if length, err := strconv.Atoi(os.Getenv("ID_LENGTH")); err == nil {
+		cfg.IDLength = length
+	} else if cfg.IDLength == 0 {
+		cfg.IDLength = currentCfg.IDLength
+	}
+
+	// Override IDSalt with the ID_SALT environment variable if set.
+	if idSalt := os.Getenv("ID_SALT"); idSalt != "" {
+		cfg.IDSalt = idSalt
+	} else if cfg.IDSalt == "" {
+		cfg.IDSalt = currentCfg.IDSalt
+	}
+
+	// Override IDMaxAttempts with the ID_MAX_ATTEMPTS environment variable if set.
+	if attempts, err := strconv.Atoi(os.Getenv("ID_MAX_ATTEMPTS")); err == nil {
+		cfg.IDMaxAttempts = attempts
+	} else if cfg.IDMaxAttempts == 0 {
+		cfg.IDMaxAttempts = currentCfg.IDMaxAttempts
+	}
+
+	// Override DeleteWorkers with the DELETE_WORKERS environment variable if set.
+	if workers, err := strconv.Atoi(os.Getenv("DELETE_WORKERS")); err == nil {
+		cfg.DeleteWorkers = workers
+	} else if cfg.DeleteWorkers == 0 {
+		cfg.DeleteWorkers = currentCfg.DeleteWorkers
+	}
+
+	// Override DeleteMaxAttempts with the DELETE_MAX_ATTEMPTS environment variable if set.
+	if attempts, err := strconv.Atoi(os.Getenv("DELETE_MAX_ATTEMPTS")); err == nil {
+		cfg.DeleteMaxAttempts = attempts
+	} else if cfg.DeleteMaxAttempts == 0 {
+		cfg.DeleteMaxAttempts = currentCfg.DeleteMaxAttempts
+	}
+
+	// Override DeleteRetryBackoff with the DELETE_RETRY_BACKOFF environment variable if set.
+	if backoff, err := time.ParseDuration(os.Getenv("DELETE_RETRY_BACKOFF")); err == nil {
+		cfg.DeleteRetryBackoff = Duration(backoff)
+	} else if cfg.DeleteRetryBackoff == 0 {
+		cfg.DeleteRetryBackoff = currentCfg.DeleteRetryBackoff
+	}
+
+	// Override DeleteDrainTimeout with the DELETE_DRAIN_TIMEOUT environment variable if set.
+	if timeout, err := time.ParseDuration(os.Getenv("DELETE_DRAIN_TIMEOUT")); err == nil {
+		cfg.DeleteDrainTimeout = Duration(timeout)
+	} else if cfg.DeleteDrainTimeout == 0 {
+		cfg.DeleteDrainTimeout = currentCfg.DeleteDrainTimeout
+	}
+
+	// Override DeleteFlushInterval with the DELETE_FLUSH_INTERVAL environment variable if set.
+	if interval, err := time.ParseDuration(os.Getenv("DELETE_FLUSH_INTERVAL")); err == nil {
+		cfg.DeleteFlushInterval = Duration(interval)
+	} else if cfg.DeleteFlushInterval == 0 {
+		cfg.DeleteFlushInterval = currentCfg.DeleteFlushInterval
+	}
+
+	// Override DeleteBatchSize with the DELETE_BATCH_SIZE environment variable if set.
+	if size, err := strconv.Atoi(os.Getenv("DELETE_BATCH_SIZE")); err == nil {
+		cfg.DeleteBatchSize = size
+	} else if cfg.DeleteBatchSize == 0 {
+		cfg.DeleteBatchSize = currentCfg.DeleteBatchSize
+	}
+
+	// Override DeleteRetention with the DELETE_RETENTION environment variable if set.
+	if retention, err := time.ParseDuration(os.Getenv("DELETE_RETENTION")); err == nil {
+		cfg.DeleteRetention = Duration(retention)
+	} else if cfg.DeleteRetention == 0 {
+		cfg.DeleteRetention = currentCfg.DeleteRetention
+	}
+
+	// Override DeletePurgeInterval with the DELETE_PURGE_INTERVAL environment variable if set.
+	if interval, err := time.ParseDuration(os.Getenv("DELETE_PURGE_INTERVAL")); err == nil {
+		cfg.DeletePurgeInterval = Duration(interval)
+	} else if cfg.DeletePurgeInterval == 0 {
+		cfg.DeletePurgeInterval = currentCfg.DeletePurgeInterval
+	}
+
+	// Override RedirectCacheSize with the REDIRECT_CACHE_SIZE environment variable if set.
+	if size, err := strconv.Atoi(os.Getenv("REDIRECT_CACHE_SIZE")); err == nil {
+		cfg.RedirectCacheSize = size
+	} else if cfg.RedirectCacheSize == 0 {
+		cfg.RedirectCacheSize = currentCfg.RedirectCacheSize
+	}
+
+	// Override RedirectCacheTTL with the REDIRECT_CACHE_TTL environment variable if set.
+	if ttl, err := time.ParseDuration(os.Getenv("REDIRECT_CACHE_TTL")); err == nil {
+		cfg.RedirectCacheTTL = Duration(ttl)
+	} else if cfg.RedirectCacheTTL == 0 {
+		cfg.RedirectCacheTTL = currentCfg.RedirectCacheTTL
+	}
+
+	// Override RedirectCacheNegativeTTL with the REDIRECT_CACHE_NEGATIVE_TTL environment variable if set.
+	if ttl, err := time.ParseDuration(os.Getenv("REDIRECT_CACHE_NEGATIVE_TTL")); err == nil {
+		cfg.RedirectCacheNegativeTTL = Duration(ttl)
+	} else if cfg.RedirectCacheNegativeTTL == 0 {
+		cfg.RedirectCacheNegativeTTL = currentCfg.RedirectCacheNegativeTTL
+	}
+
+	// Override AllowedSchemes with the ALLOWED_SCHEMES environment variable if set.
+	if schemes := os.Getenv("ALLOWED_SCHEMES"); schemes != "" {
+		cfg.AllowedSchemes = schemes
+	} else if cfg.AllowedSchemes == "" {
+		cfg.AllowedSchemes = currentCfg.AllowedSchemes
+	}
+
+	// Override MaxURLLength with the MAX_URL_LENGTH environment variable if set.
+	if length, err := strconv.Atoi(os.Getenv("MAX_URL_LENGTH")); err == nil {
+		cfg.MaxURLLength = length
+	} else if cfg.MaxURLLength == 0 {
+		cfg.MaxURLLength = currentCfg.MaxURLLength
+	}
+
+	// Override RedirectCode with the REDIRECT_CODE environment variable if set.
+	if code, err := strconv.Atoi(os.Getenv("REDIRECT_CODE")); err == nil {
+		cfg.RedirectCode = code
+	} else if cfg.RedirectCode == 0 {
+		cfg.RedirectCode = currentCfg.RedirectCode
+	}
+
+	// Override PermanentRedirectMaxAge with the PERMANENT_REDIRECT_MAX_AGE environment variable if set.
+	if maxAge, err := time.ParseDuration(os.Getenv("PERMANENT_REDIRECT_MAX_AGE")); err == nil {
+		cfg.PermanentRedirectMaxAge = Duration(maxAge)
+	} else if cfg.PermanentRedirectMaxAge == 0 {
+		cfg.PermanentRedirectMaxAge = currentCfg.PermanentRedirectMaxAge
+	}
+
+	// Override PasswordMaxAttempts with the PASSWORD_MAX_ATTEMPTS environment variable if set.
+	if attempts, err := strconv.Atoi(os.Getenv("PASSWORD_MAX_ATTEMPTS")); err == nil {
+		cfg.PasswordMaxAttempts = attempts
+	} else if cfg.PasswordMaxAttempts == 0 {
+		cfg.PasswordMaxAttempts = currentCfg.PasswordMaxAttempts
+	}
+
+	// Override PasswordAttemptWindow with the PASSWORD_ATTEMPT_WINDOW environment variable if set.
+	if window, err := time.ParseDuration(os.Getenv("PASSWORD_ATTEMPT_WINDOW")); err == nil {
+		cfg.PasswordAttemptWindow = Duration(window)
+	} else if cfg.PasswordAttemptWindow == 0 {
+		cfg.PasswordAttemptWindow = currentCfg.PasswordAttemptWindow
+	}
+
+	// Override InactiveResponse with the INACTIVE_RESPONSE environment variable if set.
+	if response := os.Getenv("INACTIVE_RESPONSE"); response != "" {
+		cfg.InactiveResponse = response
+	} else if cfg.InactiveResponse == "" {
+		cfg.InactiveResponse = currentCfg.InactiveResponse
+	}
+
+	// Override InactivePage with the INACTIVE_PAGE environment variable if set.
+	if page := os.Getenv("INACTIVE_PAGE"); page != "" {
+		cfg.InactivePage = page
+	} else if cfg.InactivePage == "" {
+		cfg.InactivePage = currentCfg.InactivePage
+	}
+
+	// Override InactiveFallbackURL with the INACTIVE_FALLBACK_URL environment variable if set.
+	if fallbackURL := os.Getenv("INACTIVE_FALLBACK_URL"); fallbackURL != "" {
+		cfg.InactiveFallbackURL = fallbackURL
+	} else if cfg.InactiveFallbackURL == "" {
+		cfg.InactiveFallbackURL = currentCfg.InactiveFallbackURL
+	}
+
 	return cfg
 }
 
@@ -1,192 +1,894 @@
 // Package database provides functionalities to interact with the PostgreSQL database.
-// It includes functions to create necessary tables, and methods to perform operations on URL records.
+// It includes versioned schema migrations embedded into the binary,
+// methods to perform operations on URL records, and a listener of the URL record changes
+// published through PostgreSQL notifications by all replicas sharing the database.
 package database
 
 import (
 	"context"
 	"errors"
-	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
 	"github.com/KirillZiborov/lnkshortener/internal/file"
 )
 
-// CreateURLTable initializes the 'urls' table in the PostgreSQL database if it does not already exist.
-// It defines the schema for storing shortened URLs, including indexes.
-//
-// Parameters:
-// - ctx: The context for managing cancellation and timeouts.
-// - db: The PostgreSQL connection pool.
-//
-// Returns:
-// - An error if the table creation fails; otherwise, nil.
-func CreateURLTable(ctx context.Context, db *pgxpool.Pool) error {
-	query := `
-    CREATE TABLE IF NOT EXISTS urls (
-        id SERIAL PRIMARY KEY,
-        short_url TEXT NOT NULL,
-        original_url TEXT NOT NULL,
-		user_id TEXT NOT NULL,
-		deleted BOOL NOT NULL
-    );
-	CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_original_url ON urls (original_url);
-    `
-	_, err := db.Exec(ctx, query)
-	if err != nil {
-		return fmt.Errorf("unable to create table: %w", err)
-	}
-	return nil
-}
+// tagsColumn selects the sorted tags of a URL record, an empty array if it has none.
+const tagsColumn = `ARRAY(SELECT tag FROM url_tags WHERE url_tags.short_url = urls.short_url ORDER BY tag)`
 
 // DBStore represents a database store for URL records.
 // It encapsulates the PostgreSQL connection pool to perform database operations.
 type DBStore struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	dedup string // dedup is the deduplication policy, one of config.Dedup* values.
 }
 
 // NewDBStore initializes and returns a pointer to a new instance of DBStore.
-func NewDBStore(db *pgxpool.Pool) *DBStore {
-	return &DBStore{db: db}
+// The dedup policy defines which original URLs SaveURLRecord reports as duplicates.
+func NewDBStore(db *pgxpool.Pool, dedup string) *DBStore {
+	return &DBStore{db: db, dedup: dedup}
 }
 
 // ErrorDuplicate is returned when attempting to insert a URL record that already exists.
 // It indicates that the original URL has already been shortened and stored.
 var ErrorDuplicate = errors.New("duplicate entry: URL already exists")
 
+// ErrorShortURLTaken is returned when attempting to insert a URL record with a short URL
+// that is already used by another record.
+var ErrorShortURLTaken = errors.New("duplicate entry: short URL already exists")
+
+// ErrorBatchRejected is returned when an atomic batch of URL records is not saved
+// because some of the records cannot be saved.
+var ErrorBatchRejected = errors.New("batch rejected: some records cannot be saved")
+
+// ErrorNotOwner is returned when attempting to change or inspect a URL record
+// that belongs to another user.
+var ErrorNotOwner = errors.New("URL belongs to another user")
+
+// ErrorDeleted is returned when attempting to change a URL record marked as deleted.
+var ErrorDeleted = errors.New("URL is deleted")
+
+// ErrorExhausted is returned when attempting to follow a URL record
+// which has already made the maximum number of redirects.
+var ErrorExhausted = errors.New("URL has reached its click limit")
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// saveBatchAttempts is the number of times SaveURLRecords runs its transaction
+// when a short URL is taken by a concurrent insert after the check.
+const saveBatchAttempts = 3
+
 // SaveURLRecord inserts a new URLRecord into the database.
-// If the original URL already exists, it retrieves and returns the existing short URL.
+// If the original URL is already shortened according to the deduplication policy,
+// it retrieves and returns the existing short URL. Deleted and expired URLs are not considered duplicates.
+// Records with user-chosen aliases skip deduplication,
+// but fail with ErrorShortURLTaken if the short URL is already used.
+// The record is assigned the UUID generated by the database and the creation time if it has none.
 //
 // Parameters:
+// - ctx: The context for managing cancellation and timeouts.
 // - urlRecord: A pointer to the URLRecord to be saved.
 //
 // Returns:
 // - The short URL string if the insertion is successful.
 // - An error if the insertion fails or if the URL already exists.
-func (store *DBStore) SaveURLRecord(urlRecord *file.URLRecord) (string, error) {
-	query := `INSERT INTO urls (short_url, original_url, user_id, deleted) 
-			  VALUES ($1, $2, $3, $4)
-			  ON CONFLICT (original_url) DO NOTHING`
-
-	c, err := store.db.Exec(context.Background(), query, urlRecord.ShortURL, urlRecord.OriginalURL, urlRecord.UserUUID, urlRecord.DeletedFlag)
+func (store *DBStore) SaveURLRecord(ctx context.Context, urlRecord *file.URLRecord) (string, error) {
+	var existingShortURL string
+	var id int64
 
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return "", err
+	if urlRecord.CreatedAt.IsZero() {
+		urlRecord.CreatedAt = time.Now()
 	}
+	err := pgx.BeginFunc(ctx, store.db, func(tx pgx.Tx) error {
+		if store.dedup != config.DedupNone && !urlRecord.Alias {
+			// Serialize saves of the same original URL until the transaction ends,
+			// so concurrent requests cannot both miss the duplicate.
+			_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, urlRecord.OriginalURL)
+			if err != nil {
+				return err
+			}
+
+			// Check if the short URL already exists for the given original URL.
+			existingShortURL, err = store.findShortURL(ctx, tx, urlRecord.OriginalURL, urlRecord.UserUUID)
+			if err == nil {
+				return ErrorDuplicate
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
 
-	// Check if the short URL already exists for the given original URL.
-	if c.RowsAffected() == 0 {
-		existingShortURL, err := store.GetShortURL(urlRecord.OriginalURL)
+		query := `INSERT INTO urls (short_url, original_url, user_id, deleted, alias, expires_at, created_at, title, notes,
+			  redirect_code, password_hash, max_clicks, not_before, not_after)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`
+		err := tx.QueryRow(ctx, query, urlRecord.ShortURL, urlRecord.OriginalURL, urlRecord.UserUUID,
+			urlRecord.DeletedFlag, urlRecord.Alias, urlRecord.ExpiresAt, urlRecord.CreatedAt,
+			urlRecord.Title, urlRecord.Notes, urlRecord.RedirectCode, urlRecord.PasswordHash, urlRecord.MaxClicks,
+			urlRecord.NotBefore, urlRecord.NotAfter).Scan(&id)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return "", err
+			return err
 		}
+		return insertTags(ctx, tx, []*file.URLRecord{urlRecord})
+	})
+
+	var pgErr *pgconn.PgError
+	if errors.Is(err, ErrorDuplicate) {
 		return existingShortURL, ErrorDuplicate
+	} else if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return "", ErrorShortURLTaken
+	} else if err != nil {
+		return "", err
 	}
 
+	urlRecord.UUID = strconv.FormatInt(id, 10)
 	return urlRecord.ShortURL, nil
 }
 
-// GetShortURL retrieves the short URL associated with the given original URL.
+// SaveURLRecords inserts a batch of URLRecords into the database within a single transaction
+// using the COPY protocol. Every record is checked the same way SaveURLRecord checks it:
+// a duplicate original URL results in the existing short URL and ErrorDuplicate,
+// a short URL that is already used results in ErrorShortURLTaken.
+// An original URL repeated within the batch is a duplicate of its first occurrence.
+// If a short URL is taken by a concurrent insert after the check, the transaction is run again,
+// so the taken short URL is reported in the results instead of failing the whole batch.
+// The saved records are assigned the UUIDs generated by the database and the creation time if they have none.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - records: The URLRecords to be saved.
+// - atomic: Whether nothing is saved if any short URL is already used.
+//
+// Returns:
+// - The result of every record in the order of the records.
+// - ErrorBatchRejected together with the results if the atomic batch is not saved,
+// or another error if the transaction fails.
+func (store *DBStore) SaveURLRecords(ctx context.Context, records []*file.URLRecord, atomic bool) ([]file.SaveResult, error) {
+	results := make([]file.SaveResult, len(records))
+
+	now := time.Now()
+	for _, rec := range records {
+		if rec.CreatedAt.IsZero() {
+			rec.CreatedAt = now
+		}
+	}
+	save := func(tx pgx.Tx) error {
+		existing, err := store.findShortURLs(ctx, tx, records)
+		if err != nil {
+			return err
+		}
+
+		shortURLs := make([]string, 0, len(records))
+		for _, rec := range records {
+			shortURLs = append(shortURLs, rec.ShortURL)
+		}
+		taken := make(map[string]bool, len(records))
+		rows, err := tx.Query(ctx, `SELECT short_url FROM urls WHERE short_url = ANY($1)`, shortURLs)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var shortURL string
+			if err := rows.Scan(&shortURL); err != nil {
+				rows.Close()
+				return err
+			}
+			taken[shortURL] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		rejected := false
+		saved := make([]*file.URLRecord, 0, len(records))
+		copyRows := make([][]any, 0, len(records))
+		for i, rec := range records {
+			key, dedup := store.dedupKey(rec)
+			if dedup {
+				if shortURL, ok := existing[key]; ok {
+					results[i] = file.SaveResult{ShortURL: shortURL, Err: ErrorDuplicate}
+					continue
+				}
+			}
+			if taken[rec.ShortURL] {
+				results[i] = file.SaveResult{Err: ErrorShortURLTaken}
+				rejected = true
+				continue
+			}
+
+			taken[rec.ShortURL] = true
+			if dedup {
+				existing[key] = rec.ShortURL
+			}
+			results[i] = file.SaveResult{ShortURL: rec.ShortURL}
+			saved = append(saved, rec)
+			copyRows = append(copyRows, []any{rec.ShortURL, rec.OriginalURL, rec.UserUUID,
+				rec.DeletedFlag, rec.Alias, rec.ExpiresAt, rec.CreatedAt, rec.Title, rec.Notes, rec.RedirectCode, rec.PasswordHash, rec.MaxClicks,
+				rec.NotBefore, rec.NotAfter})
+		}
+		if atomic && rejected {
+			return ErrorBatchRejected
+		}
+		if len(saved) == 0 {
+			return nil
+		}
+
+		_, err = tx.CopyFrom(ctx, pgx.Identifier{"urls"},
+			[]string{"short_url", "original_url", "user_id", "deleted", "alias", "expires_at", "created_at", "title", "notes", "redirect_code", "password_hash", "max_clicks",
+				"not_before", "not_after"},
+			pgx.CopyFromRows(copyRows))
+		if err != nil {
+			return err
+		}
+		if err := insertTags(ctx, tx, saved); err != nil {
+			return err
+		}
+
+		// COPY does not return the generated IDs, so they are read back.
+		savedURLs := make([]string, 0, len(saved))
+		for _, rec := range saved {
+			savedURLs = append(savedURLs, rec.ShortURL)
+		}
+		ids := make(map[string]int64, len(saved))
+		rows, err = tx.Query(ctx, `SELECT short_url, id FROM urls WHERE short_url = ANY($1)`, savedURLs)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var shortURL string
+			var id int64
+			if err := rows.Scan(&shortURL, &id); err != nil {
+				return err
+			}
+			ids[shortURL] = id
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, rec := range saved {
+			rec.UUID = strconv.FormatInt(ids[rec.ShortURL], 10)
+		}
+		return nil
+	}
+
+	var err error
+	var pgErr *pgconn.PgError
+	for attempt := 1; ; attempt++ {
+		err = pgx.BeginFunc(ctx, store.db, save)
+		// A short URL was taken by a concurrent insert after the check,
+		// the check of the next attempt sees it and reports it in the results.
+		if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation || attempt >= saveBatchAttempts {
+			break
+		}
+	}
+
+	if errors.Is(err, ErrorBatchRejected) {
+		return results, ErrorBatchRejected
+	} else if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return nil, ErrorShortURLTaken
+	} else if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// dedupKey returns the key the record is deduplicated by according to the deduplication policy
+// and false if the record skips deduplication.
+func (store *DBStore) dedupKey(rec *file.URLRecord) (string, bool) {
+	switch {
+	case store.dedup == config.DedupNone || rec.Alias:
+		return "", false
+	case store.dedup == config.DedupPerUser:
+		return rec.UserUUID + " " + rec.OriginalURL, true
+	default:
+		return rec.OriginalURL, true
+	}
+}
+
+// findShortURLs retrieves the short URLs of non-deleted, non-expired records
+// with the original URLs of the batch the same way findShortURL does.
+// Saves of the same original URLs are serialized until the transaction ends.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - tx: The transaction to run the query in.
+// - records: The records of the batch.
+//
+// Returns:
+// - The existing short URLs by the dedupKey of their records.
+// - An error if the query fails.
+func (store *DBStore) findShortURLs(ctx context.Context, tx pgx.Tx, records []*file.URLRecord) (map[string]string, error) {
+	existing := make(map[string]string)
+	if store.dedup == config.DedupNone {
+		return existing, nil
+	}
+
+	var originalURLs []string
+	for _, rec := range records {
+		if !rec.Alias {
+			originalURLs = append(originalURLs, rec.OriginalURL)
+		}
+	}
+	if len(originalURLs) == 0 {
+		return existing, nil
+	}
+
+	// Take the locks in a fixed order, so concurrent batches cannot deadlock.
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(h)
+		FROM (SELECT DISTINCT hashtext(u) AS h FROM unnest($1::text[]) AS u ORDER BY h) AS locks`, originalURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT DISTINCT ON (original_url) original_url, user_id, short_url FROM urls
+		  WHERE original_url = ANY($1) AND NOT deleted AND NOT alias AND (expires_at IS NULL OR expires_at > now())
+		  ORDER BY original_url, id`
+	if store.dedup == config.DedupPerUser {
+		query = `SELECT DISTINCT ON (original_url, user_id) original_url, user_id, short_url FROM urls
+		  WHERE original_url = ANY($1) AND NOT deleted AND NOT alias AND (expires_at IS NULL OR expires_at > now())
+		  ORDER BY original_url, user_id, id`
+	}
+	rows, err := tx.Query(ctx, query, originalURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rec := file.URLRecord{}
+		var shortURL string
+		if err := rows.Scan(&rec.OriginalURL, &rec.UserUUID, &shortURL); err != nil {
+			return nil, err
+		}
+		key, _ := store.dedupKey(&rec)
+		existing[key] = shortURL
+	}
+	return existing, rows.Err()
+}
+
+// findShortURL retrieves the short URL of a non-deleted, non-expired record with the given original URL.
+// With the per-user deduplication policy only the records of the given user are considered.
 //
 // Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - tx: The transaction to run the query in.
 // - originalURL: The original URL to look up.
+// - userID: The user ID saving the original URL.
 //
 // Returns:
 // - The corresponding short URL string if found.
-// - An error if the original URL does not exist or if the query fails.
-func (store *DBStore) GetShortURL(originalURL string) (string, error) {
+// - pgx.ErrNoRows if there is no such record or another error if the query fails.
+func (store *DBStore) findShortURL(ctx context.Context, tx pgx.Tx, originalURL, userID string) (string, error) {
 	var shortURL string
+	var err error
 
-	query := `SELECT short_url FROM urls WHERE original_url = $1`
-	err := store.db.QueryRow(context.Background(), query, originalURL).Scan(&shortURL)
+	if store.dedup == config.DedupPerUser {
+		query := `SELECT short_url FROM urls WHERE original_url = $1 AND user_id = $2
+			  AND NOT deleted AND NOT alias AND (expires_at IS NULL OR expires_at > now()) ORDER BY id LIMIT 1`
+		err = tx.QueryRow(ctx, query, originalURL, userID).Scan(&shortURL)
+	} else {
+		query := `SELECT short_url FROM urls WHERE original_url = $1
+			  AND NOT deleted AND NOT alias AND (expires_at IS NULL OR expires_at > now()) ORDER BY id LIMIT 1`
+		err = tx.QueryRow(ctx, query, originalURL).Scan(&shortURL)
+	}
 	if err != nil {
 		return "", err
 	}
 	return shortURL, nil
 }
 
-// GetOriginalURL retrieves the original URL and its deletion status based on the provided short URL.
+// insertTags inserts the tags of the records with a single statement.
+func insertTags(ctx context.Context, tx pgx.Tx, records []*file.URLRecord) error {
+	var shortURLs, userIDs, tags []string
+	for _, rec := range records {
+		for _, tag := range rec.Tags {
+			shortURLs = append(shortURLs, rec.ShortURL)
+			userIDs = append(userIDs, rec.UserUUID)
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO url_tags (short_url, user_id, tag)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::text[])`
+	_, err := tx.Exec(ctx, query, shortURLs, userIDs, tags)
+	return err
+}
+
+// GetURLRecord retrieves the URL record based on the provided short URL.
 //
 // Parameters:
+// - ctx: The context for managing cancellation and timeouts.
 // - shortURL: The short URL to look up.
 //
 // Returns:
-// - The corresponding original URL string.
-// - A boolean indicating whether the URL has been marked as deleted.
-// - An error if the short URL does not exist or if the query fails.
-func (store *DBStore) GetOriginalURL(shortURL string) (string, bool, error) {
-	var originalURL string
-	var deleted bool
+// - The URL record with the original URL, its deletion status and expiration time.
+// - os.ErrProcessDone if the short URL does not exist, as the other stores do,
+// or an error if the query fails.
+func (store *DBStore) GetURLRecord(ctx context.Context, shortURL string) (*file.URLRecord, error) {
+	rec := file.URLRecord{ShortURL: shortURL}
 
-	query := `SELECT original_url, deleted FROM urls WHERE short_url = $1`
-	err := store.db.QueryRow(context.Background(), query, shortURL).Scan(&originalURL, &deleted)
-	if err != nil {
-		return "", false, err
+	query := `SELECT original_url, user_id, deleted, deleted_at, alias, expires_at, created_at,
+		updated_at, last_accessed_at, title, notes, redirect_code, password_hash, max_clicks, used_clicks,
+		not_before, not_after, rules, ` + tagsColumn + ` FROM urls WHERE short_url = $1`
+	err := store.db.QueryRow(ctx, query, shortURL).Scan(&rec.OriginalURL, &rec.UserUUID,
+		&rec.DeletedFlag, &rec.DeletedAt, &rec.Alias, &rec.ExpiresAt, &rec.CreatedAt,
+		&rec.UpdatedAt, &rec.LastAccessedAt, &rec.Title, &rec.Notes, &rec.RedirectCode, &rec.PasswordHash, &rec.MaxClicks, &rec.UsedClicks,
+		&rec.NotBefore, &rec.NotAfter, &rec.Rules, &rec.Tags)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, os.ErrProcessDone
+	} else if err != nil {
+		return nil, err
 	}
-	return originalURL, deleted, nil
+	return &rec, nil
 }
 
-// GetUserURLs retrieves all URL records associated with a given user ID.
+// GetUserURLs retrieves a page of the URL records associated with a given user ID
+// which match the filters of the query, ordered by creation time and ID.
+// The page and the total number of matching records are read from the same snapshot.
 //
 // Parameters:
+// - ctx: The context for managing cancellation and timeouts.
 // - userID: The user ID whose URLs are to be retrieved.
+// - q: The filters, the order and the position of the page.
 //
 // Returns:
-// - A slice of URLRecord containing the user's URLs.
+// - The page of the user's URLs with the total number of matching URLs.
 // - An error if the query fails.
-func (store *DBStore) GetUserURLs(userID string) ([]file.URLRecord, error) {
-	var records []file.URLRecord
+func (store *DBStore) GetUserURLs(ctx context.Context, userID string, q file.URLQuery) (file.URLPage, error) {
+	args := []any{userID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	filter := "user_id = $1"
+	switch q.Status {
+	case file.URLStatusActive:
+		filter += " AND NOT deleted"
+	case file.URLStatusDeleted:
+		filter += " AND deleted"
+	}
+	if q.Contains != "" {
+		filter += " AND strpos(original_url, " + arg(q.Contains) + ") > 0"
+	}
+	if q.CreatedFrom != nil {
+		filter += " AND created_at >= " + arg(*q.CreatedFrom)
+	}
+	if q.CreatedTo != nil {
+		filter += " AND created_at < " + arg(*q.CreatedTo)
+	}
+	if q.Tag != "" {
+		filter += " AND short_url IN (SELECT short_url FROM url_tags WHERE user_id = $1 AND tag = " + arg(q.Tag) + ")"
+	}
+	countArgs := len(args)
+
+	order, after := "ASC", ">"
+	if q.Desc {
+		order, after = "DESC", "<"
+	}
+	query := `SELECT id, short_url, original_url, user_id, deleted, deleted_at, alias, expires_at, created_at,
+		updated_at, last_accessed_at, title, notes, redirect_code, password_hash, max_clicks, used_clicks,
+		not_before, not_after, rules, ` + tagsColumn + ` FROM urls WHERE ` + filter
+	if q.After != nil {
+		query += " AND (created_at, id) " + after + " (" + arg(q.After.CreatedAt) + ", " + arg(q.After.ID) + ")"
+	}
+	query += " ORDER BY created_at " + order + ", id " + order
+	if q.Limit > 0 {
+		// One more record tells whether there is a next page.
+		query += " LIMIT " + arg(q.Limit+1)
+	}
+
+	var page file.URLPage
+	err := pgx.BeginTxFunc(ctx, store.db, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `SELECT count(*) FROM urls WHERE `+filter, args[:countArgs]...).Scan(&page.Total)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		// Iterates through all found rows.
+		for rows.Next() {
+			var rec file.URLRecord
+			var id int64
+			err := rows.Scan(&id, &rec.ShortURL, &rec.OriginalURL, &rec.UserUUID, &rec.DeletedFlag,
+				&rec.DeletedAt, &rec.Alias, &rec.ExpiresAt, &rec.CreatedAt,
+				&rec.UpdatedAt, &rec.LastAccessedAt, &rec.Title, &rec.Notes, &rec.RedirectCode, &rec.PasswordHash, &rec.MaxClicks, &rec.UsedClicks,
+				&rec.NotBefore, &rec.NotAfter, &rec.Rules, &rec.Tags)
+			if err != nil {
+				return err
+			}
+			rec.UUID = strconv.FormatInt(id, 10)
+			page.Records = append(page.Records, rec)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return file.URLPage{}, err
+	}
+
+	if q.Limit > 0 && len(page.Records) > q.Limit {
+		page.Records = page.Records[:q.Limit]
+		next := page.Records[q.Limit-1].Position()
+		page.Next = &next
+	}
+	return page, nil
+}
+
+// BatchUpdateDeleteFlag marks multiple URL records of the user as deleted with a single statement.
+// The deletion time of already deleted records is kept.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - urlIDs: The short URL identifiers to be marked as deleted.
+// - userID: The user ID associated with the URLs.
+//
+// Returns:
+// - An error if the update operation fails.
+func (store *DBStore) BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error {
+	if len(urlIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE urls SET deleted = TRUE, deleted_at = now(), updated_at = now() WHERE short_url = ANY($1) AND user_id = $2 AND NOT deleted`
+	_, err := store.db.Exec(ctx, query, urlIDs, userID)
+	return err
+}
+
+// RestoreURLs clears the deletion mark of multiple URL records of the user with a single statement.
+// Records deleted before deletedSince, unknown short URLs and the ones belonging to other users are skipped.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - urlIDs: The short URLs of the records to be restored.
+// - userID: The user ID associated with the URL records.
+// - deletedSince: The earliest deletion time of the records that can be restored.
+//
+// Returns:
+// - The short URLs of the restored records.
+// - An error if the update operation fails.
+func (store *DBStore) RestoreURLs(ctx context.Context, urlIDs []string, userID string, deletedSince time.Time) ([]string, error) {
+	if len(urlIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `UPDATE urls SET deleted = FALSE, deleted_at = NULL, updated_at = now()
+		WHERE short_url = ANY($1) AND user_id = $2 AND deleted AND deleted_at >= $3
+		RETURNING short_url`
+	rows, err := store.db.Query(ctx, query, urlIDs, userID, deletedSince)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// PurgeDeleted removes the URL records deleted before the given time together with their clicks.
+// The revisions of the records are removed by the database as well.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - deletedBefore: The time the records must have been deleted before.
+//
+// Returns:
+// - A number of removed URL records.
+// - An error if the removal fails.
+func (store *DBStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := `
+	WITH purged AS (
+		DELETE FROM urls WHERE deleted AND deleted_at < $1 RETURNING short_url
+	), purged_clicks AS (
+		DELETE FROM clicks WHERE short_url IN (SELECT short_url FROM purged)
+	)
+	SELECT count(*) FROM purged`
+
+	var count int
+	if err := store.db.QueryRow(ctx, query, deletedBefore).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateOriginalURL changes the original URL of the user's short URL and records the revision
+// in the same transaction. The new original URL is checked for duplicates the way SaveURLRecord checks it,
+// unless the short URL is a user-chosen alias.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - originalURL: The new original URL.
+//
+// Returns:
+// - The recorded revision.
+// - os.ErrProcessDone if the short URL does not exist, ErrorNotOwner if it belongs to another user,
+// ErrorDeleted if it is deleted, ErrorDuplicate if the new original URL is already shortened,
+// or an error if the query fails.
+func (store *DBStore) UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*file.URLRevision, error) {
+	rev := file.URLRevision{ShortURL: shortURL, UserUUID: userID, NewURL: originalURL}
+
+	err := pgx.BeginFunc(ctx, store.db, func(tx pgx.Tx) error {
+		var owner string
+		var deleted, alias bool
+		query := `SELECT original_url, user_id, deleted, alias FROM urls WHERE short_url = $1 FOR UPDATE`
+		err := tx.QueryRow(ctx, query, shortURL).Scan(&rev.OldURL, &owner, &deleted, &alias)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return os.ErrProcessDone
+		} else if err != nil {
+			return err
+		}
+		switch {
+		case owner != userID:
+			return ErrorNotOwner
+		case deleted:
+			return ErrorDeleted
+		}
+
+		if store.dedup != config.DedupNone && !alias {
+			// Serialize with saves of the same original URL, as SaveURLRecord does.
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, originalURL); err != nil {
+				return err
+			}
+			existing, err := store.findShortURL(ctx, tx, originalURL, userID)
+			if err == nil && existing != shortURL {
+				return ErrorDuplicate
+			} else if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE urls SET original_url = $1, updated_at = now() WHERE short_url = $2`, originalURL, shortURL); err != nil {
+			return err
+		}
+		query = `INSERT INTO url_revisions (short_url, user_id, old_url, new_url)
+			VALUES ($1, $2, $3, $4) RETURNING changed_at`
+		return tx.QueryRow(ctx, query, shortURL, userID, rev.OldURL, rev.NewURL).Scan(&rev.ChangedAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetURLHistory retrieves the revisions of the user's short URL.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to look up.
+// - userID: The user ID requesting the history, it must own the short URL.
+//
+// Returns:
+// - The revisions in the order they were made.
+// - os.ErrProcessDone if the short URL does not exist, ErrorNotOwner if it belongs to another user,
+// or an error if the query fails.
+func (store *DBStore) GetURLHistory(ctx context.Context, shortURL, userID string) ([]file.URLRevision, error) {
+	var owner string
+	err := store.db.QueryRow(ctx, `SELECT user_id FROM urls WHERE short_url = $1`, shortURL).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, os.ErrProcessDone
+	} else if err != nil {
+		return nil, err
+	}
+	if owner != userID {
+		return nil, ErrorNotOwner
+	}
 
-	query := `SELECT short_url, original_url FROM urls WHERE user_id = $1`
-	rows, err := store.db.Query(context.Background(), query, userID)
+	query := `SELECT user_id, old_url, new_url, changed_at FROM url_revisions WHERE short_url = $1 ORDER BY id`
+	rows, err := store.db.Query(ctx, query, shortURL)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// Iterates through all found rows.
+	var revisions []file.URLRevision
 	for rows.Next() {
-		var shortURL, originalURL string
-
-		err := rows.Scan(&shortURL, &originalURL)
-		if err != nil {
+		rev := file.URLRevision{ShortURL: shortURL}
+		if err := rows.Scan(&rev.UserUUID, &rev.OldURL, &rev.NewURL, &rev.ChangedAt); err != nil {
 			return nil, err
 		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, rows.Err()
+}
 
-		records = append(records, file.URLRecord{
-			ShortURL:    shortURL,
-			OriginalURL: originalURL,
-		})
+// SetTags replaces the tags of the user's short URL within a single transaction
+// and records the time it is changed at.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be tagged.
+// - userID: The user ID tagging the short URL, it must own the short URL.
+// - tags: The new tags of the short URL.
+//
+// Returns:
+// - os.ErrProcessDone if the short URL does not exist, ErrorNotOwner if it belongs to another user,
+// or an error if the transaction fails.
+func (store *DBStore) SetTags(ctx context.Context, shortURL, userID string, tags []string) error {
+	return pgx.BeginFunc(ctx, store.db, func(tx pgx.Tx) error {
+		var owner string
+		err := tx.QueryRow(ctx, `SELECT user_id FROM urls WHERE short_url = $1 FOR UPDATE`, shortURL).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return os.ErrProcessDone
+		} else if err != nil {
+			return err
+		}
+		if owner != userID {
+			return ErrorNotOwner
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE urls SET updated_at = now() WHERE short_url = $1`, shortURL); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM url_tags WHERE short_url = $1`, shortURL); err != nil {
+			return err
+		}
+		return insertTags(ctx, tx, []*file.URLRecord{{ShortURL: shortURL, UserUUID: userID, Tags: tags}})
+	})
+}
+
+// SetActiveWindow changes the activation window of the user's short URL and records the time it is changed at.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - notBefore: The time the short URL becomes active at, nil if it is active right away.
+// - notAfter: The time the short URL stops being active at, nil if it stays active.
+//
+// Returns:
+// - os.ErrProcessDone if the short URL does not exist, ErrorNotOwner if it belongs to another user,
+// or an error if the query fails.
+func (store *DBStore) SetActiveWindow(ctx context.Context, shortURL, userID string, notBefore, notAfter *time.Time) error {
+	var owner string
+	err := store.db.QueryRow(ctx, `WITH target AS (SELECT user_id FROM urls WHERE short_url = $1),
+		updated AS (UPDATE urls SET not_before = $3, not_after = $4, updated_at = now()
+		  WHERE short_url = $1 AND user_id = $2 RETURNING user_id)
+		SELECT user_id FROM target`, shortURL, userID, notBefore, notAfter).Scan(&owner)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return os.ErrProcessDone
+	case err != nil:
+		return err
+	case owner != userID:
+		return ErrorNotOwner
 	}
-	return records, rows.Err()
+	return nil
 }
 
-// BatchUpdateDeleteFlag marks multiple URL records as deleted based on the provided short URL and user ID.
+// SetRules replaces the redirect rules of the user's short URL, they are stored as JSON.
+// The time the short URL is changed at is recorded.
 //
 // Parameters:
-// - urlID: The short URL identifier to be marked as deleted.
-// - userID: The user ID associated with the URL.
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - rules: The new redirect rules of the short URL, none to redirect every visitor to the original URL.
 //
 // Returns:
-// - An error if the update operation fails.
-func (store *DBStore) BatchUpdateDeleteFlag(urlID string, userID string) error {
-	query := `UPDATE urls SET deleted = TRUE WHERE short_url = $1 AND user_id = $2`
-	_, err := store.db.Exec(context.Background(), query, urlID, userID)
-	return err
+// - os.ErrProcessDone if the short URL does not exist, ErrorNotOwner if it belongs to another user,
+// or an error if the query fails.
+func (store *DBStore) SetRules(ctx context.Context, shortURL, userID string, rules []file.RedirectRule) error {
+	// Store no rules as NULL rather than a JSON null.
+	var value any
+	if len(rules) > 0 {
+		value = rules
+	}
+
+	var owner string
+	err := store.db.QueryRow(ctx, `WITH target AS (SELECT user_id FROM urls WHERE short_url = $1),
+		updated AS (UPDATE urls SET rules = $3, updated_at = now() WHERE short_url = $1 AND user_id = $2 RETURNING user_id)
+		SELECT user_id FROM target`, shortURL, userID, value).Scan(&owner)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return os.ErrProcessDone
+	case err != nil:
+		return err
+	case owner != userID:
+		return ErrorNotOwner
+	}
+	return nil
+}
+
+// UseClick counts a redirect of the short URL against its click limit.
+// The limit is checked and the redirect is counted by a single statement,
+// so concurrent redirects never exceed the limit. Redirects of short URLs without a limit are not counted.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL being followed.
+//
+// Returns:
+// - os.ErrProcessDone if the short URL does not exist, ErrorExhausted if the limit is already reached,
+// or an error if the query fails.
+func (store *DBStore) UseClick(ctx context.Context, shortURL string) error {
+	var usedClicks int
+	err := store.db.QueryRow(ctx, `UPDATE urls SET used_clicks = used_clicks + 1
+		WHERE short_url = $1 AND max_clicks > 0 AND used_clicks < max_clicks
+		RETURNING used_clicks`, shortURL).Scan(&usedClicks)
+	if err == nil {
+		return nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// Nothing is counted if the short URL is unknown, unlimited or exhausted.
+	// The number of used clicks never decreases, so a separate query tells them apart.
+	var maxClicks int
+	err = store.db.QueryRow(ctx, `SELECT max_clicks FROM urls WHERE short_url = $1`, shortURL).Scan(&maxClicks)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return os.ErrProcessDone
+	case err != nil:
+		return err
+	case maxClicks > 0:
+		return ErrorExhausted
+	}
+	return nil
+}
+
+// GetTagStats counts the short URLs of the user and their clicks by tags.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - userID: The user ID whose tags are counted.
+//
+// Returns:
+// - The statistics of every tag of the user ordered by tag.
+// - An error if the query fails.
+func (store *DBStore) GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error) {
+	query := `
+	SELECT t.tag, COUNT(DISTINCT t.short_url), COUNT(c.short_url)
+	FROM url_tags t
+	LEFT JOIN clicks c ON c.short_url = t.short_url
+	WHERE t.user_id = $1
+	GROUP BY t.tag
+	ORDER BY t.tag`
+	rows, err := store.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []clicks.TagStats{}
+	for rows.Next() {
+		var s clicks.TagStats
+		if err := rows.Scan(&s.Tag, &s.URLs, &s.Clicks); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
 }
 
 // GetURLsCount counts shortened URLs.
 //
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+//
 // Returns:
 // - A number of shortened URLs.
 // - An error if the query fails.
-func (store *DBStore) GetURLsCount() (int, error) {
+func (store *DBStore) GetURLsCount(ctx context.Context) (int, error) {
 	var count int
 
 	query := `SELECT COUNT(*) FROM urls`
-	err := store.db.QueryRow(context.Background(), query).Scan(&count)
+	err := store.db.QueryRow(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -195,16 +897,332 @@ func (store *DBStore) GetURLsCount() (int, error) {
 
 // GetUsersCount counts unique users.
 //
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+//
 // Returns:
 // - A number of unique users.
 // - An error if the query fails.
-func (store *DBStore) GetUsersCount() (int, error) {
+func (store *DBStore) GetUsersCount(ctx context.Context) (int, error) {
 	var count int
 
 	query := `SELECT COUNT(DISTINCT user_id) FROM urls`
-	err := store.db.QueryRow(context.Background(), query).Scan(&count)
+	err := store.db.QueryRow(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
 	return count, nil
 }
+
+// DeleteExpired removes URL records expired by the given time together with their clicks and revisions.
+// With the archive policy the records are moved to the urls_archive table in the same statement.
+// An archived row has the columns of the short URL the archive is looked up by and the record column
+// with the full row of urls as a JSON object, the "tags" key holds the tags of the short URL.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - now: The time to check expiration against.
+// - policy: One of config.Expired* values, whether the records are deleted or archived.
+//
+// Returns:
+// - A number of removed URL records.
+// - An error if the query fails.
+func (store *DBStore) DeleteExpired(ctx context.Context, now time.Time, policy string) (int, error) {
+	query := `
+	WITH expired AS (
+		DELETE FROM urls WHERE expires_at <= $1 RETURNING short_url
+	), expired_clicks AS (
+		DELETE FROM clicks WHERE short_url IN (SELECT short_url FROM expired)
+	)
+	SELECT count(*) FROM expired`
+	if policy == config.ExpiredArchive {
+		// Tags are removed by the cascade, the statement still reads them as they were before it.
+		query = `
+		WITH expired AS (
+			DELETE FROM urls WHERE expires_at <= $1 RETURNING *
+		), expired_clicks AS (
+			DELETE FROM clicks WHERE short_url IN (SELECT short_url FROM expired)
+		), archived AS (
+			INSERT INTO urls_archive (short_url, original_url, user_id, deleted, alias, expires_at, record)
+			SELECT e.short_url, e.original_url, e.user_id, e.deleted, e.alias, e.expires_at,
+				to_jsonb(e) || jsonb_build_object('tags', COALESCE(
+					(SELECT jsonb_agg(t.tag ORDER BY t.tag) FROM url_tags t WHERE t.short_url = e.short_url),
+					'[]'::jsonb))
+			FROM expired e
+			RETURNING 1
+		)
+		SELECT count(*) FROM archived`
+	}
+
+	var count int
+	if err := store.db.QueryRow(ctx, query, now).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// SaveClicks inserts a batch of click events with a single COPY
+// and moves the last access time of the clicked URLs forward within the same transaction.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - events: The click events to be saved.
+//
+// Returns:
+// - An error if the insertion fails.
+func (store *DBStore) SaveClicks(ctx context.Context, events []clicks.Click) error {
+	// The latest click of every short URL.
+	last := make(map[string]time.Time)
+	for _, c := range events {
+		if t, ok := last[c.ShortURL]; !ok || t.Before(c.Time) {
+			last[c.ShortURL] = c.Time
+		}
+	}
+	// Concurrent batches lock the rows in the same order.
+	shortURLs := make([]string, 0, len(last))
+	for shortURL := range last {
+		shortURLs = append(shortURLs, shortURL)
+	}
+	slices.Sort(shortURLs)
+	times := make([]time.Time, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		times = append(times, last[shortURL])
+	}
+
+	return pgx.BeginFunc(ctx, store.db, func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"clicks"},
+			[]string{"short_url", "clicked_at", "referrer", "user_agent", "ip"},
+			pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+				c := events[i]
+				return []any{c.ShortURL, c.Time, c.Referrer, c.UserAgent, c.IP}, nil
+			}),
+		)
+		if err != nil {
+			return err
+		}
+
+		query := `UPDATE urls SET last_accessed_at = c.clicked_at
+			FROM unnest($1::text[], $2::timestamptz[]) AS c(short_url, clicked_at)
+			WHERE urls.short_url = c.short_url
+			AND (urls.last_accessed_at IS NULL OR urls.last_accessed_at < c.clicked_at)`
+		_, err = tx.Exec(ctx, query, shortURLs, times)
+		return err
+	})
+}
+
+// GetClickStats counts the clicks of a short URL by buckets in UTC.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - q: The short URL, the period and the bucket size to aggregate clicks by.
+//
+// Returns:
+// - The total number of clicks and non-empty buckets in ascending order.
+// - An error if the query fails.
+func (store *DBStore) GetClickStats(ctx context.Context, q clicks.Query) (clicks.Stats, error) {
+	stats := clicks.Stats{Buckets: []clicks.Bucket{}}
+
+	// Zero bounds are passed as NULL and do not limit the period.
+	var from, to *time.Time
+	if !q.From.IsZero() {
+		from = &q.From
+	}
+	if !q.To.IsZero() {
+		to = &q.To
+	}
+	bucket := clicks.BucketDay
+	if q.Bucket == clicks.BucketHour {
+		bucket = clicks.BucketHour
+	}
+
+	query := `
+	SELECT date_trunc($2, clicked_at AT TIME ZONE 'UTC') AS bucket, COUNT(*)
+	FROM clicks
+	WHERE short_url = $1
+		AND ($3::timestamptz IS NULL OR clicked_at >= $3)
+		AND ($4::timestamptz IS NULL OR clicked_at < $4)
+	GROUP BY bucket
+	ORDER BY bucket`
+	rows, err := store.db.Query(ctx, query, q.ShortURL, bucket, from, to)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b clicks.Bucket
+		if err := rows.Scan(&b.Start, &b.Clicks); err != nil {
+			return stats, err
+		}
+		b.Start = b.Start.UTC()
+		stats.Total += b.Clicks
+		stats.Buckets = append(stats.Buckets, b)
+	}
+	return stats, rows.Err()
+}
+
+// NextSequence returns the next number of the short ID sequence.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+//
+// Returns:
+// - The next number of the sequence.
+// - An error if the query fails.
+func (store *DBStore) NextSequence(ctx context.Context) (int64, error) {
+	var n int64
+	err := store.db.QueryRow(ctx, `SELECT nextval('short_id_seq')`).Scan(&n)
+	return n, err
+}
+
+// SaveDeleteJob inserts the deletion job with its items in a single transaction.
+// The job is assigned the ID and the creation time generated by the database.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - job: A pointer to the job to be saved.
+//
+// Returns:
+// - An error if the insertion fails.
+func (store *DBStore) SaveDeleteJob(ctx context.Context, job *deletion.Job) error {
+	var id int64
+	var createdAt time.Time
+
+	err := pgx.BeginFunc(ctx, store.db, func(tx pgx.Tx) error {
+		query := `INSERT INTO delete_jobs (user_id) VALUES ($1) RETURNING id, created_at`
+		if err := tx.QueryRow(ctx, query, job.UserID).Scan(&id, &createdAt); err != nil {
+			return err
+		}
+
+		_, err := tx.CopyFrom(ctx,
+			pgx.Identifier{"delete_job_items"},
+			[]string{"job_id", "short_url", "status", "attempts", "error"},
+			pgx.CopyFromSlice(len(job.Items), func(i int) ([]any, error) {
+				item := job.Items[i]
+				return []any{id, item.ShortURL, string(item.Status), item.Attempts, item.Error}, nil
+			}),
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	job.ID = strconv.FormatInt(id, 10)
+	job.CreatedAt = createdAt.UTC()
+	return nil
+}
+
+// UpdateDeleteJobItems updates the statuses of the deletion job items with the same short URLs
+// with a single statement.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - jobID: The ID of the job the items belong to.
+// - items: The items with their new statuses.
+//
+// Returns:
+// - An error if the update fails.
+func (store *DBStore) UpdateDeleteJobItems(ctx context.Context, jobID string, items []deletion.Item) error {
+	id, err := strconv.ParseInt(jobID, 10, 64)
+	if err != nil || len(items) == 0 {
+		// Jobs with such IDs cannot exist.
+		return nil
+	}
+
+	shortURLs := make([]string, 0, len(items))
+	statuses := make([]string, 0, len(items))
+	attempts := make([]int32, 0, len(items))
+	errs := make([]string, 0, len(items))
+	for _, item := range items {
+		shortURLs = append(shortURLs, item.ShortURL)
+		statuses = append(statuses, string(item.Status))
+		attempts = append(attempts, int32(item.Attempts))
+		errs = append(errs, item.Error)
+	}
+
+	query := `
+	UPDATE delete_job_items i SET status = u.status, attempts = u.attempts, error = u.error
+	FROM unnest($2::text[], $3::text[], $4::int[], $5::text[]) AS u(short_url, status, attempts, error)
+	WHERE i.job_id = $1 AND i.short_url = u.short_url`
+	_, err = store.db.Exec(ctx, query, id, shortURLs, statuses, attempts, errs)
+	return err
+}
+
+// GetDeleteJob retrieves the deletion job with its items.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - id: The ID of the job.
+//
+// Returns:
+// - The job if found.
+// - os.ErrProcessDone if the job does not exist, as the other stores do,
+// or an error if the query fails.
+func (store *DBStore) GetDeleteJob(ctx context.Context, id string) (*deletion.Job, error) {
+	jobID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, os.ErrProcessDone
+	}
+
+	jobs, err := store.queryDeleteJobs(ctx, `j.id = $1`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, os.ErrProcessDone
+	}
+	return &jobs[0], nil
+}
+
+// PendingDeleteJobs retrieves the deletion jobs having pending items in creation order.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+//
+// Returns:
+// - The pending jobs with all their items.
+// - An error if the query fails.
+func (store *DBStore) PendingDeleteJobs(ctx context.Context) ([]deletion.Job, error) {
+	return store.queryDeleteJobs(ctx,
+		`j.id IN (SELECT job_id FROM delete_job_items WHERE status = $1)`, string(deletion.StatusPending))
+}
+
+// queryDeleteJobs retrieves the deletion jobs matching the condition with their items ordered by job ID.
+func (store *DBStore) queryDeleteJobs(ctx context.Context, where string, args ...any) ([]deletion.Job, error) {
+	query := `
+	SELECT j.id, j.user_id, j.created_at, i.short_url, i.status, i.attempts, i.error
+	FROM delete_jobs j
+	JOIN delete_job_items i ON i.job_id = j.id
+	WHERE ` + where + `
+	ORDER BY j.id, i.short_url`
+	rows, err := store.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []deletion.Job
+	for rows.Next() {
+		var id int64
+		var job deletion.Job
+		var item deletion.Item
+		var status string
+
+		err := rows.Scan(&id, &job.UserID, &job.CreatedAt, &item.ShortURL, &status, &item.Attempts, &item.Error)
+		if err != nil {
+			return nil, err
+		}
+		item.Status = deletion.Status(status)
+
+		// Rows of the same job are adjacent.
+		job.ID = strconv.FormatInt(id, 10)
+		if n := len(jobs); n == 0 || jobs[n-1].ID != job.ID {
+			job.CreatedAt = job.CreatedAt.UTC()
+			jobs = append(jobs, job)
+		}
+		jobs[len(jobs)-1].Items = append(jobs[len(jobs)-1].Items, item)
+	}
+	return jobs, rows.Err()
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// ChangesChannel is the PostgreSQL notification channel every change of a URL record is published to.
+// The notifications are sent by the trigger on the urls table, so changes made by any replica are seen.
+const ChangesChannel = "url_changes"
+
+// ChangeOp is the kind of a URL record change.
+type ChangeOp string
+
+// Kinds of changes delivered to subscribers.
+const (
+	ChangeCreated ChangeOp = "created" // ChangeCreated means a new URL record is saved.
+	ChangeUpdated ChangeOp = "updated" // ChangeUpdated means an existing URL record is changed.
+	ChangeDeleted ChangeOp = "deleted" // ChangeDeleted means a URL record is marked as deleted or removed.
+	// ChangeResync means changes may have been missed while the listener was disconnected,
+	// so subscribers must drop everything they derived from earlier changes.
+	ChangeResync ChangeOp = "resync"
+)
+
+// Change is a change of a single URL record, it has no short URL if Op is ChangeResync.
+type Change struct {
+	Op       ChangeOp `json:"op"`
+	ShortURL string   `json:"short_url"`
+}
+
+// Reconnection delays of the listener, the delay doubles after every failed attempt.
+const (
+	listenMinBackoff = 500 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+// listenConn is the part of *pgx.Conn the listener uses.
+type listenConn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
+// Listener receives URL record changes published by all replicas sharing the database
+// and delivers them to the subscribers inside the process.
+// It listens on a dedicated connection, which is re-established automatically if it is lost.
+// Every successful LISTEN after a failed attempt, including the failed first connects after startup,
+// is followed by a ChangeResync change.
+type Listener struct {
+	connect    func(ctx context.Context) (listenConn, error)
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu          sync.RWMutex
+	subscribers []func(Change)
+}
+
+// NewListener creates a listener of URL record changes.
+//
+// Parameters:
+// - dsn: The connection string of the database the listener connects to.
+//
+// Returns:
+// - A pointer to a Listener instance, it does not listen until Run is called.
+func NewListener(dsn string) *Listener {
+	return &Listener{
+		connect: func(ctx context.Context) (listenConn, error) {
+			return pgx.Connect(ctx, dsn)
+		},
+		minBackoff: listenMinBackoff,
+		maxBackoff: listenMaxBackoff,
+	}
+}
+
+// Subscribe adds a function called with every received change.
+// Subscribers are called one by one from the listening goroutine, so they must not block.
+func (l *Listener) Subscribe(fn func(Change)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, fn)
+}
+
+// Run listens for changes until the context is canceled, reconnecting with a growing delay on failures.
+//
+// Parameters:
+// - ctx: The context stopping the listener.
+func (l *Listener) Run(ctx context.Context) {
+	backoff := l.minBackoff
+	// Changes may have been missed since startup as soon as an attempt fails,
+	// even if the listener has never been subscribed yet.
+	failed := false
+	for {
+		err := l.listen(ctx, failed, func() {
+			backoff = l.minBackoff
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		failed = true
+		logging.Sugar.Warnw("Lost connection listening for URL changes", "error", err, "retry_in", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, l.maxBackoff)
+	}
+}
+
+// listen connects to the database and delivers changes until the connection fails.
+// After a failed attempt subscribers are told to resync once the listener is subscribed,
+// so no change made after the resync is missed. onListen is called when the listener is subscribed.
+func (l *Listener) listen(ctx context.Context, resync bool, onListen func()) error {
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+ChangesChannel); err != nil {
+		return err
+	}
+	onListen()
+	if resync {
+		logging.Sugar.Infow("Resumed listening for URL changes, resyncing")
+		l.publish(Change{Op: ChangeResync})
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		change, err := parseChange(n.Payload)
+		if err != nil {
+			logging.Sugar.Warnw("Skipping malformed URL change", "payload", n.Payload, "error", err)
+			continue
+		}
+		l.publish(change)
+	}
+}
+
+// publish delivers the change to all subscribers.
+func (l *Listener) publish(change Change) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, fn := range l.subscribers {
+		fn(change)
+	}
+}
+
+// errUnknownChange is returned when a notification payload has an unknown operation or no short URL.
+var errUnknownChange = errors.New("unknown URL change")
+
+// parseChange decodes the JSON payload of a notification sent by the urls table trigger.
+func parseChange(payload string) (Change, error) {
+	var change Change
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		return Change{}, err
+	}
+	switch change.Op {
+	case ChangeCreated, ChangeUpdated, ChangeDeleted:
+	default:
+		return Change{}, errUnknownChange
+	}
+	if change.ShortURL == "" {
+		return Change{}, errUnknownChange
+	}
+	return change, nil
+}
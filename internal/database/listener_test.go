@@ -0,0 +1,158 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+func TestParseChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    Change
+		wantErr bool
+	}{
+		{name: "created", payload: `{"op":"created","short_url":"http://localhost:8080/a"}`,
+			want: Change{Op: ChangeCreated, ShortURL: "http://localhost:8080/a"}},
+		{name: "deleted", payload: `{"op":"deleted","short_url":"http://localhost:8080/a"}`,
+			want: Change{Op: ChangeDeleted, ShortURL: "http://localhost:8080/a"}},
+		{name: "resync is not published", payload: `{"op":"resync","short_url":""}`, wantErr: true},
+		{name: "no short URL", payload: `{"op":"updated"}`, wantErr: true},
+		{name: "malformed", payload: `created`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			change, err := parseChange(tc.payload)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, change)
+		})
+	}
+}
+
+// fakeConn delivers the queued notifications and then fails with err,
+// or blocks until the context is canceled if err is nil.
+type fakeConn struct {
+	notifications chan *pgconn.Notification
+	err           error
+}
+
+func (c *fakeConn) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (c *fakeConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case n := <-c.notifications:
+		return n, nil
+	default:
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (c *fakeConn) Close(context.Context) error {
+	return nil
+}
+
+func TestListenerRun(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+
+	notification := func(payload string) *pgconn.Notification {
+		return &pgconn.Notification{Channel: ChangesChannel, Payload: payload}
+	}
+	created := Change{Op: ChangeCreated, ShortURL: "http://localhost:8080/a"}
+	resync := Change{Op: ChangeResync}
+
+	tests := []struct {
+		name  string
+		conns []*fakeConn // nil is a failed connection attempt
+		want  []Change
+	}{
+		{
+			name:  "first connect",
+			conns: []*fakeConn{{notifications: make(chan *pgconn.Notification, 1)}},
+			want:  []Change{created},
+		},
+		{
+			name:  "first connect after failed attempts",
+			conns: []*fakeConn{nil, nil, {notifications: make(chan *pgconn.Notification, 1)}},
+			want:  []Change{resync, created},
+		},
+		{
+			name: "reconnect",
+			conns: []*fakeConn{
+				{notifications: make(chan *pgconn.Notification, 1), err: errors.New("connection lost")},
+				nil,
+				{notifications: make(chan *pgconn.Notification, 1)},
+			},
+			want: []Change{created, resync, created},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, conn := range tc.conns {
+				if conn != nil {
+					conn.notifications <- notification(`{"op":"created","short_url":"http://localhost:8080/a"}`)
+				}
+			}
+
+			attempt := 0
+			l := &Listener{
+				connect: func(context.Context) (listenConn, error) {
+					if attempt >= len(tc.conns) {
+						return nil, errors.New("no more connections")
+					}
+					conn := tc.conns[attempt]
+					attempt++
+					if conn == nil {
+						return nil, errors.New("connection refused")
+					}
+					return conn, nil
+				},
+				minBackoff: time.Millisecond,
+				maxBackoff: time.Millisecond,
+			}
+			changes := make(chan Change, 10)
+			l.Subscribe(func(change Change) {
+				changes <- change
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				l.Run(ctx)
+				close(done)
+			}()
+
+			for _, want := range tc.want {
+				select {
+				case change := <-changes:
+					assert.Equal(t, want, change)
+				case <-time.After(time.Second):
+					t.Fatalf("change %v was not delivered", want)
+				}
+			}
+			cancel()
+			<-done
+			assert.Empty(t, changes)
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationsFS holds the SQL migrations compiled into the binary.
+// Every migration consists of two files: NNNN_name.up.sql and NNNN_name.down.sql,
+// where NNNN is the migration version.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationLockKey is the key of the PostgreSQL advisory lock held while migrating,
+// so several replicas starting at once apply every migration exactly once.
+const migrationLockKey int64 = 0x6c6e6b73686f7274 // "lnkshort"
+
+// migrationFileRe matches migration file names and captures the version, the name and the direction.
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int64  // Version orders migrations, they are applied in ascending order.
+	Name    string // Name describes the migration.
+	Up      string // Up is the SQL applying the migration.
+	Down    string // Down is the SQL reverting the migration.
+}
+
+// MigrationStatus describes whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time // AppliedAt is the time the migration was applied, nil if it is pending.
+}
+
+// ErrNoMigrations is returned when rolling back a database without applied migrations.
+var ErrNoMigrations = errors.New("no applied migrations")
+
+// loadMigrations reads the embedded migrations sorted by version.
+func loadMigrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, f := range files {
+		m := migrationFileRe.FindStringSubmatch(f.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", f.Name())
+		}
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", f.Name(), err)
+		}
+		body, err := fs.ReadFile(migrationsFS, "migrations/"+f.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has different names: %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// withMigrationLock runs f on a dedicated connection holding the migration advisory lock.
+// It also makes sure the schema_migrations table exists.
+func withMigrationLock(ctx context.Context, db *pgxpool.Pool, f func(conn *pgxpool.Conn) error) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	// Wait for other replicas to finish migrating.
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("unable to acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Use a fresh context, so the lock is released even if ctx is done.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			// Drop the connection to release the session lock for sure.
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	query := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+	if _, err := conn.Exec(ctx, query); err != nil {
+		return fmt.Errorf("unable to create schema_migrations table: %w", err)
+	}
+
+	return f(conn)
+}
+
+// appliedMigrations returns the application time of every applied migration by version.
+func appliedMigrations(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// MigrateUp applies all pending migrations in ascending order.
+// Every migration runs in its own transaction together with its schema_migrations entry.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - db: The PostgreSQL connection pool.
+//
+// Returns:
+// - The migrations applied by this call.
+// - An error if any migration fails; migrations applied before it stay applied.
+func MigrateUp(ctx context.Context, db *pgxpool.Pool) ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	err = withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mig.Version, mig.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("unable to apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// MigrateDown reverts the latest applied migration.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - db: The PostgreSQL connection pool.
+//
+// Returns:
+// - The reverted migration.
+// - ErrNoMigrations if there is nothing to revert, or an error if the migration fails.
+func MigrateDown(ctx context.Context, db *pgxpool.Pool) (Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return Migration{}, err
+	}
+
+	var reverted Migration
+	err = withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// Find the latest applied migration.
+		for i := len(migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[migrations[i].Version]; ok {
+				reverted = migrations[i]
+				break
+			}
+		}
+		if reverted.Version == 0 {
+			return ErrNoMigrations
+		}
+
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, reverted.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, reverted.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("unable to revert migration %d_%s: %w", reverted.Version, reverted.Name, err)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// GetMigrationStatus reports every known migration with its application time.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - db: The PostgreSQL connection pool.
+//
+// Returns:
+// - The migrations in ascending order, pending ones have nil AppliedAt.
+// - An error if the query fails.
+func GetMigrationStatus(ctx context.Context, db *pgxpool.Pool) ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withMigrationLock(ctx, db, func(conn *pgxpool.Conn) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range migrations {
+			status := MigrationStatus{Migration: mig}
+			if appliedAt, ok := applied[mig.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
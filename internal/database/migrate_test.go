@@ -0,0 +1,44 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_urls", migrations[0].Name)
+
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Up, "migration %d has no up SQL", m.Version)
+		assert.NotEmpty(t, m.Down, "migration %d has no down SQL", m.Version)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestMigrationFileNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "0001_create_urls.up.sql", valid: true},
+		{name: "0012_add_column.down.sql", valid: true},
+		{name: "create_urls.up.sql", valid: false},
+		{name: "0001_create_urls.sql", valid: false},
+		{name: "0001_create-urls.up.sql", valid: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.valid, migrationFileRe.MatchString(tc.name))
+		})
+	}
+}
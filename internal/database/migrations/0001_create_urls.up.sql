@@ -0,0 +1,9 @@
+-- The table may already exist in databases created before migrations were introduced.
+CREATE TABLE IF NOT EXISTS urls (
+    id SERIAL PRIMARY KEY,
+    short_url TEXT NOT NULL,
+    original_url TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    deleted BOOL NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_original_url ON urls (original_url);
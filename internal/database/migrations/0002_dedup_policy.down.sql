@@ -0,0 +1,4 @@
+-- Fails if the table already contains duplicate original URLs.
+DROP INDEX IF EXISTS idx_urls_user_id;
+DROP INDEX IF EXISTS idx_urls_original_url;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_original_url ON urls (original_url);
@@ -0,0 +1,5 @@
+-- Deduplication of original URLs depends on the configured policy and is enforced
+-- by the application, so the global unique index is replaced with lookup indexes.
+DROP INDEX IF EXISTS idx_unique_original_url;
+CREATE INDEX IF NOT EXISTS idx_urls_original_url ON urls (original_url);
+CREATE INDEX IF NOT EXISTS idx_urls_user_id ON urls (user_id);
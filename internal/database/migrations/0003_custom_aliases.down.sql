@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_unique_short_url;
+ALTER TABLE urls DROP COLUMN IF EXISTS alias;
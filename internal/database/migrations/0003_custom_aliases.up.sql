@@ -0,0 +1,3 @@
+-- Short URLs may be chosen by users now, so their uniqueness is enforced by the database.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS alias BOOL NOT NULL DEFAULT FALSE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_short_url ON urls (short_url);
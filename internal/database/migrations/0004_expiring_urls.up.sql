@@ -0,0 +1,13 @@
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+-- Only expiring URLs are looked up by the sweeper.
+CREATE INDEX IF NOT EXISTS idx_urls_expires_at ON urls (expires_at) WHERE expires_at IS NOT NULL;
+CREATE TABLE IF NOT EXISTS urls_archive (
+    id SERIAL PRIMARY KEY,
+    short_url TEXT NOT NULL,
+    original_url TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    deleted BOOL NOT NULL,
+    alias BOOL NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
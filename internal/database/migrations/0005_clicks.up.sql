@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS clicks (
+    id BIGSERIAL PRIMARY KEY,
+    short_url TEXT NOT NULL,
+    clicked_at TIMESTAMPTZ NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT ''
+);
+-- Statistics are always requested for a single short URL over a period.
+CREATE INDEX IF NOT EXISTS idx_clicks_short_url_clicked_at ON clicks (short_url, clicked_at);
@@ -0,0 +1 @@
+CREATE SEQUENCE IF NOT EXISTS short_id_seq;
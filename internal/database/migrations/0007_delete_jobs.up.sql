@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS delete_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS delete_job_items (
+    job_id BIGINT NOT NULL REFERENCES delete_jobs (id) ON DELETE CASCADE,
+    short_url TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (job_id, short_url)
+);
+-- Jobs with pending items are resumed on startup.
+CREATE INDEX IF NOT EXISTS idx_delete_job_items_pending ON delete_job_items (job_id) WHERE status = 'pending';
@@ -0,0 +1,27 @@
+-- Every change of a URL record is published to the url_changes channel,
+-- so all replicas can invalidate their cached copies of the record.
+CREATE OR REPLACE FUNCTION notify_url_change() RETURNS trigger AS $$
+DECLARE
+    op TEXT;
+    changed_url TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        op := 'created';
+        changed_url := NEW.short_url;
+    ELSIF TG_OP = 'DELETE' THEN
+        op := 'deleted';
+        changed_url := OLD.short_url;
+    ELSIF NEW.deleted AND NOT OLD.deleted THEN
+        op := 'deleted';
+        changed_url := NEW.short_url;
+    ELSE
+        op := 'updated';
+        changed_url := NEW.short_url;
+    END IF;
+    PERFORM pg_notify('url_changes', json_build_object('op', op, 'short_url', changed_url)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS urls_notify_change ON urls;
+CREATE TRIGGER urls_notify_change AFTER INSERT OR UPDATE OR DELETE ON urls
+    FOR EACH ROW EXECUTE FUNCTION notify_url_change();
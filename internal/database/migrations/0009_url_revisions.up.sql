@@ -0,0 +1,11 @@
+-- Revisions are removed together with their short URL, so a purged alias taken again starts a new history.
+CREATE TABLE IF NOT EXISTS url_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    short_url TEXT NOT NULL REFERENCES urls (short_url) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    old_url TEXT NOT NULL,
+    new_url TEXT NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- The history of a short URL is read in the order of the revisions.
+CREATE INDEX IF NOT EXISTS idx_url_revisions_short_url ON url_revisions (short_url, id);
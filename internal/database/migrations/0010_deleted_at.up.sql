@@ -0,0 +1,5 @@
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+-- URLs deleted before the column existed can be restored for the whole retention from now on.
+UPDATE urls SET deleted_at = now() WHERE deleted AND deleted_at IS NULL;
+-- Only deleted URLs are looked up by the purge.
+CREATE INDEX IF NOT EXISTS idx_urls_deleted_at ON urls (deleted_at) WHERE deleted;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_urls_user_created;
+ALTER TABLE urls DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,4 @@
+-- URLs created before the column existed are dated by the migration.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+-- Listings of a user are ordered by creation time and then by ID.
+CREATE INDEX IF NOT EXISTS idx_urls_user_created ON urls (user_id, created_at, id);
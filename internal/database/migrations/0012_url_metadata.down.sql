@@ -0,0 +1,26 @@
+CREATE OR REPLACE FUNCTION notify_url_change() RETURNS trigger AS $$
+DECLARE
+    op TEXT;
+    changed_url TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        op := 'created';
+        changed_url := NEW.short_url;
+    ELSIF TG_OP = 'DELETE' THEN
+        op := 'deleted';
+        changed_url := OLD.short_url;
+    ELSIF NEW.deleted AND NOT OLD.deleted THEN
+        op := 'deleted';
+        changed_url := NEW.short_url;
+    ELSE
+        op := 'updated';
+        changed_url := NEW.short_url;
+    END IF;
+    PERFORM pg_notify('url_changes', json_build_object('op', op, 'short_url', changed_url)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+ALTER TABLE urls DROP COLUMN IF EXISTS notes;
+ALTER TABLE urls DROP COLUMN IF EXISTS title;
+ALTER TABLE urls DROP COLUMN IF EXISTS last_accessed_at;
+ALTER TABLE urls DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,30 @@
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS last_accessed_at TIMESTAMPTZ;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+-- The last access time is moved forward by every batch of clicks and is not cached by the replicas,
+-- so changes of nothing else are not published.
+CREATE OR REPLACE FUNCTION notify_url_change() RETURNS trigger AS $$
+DECLARE
+    op TEXT;
+    changed_url TEXT;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        op := 'created';
+        changed_url := NEW.short_url;
+    ELSIF TG_OP = 'DELETE' THEN
+        op := 'deleted';
+        changed_url := OLD.short_url;
+    ELSIF to_jsonb(NEW) - 'last_accessed_at' = to_jsonb(OLD) - 'last_accessed_at' THEN
+        RETURN NULL;
+    ELSIF NEW.deleted AND NOT OLD.deleted THEN
+        op := 'deleted';
+        changed_url := NEW.short_url;
+    ELSE
+        op := 'updated';
+        changed_url := NEW.short_url;
+    END IF;
+    PERFORM pg_notify('url_changes', json_build_object('op', op, 'short_url', changed_url)::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,9 @@
+-- Tags are removed together with their short URL.
+CREATE TABLE IF NOT EXISTS url_tags (
+    short_url TEXT NOT NULL REFERENCES urls (short_url) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (short_url, tag)
+);
+-- Listings and statistics look the tags up by user.
+CREATE INDEX IF NOT EXISTS idx_url_tags_user_tag ON url_tags (user_id, tag);
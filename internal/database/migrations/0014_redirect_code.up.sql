@@ -0,0 +1,2 @@
+-- Zero means the server default redirect status code.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS redirect_code SMALLINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- The salted bcrypt hash of the password, empty if the short URL is not protected.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Zero max_clicks means the short URL makes any number of redirects.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_clicks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS used_clicks INTEGER NOT NULL DEFAULT 0;
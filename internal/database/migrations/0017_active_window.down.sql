@@ -0,0 +1,2 @@
+ALTER TABLE urls DROP COLUMN IF EXISTS not_after;
+ALTER TABLE urls DROP COLUMN IF EXISTS not_before;
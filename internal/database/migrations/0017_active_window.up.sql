@@ -0,0 +1,3 @@
+-- NULL not_before and not_after leave the short URL active since creation and without an end.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS not_before TIMESTAMPTZ;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS not_after TIMESTAMPTZ;
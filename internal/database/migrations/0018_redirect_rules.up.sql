@@ -0,0 +1,2 @@
+-- NULL rules send every visitor to the original URL.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS rules JSONB;
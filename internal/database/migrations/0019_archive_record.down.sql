@@ -0,0 +1 @@
+ALTER TABLE urls_archive DROP COLUMN IF EXISTS record;
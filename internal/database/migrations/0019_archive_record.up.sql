@@ -0,0 +1,4 @@
+-- The archive keeps the full row of an expired URL together with its tags, the columns
+-- added to urls later are archived without changing the archive again.
+-- Rows archived before the column existed have only the columns of the archive table.
+ALTER TABLE urls_archive ADD COLUMN IF NOT EXISTS record JSONB;
@@ -0,0 +1,87 @@
+// Package deletion provides the durable queue of asynchronous short URL deletions.
+//
+// A deletion request is saved to the storage as a Job with one Item per short URL
+// before it is processed, so it survives restarts. Jobs are processed by a pool of workers,
+// the pending items of a job are deleted at once and retried with exponential backoff,
+// and their final statuses are saved as well, so the progress of a job can be queried at any time.
+package deletion
+
+import (
+	"context"
+	"time"
+)
+
+// Status is the state of a single short URL deletion.
+type Status string
+
+// Statuses of job items.
+const (
+	StatusPending Status = "pending" // StatusPending means the short URL is waiting to be deleted.
+	StatusDone    Status = "done"    // StatusDone means the short URL is deleted.
+	StatusFailed  Status = "failed"  // StatusFailed means all attempts to delete the short URL failed.
+)
+
+// Item is the deletion of a single short URL within a job.
+type Item struct {
+	ShortURL string `json:"short_url"`
+	Status   Status `json:"status"`
+	Attempts int    `json:"attempts,omitempty"` // Attempts counts failed attempts.
+	Error    string `json:"error,omitempty"`    // Error is the last error of a failed item.
+}
+
+// Job is a request of a user to delete a set of short URLs.
+type Job struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Items     []Item    `json:"items"`
+}
+
+// Counts holds the number of job items in each status.
+type Counts struct {
+	Pending int `json:"pending"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+}
+
+// NewJob creates a job deleting the short URLs of the user, repeated short URLs are deleted once.
+func NewJob(userID string, shortURLs []string, now time.Time) *Job {
+	job := &Job{UserID: userID, CreatedAt: now}
+	seen := make(map[string]struct{}, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		if _, ok := seen[shortURL]; ok {
+			continue
+		}
+		seen[shortURL] = struct{}{}
+		job.Items = append(job.Items, Item{ShortURL: shortURL, Status: StatusPending})
+	}
+	return job
+}
+
+// Counts counts the job items by status.
+func (j *Job) Counts() Counts {
+	var c Counts
+	for _, item := range j.Items {
+		switch item.Status {
+		case StatusDone:
+			c.Done++
+		case StatusFailed:
+			c.Failed++
+		default:
+			c.Pending++
+		}
+	}
+	return c
+}
+
+// Store persists deletion jobs.
+type Store interface {
+	// SaveDeleteJob saves a new job and assigns it an ID.
+	SaveDeleteJob(ctx context.Context, job *Job) error
+	// UpdateDeleteJobItems saves the new statuses of the job items with the same short URLs.
+	UpdateDeleteJobItems(ctx context.Context, jobID string, items []Item) error
+	// GetDeleteJob returns the job with the ID or os.ErrProcessDone if it does not exist.
+	GetDeleteJob(ctx context.Context, id string) (*Job, error)
+	// PendingDeleteJobs returns the jobs having pending items in creation order.
+	PendingDeleteJobs(ctx context.Context) ([]Job, error)
+}
@@ -0,0 +1,293 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// DeleteFunc deletes the short URLs belonging to the user at once.
+// Deleting already deleted or unknown short URLs must succeed, so items can be retried safely.
+type DeleteFunc func(ctx context.Context, userID string, shortURLs []string) error
+
+// ErrQueueClosed is returned when a job is submitted to a closed queue.
+var ErrQueueClosed = errors.New("deletion queue is closed")
+
+// maxBackoff caps the delay between retries of an item.
+const maxBackoff = time.Minute
+
+// Queue processes deletion jobs with a pool of workers.
+// Jobs are saved to the storage before they are queued and item statuses are saved
+// as soon as they are final, so the jobs interrupted by a shutdown are resumed on the next start.
+type Queue struct {
+	store        Store
+	deleteURLs   DeleteFunc
+	maxAttempts  int
+	backoff      time.Duration
+	storeTimeout time.Duration // storeTimeout limits a single storage operation of the queue, zero means no limit.
+
+	incoming chan *Job // incoming passes submitted jobs to the dispatcher.
+	work     chan *Job // work passes queued jobs from the dispatcher to the workers.
+
+	mu     sync.RWMutex // mu guards closed and serializes it with sends to incoming.
+	closed bool
+
+	ctx    context.Context    // ctx is canceled if draining the queue takes too long.
+	cancel context.CancelFunc // cancel interrupts the workers.
+	wg     sync.WaitGroup     // wg waits for the dispatcher and the workers to stop.
+}
+
+// NewQueue creates a Queue, queues the pending jobs from the storage and starts the workers.
+//
+// Parameters:
+// - ctx: The context for loading the pending jobs.
+// - store: The storage of jobs.
+// - deleteURLs: The function deleting the short URLs of a job.
+// - workers: The number of jobs processed concurrently.
+// - maxAttempts: The number of attempts to delete the short URLs before the items fail.
+// - backoff: The delay before the first retry, it doubles with every next retry up to a minute.
+// - storeTimeout: The time limit of saving the item statuses, zero means no limit.
+//
+// Returns:
+// - A pointer to a running Queue, it must be closed to stop the workers.
+// - An error if the pending jobs cannot be loaded.
+func NewQueue(ctx context.Context, store Store, deleteURLs DeleteFunc, workers, maxAttempts int, backoff, storeTimeout time.Duration) (*Queue, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	pending, err := store.PendingDeleteJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Queue{
+		store:        store,
+		deleteURLs:   deleteURLs,
+		maxAttempts:  maxAttempts,
+		backoff:      backoff,
+		storeTimeout: storeTimeout,
+		incoming:     make(chan *Job),
+		work:         make(chan *Job),
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	queued := make([]*Job, 0, len(pending))
+	for i := range pending {
+		queued = append(queued, &pending[i])
+	}
+	if len(queued) > 0 {
+		logging.Sugar.Infow("Resuming deletion jobs", "count", len(queued))
+	}
+
+	q.wg.Add(1 + workers)
+	go q.dispatch(queued)
+	for i := 0; i < workers; i++ {
+		go q.worker()
+	}
+
+	return q, nil
+}
+
+// Submit saves a new job deleting the short URLs of the user and queues it.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts of saving the job.
+// - userID: The user ID the short URLs must belong to.
+// - shortURLs: The short URLs to be deleted.
+//
+// Returns:
+// - A copy of the saved job with the assigned ID.
+// - ErrQueueClosed if the queue is closed, or an error if the job cannot be saved.
+func (q *Queue) Submit(ctx context.Context, userID string, shortURLs []string) (*Job, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+
+	job := NewJob(userID, shortURLs, time.Now().UTC())
+	if err := q.store.SaveDeleteJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The workers change the queued job, so the caller gets its own copy.
+	queued := *job
+	queued.Items = append([]Item(nil), job.Items...)
+	q.incoming <- &queued
+
+	return job, nil
+}
+
+// Close stops accepting jobs and waits for the queued jobs to be processed.
+// If the context is done first, the workers are interrupted and the unfinished jobs
+// stay pending in the storage.
+//
+// Returns:
+// - The context error if the queue is not drained in time.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.incoming)
+	}
+	q.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-drained
+		return ctx.Err()
+	}
+}
+
+// dispatch passes the submitted jobs to the workers in order without blocking Submit
+// while all workers are busy. After the queue is closed, it passes the remaining jobs
+// and stops the workers.
+func (q *Queue) dispatch(queued []*Job) {
+	defer q.wg.Done()
+	defer close(q.work)
+
+	incoming := q.incoming
+	for incoming != nil || len(queued) > 0 {
+		// Sending to a nil channel blocks, so the case is disabled while nothing is queued.
+		var work chan *Job
+		var next *Job
+		if len(queued) > 0 {
+			work = q.work
+			next = queued[0]
+		}
+
+		select {
+		case job, ok := <-incoming:
+			if !ok {
+				incoming = nil
+				continue
+			}
+			queued = append(queued, job)
+		case work <- next:
+			queued = queued[1:]
+		}
+	}
+}
+
+// worker processes jobs until the dispatcher stops.
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	for job := range q.work {
+		q.process(job)
+	}
+}
+
+// process deletes all pending items of the job at once and saves their final statuses.
+// Items interrupted by the queue shutdown stay pending.
+func (q *Queue) process(job *Job) {
+	pending := make([]*Item, 0, len(job.Items))
+	for i := range job.Items {
+		if job.Items[i].Status == StatusPending {
+			pending = append(pending, &job.Items[i])
+		}
+	}
+	if len(pending) == 0 || !q.deleteItems(job.UserID, pending) {
+		return
+	}
+
+	final := make([]Item, 0, len(pending))
+	for _, item := range pending {
+		final = append(final, *item)
+	}
+	q.saveItems(job.ID, final)
+}
+
+// deleteItems deletes the short URLs of the items, retrying with backoff until the attempts are exhausted.
+// The items share the outcome of every attempt.
+// It returns false if the queue is interrupted before the item statuses are final.
+func (q *Queue) deleteItems(userID string, items []*Item) bool {
+	shortURLs := make([]string, 0, len(items))
+	for _, item := range items {
+		shortURLs = append(shortURLs, item.ShortURL)
+	}
+
+	for attempts := 0; ; {
+		if q.ctx.Err() != nil {
+			return false
+		}
+
+		err := q.deleteURLs(q.ctx, userID, shortURLs)
+		if err == nil {
+			for _, item := range items {
+				item.Status = StatusDone
+				item.Error = ""
+			}
+			return true
+		}
+		if q.ctx.Err() != nil {
+			// The attempt was interrupted, it does not count.
+			return false
+		}
+
+		attempts++
+		for _, item := range items {
+			item.Attempts = attempts
+			item.Error = err.Error()
+		}
+		if attempts >= q.maxAttempts {
+			for _, item := range items {
+				item.Status = StatusFailed
+			}
+			logging.Sugar.Errorw("Failed to delete URLs",
+				"user_id", userID, "count", len(items), "attempts", attempts, "error", err)
+			return true
+		}
+
+		timer := time.NewTimer(q.delay(attempts))
+		select {
+		case <-q.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns the backoff before the retry following the given number of failed attempts.
+func (q *Queue) delay(attempts int) time.Duration {
+	d := q.backoff
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
+
+// saveItems saves the final item statuses and logs failures.
+// If saving fails, the items stay pending in the storage and are deleted again on the next start.
+func (q *Queue) saveItems(jobID string, items []Item) {
+	ctx := context.Background()
+	if q.storeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.storeTimeout)
+		defer cancel()
+	}
+
+	if err := q.store.UpdateDeleteJobItems(ctx, jobID, items); err != nil {
+		logging.Sugar.Errorw("Failed to save deletion statuses",
+			"job_id", jobID, "count", len(items), "error", err)
+	}
+}
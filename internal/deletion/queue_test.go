@@ -0,0 +1,125 @@
+package deletion_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+// deleter records deleted short URLs and fails the batches containing the configured ones.
+type deleter struct {
+	mu      sync.Mutex
+	deleted []string
+	calls   int
+	failing map[string]bool
+	block   chan struct{} // block holds every deletion until it is closed, if set.
+}
+
+func (d *deleter) delete(ctx context.Context, userID string, shortURLs []string) error {
+	if d.block != nil {
+		select {
+		case <-d.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	for _, shortURL := range shortURLs {
+		if d.failing[shortURL] {
+			return errors.New("storage is down")
+		}
+	}
+	d.deleted = append(d.deleted, shortURLs...)
+	return nil
+}
+
+func waitFinished(t *testing.T, store deletion.Store, jobID string) *deletion.Job {
+	var job *deletion.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = store.GetDeleteJob(context.Background(), jobID)
+		require.NoError(t, err)
+		return job.Counts().Pending == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestQueue(t *testing.T) {
+	require.NoError(t, logging.Initialize())
+	ctx := context.Background()
+
+	t.Run("deletes and retries", func(t *testing.T) {
+		store := memory.NewMemoryStore(config.DedupGlobal)
+		d := &deleter{failing: map[string]bool{"c": true}}
+		q, err := deletion.NewQueue(ctx, store, d.delete, 2, 3, time.Millisecond, time.Second)
+		require.NoError(t, err)
+
+		job, err := q.Submit(ctx, "user1", []string{"a", "b", "a"})
+		require.NoError(t, err)
+		assert.Equal(t, deletion.Counts{Pending: 2}, job.Counts())
+
+		// The items of a job are deleted at once.
+		finished := waitFinished(t, store, job.ID)
+		assert.Equal(t, deletion.Counts{Done: 2}, finished.Counts())
+		assert.ElementsMatch(t, []string{"a", "b"}, d.deleted)
+		assert.Equal(t, 1, d.calls)
+
+		// The items of a failing job share the attempts.
+		job, err = q.Submit(ctx, "user1", []string{"c", "d"})
+		require.NoError(t, err)
+
+		finished = waitFinished(t, store, job.ID)
+		assert.Equal(t, deletion.Counts{Failed: 2}, finished.Counts())
+		for _, item := range finished.Items {
+			assert.Equal(t, 3, item.Attempts)
+			assert.Equal(t, "storage is down", item.Error)
+		}
+		assert.Equal(t, 4, d.calls)
+
+		require.NoError(t, q.Close(ctx))
+		_, err = q.Submit(ctx, "user1", []string{"e"})
+		assert.ErrorIs(t, err, deletion.ErrQueueClosed)
+	})
+
+	t.Run("resumes interrupted jobs", func(t *testing.T) {
+		store := memory.NewMemoryStore(config.DedupGlobal)
+		blocked := &deleter{block: make(chan struct{})}
+		q, err := deletion.NewQueue(ctx, store, blocked.delete, 1, 3, time.Millisecond, time.Second)
+		require.NoError(t, err)
+
+		job, err := q.Submit(ctx, "user1", []string{"a", "b"})
+		require.NoError(t, err)
+
+		// The drain times out while the deletion is stuck, so the job stays pending.
+		drainCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, q.Close(drainCtx), context.DeadlineExceeded)
+
+		pending, err := store.PendingDeleteJobs(ctx)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, deletion.Counts{Pending: 2}, pending[0].Counts())
+
+		// The next queue picks the job up on start.
+		d := &deleter{}
+		q, err = deletion.NewQueue(ctx, store, d.delete, 1, 3, time.Millisecond, time.Second)
+		require.NoError(t, err)
+		defer q.Close(ctx)
+
+		finished := waitFinished(t, store, job.ID)
+		assert.Equal(t, deletion.Counts{Done: 2}, finished.Counts())
+	})
+}
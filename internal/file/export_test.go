@@ -0,0 +1,6 @@
+package file
+
+// CloseLog closes the log file of the store, so writing the log fails from then on.
+func CloseLog(store *FileStore) {
+	store.log.Close()
+}
@@ -1,21 +1,155 @@
 // Package file provides functionalities to manage URL records using file-based storage.
 // It includes structures and methods for creating, reading, updating, and deleting URL records.
+//
+// FileStore keeps the records in an append-only log of create, delete and purge events.
+// The log is replayed into an in-memory index at startup and is periodically compacted
+// into a fresh snapshot file. Click events are kept in a separate append-only file.
 package file
 
 import (
 	"encoding/json"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // URLRecord represents a single URL mapping in the storage system.
 // It contains information about the shortened URL, the original URL, the associated user,
-// and a flag indicating whether the URL has been deleted.
+// a flag indicating whether the URL has been deleted with the deletion time, the times the URL
+// was created, changed, last accessed and expires at, the user's title, notes and tags, the redirect status code
+// the password hash of a protected URL, the limit of redirects with the number of redirects made,
+// the activation window of the URL and the rules redirecting some visitors to other targets.
 type URLRecord struct {
-	UUID        string `json:"uuid"`         // UUID uniquely identifies the URL record.
-	ShortURL    string `json:"short_url"`    // ShortURL is the shortened version of the original URL.
-	OriginalURL string `json:"original_url"` // OriginalURL is the original, long-form URL.
-	UserUUID    string `json:"user_uuid"`    // UserUUID associates the URL with a specific user.
-	DeletedFlag bool   `json:"deleted"`      // DeletedFlag indicates whether the URL has been marked as deleted.
+	UUID           string         `json:"uuid"`                       // UUID uniquely identifies the URL record.
+	ShortURL       string         `json:"short_url"`                  // ShortURL is the shortened version of the original URL.
+	OriginalURL    string         `json:"original_url"`               // OriginalURL is the original, long-form URL.
+	UserUUID       string         `json:"user_uuid"`                  // UserUUID associates the URL with a specific user.
+	DeletedFlag    bool           `json:"deleted"`                    // DeletedFlag indicates whether the URL has been marked as deleted.
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty"`       // DeletedAt is the time the URL was marked as deleted, nil if it is not deleted.
+	Alias          bool           `json:"alias,omitempty"`            // Alias indicates whether the short URL ID was chosen by the user.
+	ExpiresAt      *time.Time     `json:"expires_at,omitempty"`       // ExpiresAt is the time the URL expires at, nil if it never expires.
+	CreatedAt      time.Time      `json:"created_at"`                 // CreatedAt is the time the URL was created, zero for records saved before it was kept.
+	UpdatedAt      *time.Time     `json:"updated_at,omitempty"`       // UpdatedAt is the time the URL was last changed, deleted or restored, nil if it never was.
+	LastAccessedAt *time.Time     `json:"last_accessed_at,omitempty"` // LastAccessedAt is the time of the last recorded click, nil if there was none.
+	Title          string         `json:"title,omitempty"`            // Title is a free-form title of the URL given by the user.
+	Notes          string         `json:"notes,omitempty"`            // Notes are free-form notes on the URL given by the user.
+	Tags           []string       `json:"tags,omitempty"`             // Tags are the sorted unique tags the user organizes the URL with.
+	RedirectCode   int            `json:"redirect_code,omitempty"`    // RedirectCode is the HTTP status code of the redirect, zero for the server default.
+	PasswordHash   string         `json:"password_hash,omitempty"`    // PasswordHash is the salted hash of the password protecting the URL, empty if it is not protected.
+	MaxClicks      int            `json:"max_clicks,omitempty"`       // MaxClicks is the number of redirects the URL is deactivated after, zero if it is unlimited.
+	UsedClicks     int            `json:"used_clicks,omitempty"`      // UsedClicks is the number of redirects made out of MaxClicks.
+	NotBefore      *time.Time     `json:"not_before,omitempty"`       // NotBefore is the time the URL becomes active at, nil if it is active since creation.
+	NotAfter       *time.Time     `json:"not_after,omitempty"`        // NotAfter is the time the URL stops being active at, nil if it stays active.
+	Rules          []RedirectRule `json:"rules,omitempty"`            // Rules are checked in order on every redirect, the OriginalURL is the default target.
+}
+
+// RedirectRule redirects the visitors matching all of its conditions to its own target.
+// Empty conditions match any visitor.
+type RedirectRule struct {
+	Device   string            `json:"device,omitempty"`   // Device is the device family of the visitor: "ios", "android", "desktop" or "bot".
+	Language string            `json:"language,omitempty"` // Language is the language tag the preferred language of the visitor must match, like "de" or "pt-br".
+	Query    map[string]string `json:"query,omitempty"`    // Query holds the query parameters the request must have, an empty value matches any value.
+	Target   string            `json:"target"`             // Target is the URL the matching visitors are redirected to.
+}
+
+// Position returns the position of the record in the listings of its user.
+// Records with a UUID which is not a number are placed before the other ones created at the same time.
+func (r *URLRecord) Position() URLPosition {
+	id, _ := strconv.ParseInt(r.UUID, 10, 64)
+	return URLPosition{CreatedAt: r.CreatedAt, ID: id}
+}
+
+// URLStatus selects URL records by their deletion status.
+type URLStatus string
+
+// Deletion statuses URL records are filtered by.
+const (
+	URLStatusAll     URLStatus = ""        // URLStatusAll selects all records.
+	URLStatusActive  URLStatus = "active"  // URLStatusActive selects the records which are not deleted.
+	URLStatusDeleted URLStatus = "deleted" // URLStatusDeleted selects the deleted records.
+)
+
+// URLPosition is the position of a URL record in a listing.
+// Listings are ordered by the creation time and then by the UUID.
+type URLPosition struct {
+	CreatedAt time.Time `json:"created_at"` // CreatedAt is the creation time of the record.
+	ID        int64     `json:"id"`         // ID is the numeric UUID of the record.
+}
+
+// Before reports whether the position p comes before the position other in ascending order.
+func (p URLPosition) Before(other URLPosition) bool {
+	if !p.CreatedAt.Equal(other.CreatedAt) {
+		return p.CreatedAt.Before(other.CreatedAt)
+	}
+	return p.ID < other.ID
+}
+
+// URLQuery selects a page of the URL records of a user.
+type URLQuery struct {
+	Limit       int          // Limit is the maximum number of records in the page, 0 means no limit.
+	After       *URLPosition // After is the position of the last record of the previous page, nil for the first page.
+	Desc        bool         // Desc orders the records from the newest to the oldest.
+	Status      URLStatus    // Status selects the records by their deletion status.
+	Contains    string       // Contains selects the records with the original URL containing it, if not empty.
+	CreatedFrom *time.Time   // CreatedFrom selects the records created at or after it, if not nil.
+	CreatedTo   *time.Time   // CreatedTo selects the records created before it, if not nil.
+	Tag         string       // Tag selects the records tagged with it, if not empty.
+}
+
+// Match reports whether the record passes the filters of the query, the page position is not checked.
+func (q *URLQuery) Match(r *URLRecord) bool {
+	switch {
+	case q.Status == URLStatusActive && r.DeletedFlag,
+		q.Status == URLStatusDeleted && !r.DeletedFlag,
+		q.Contains != "" && !strings.Contains(r.OriginalURL, q.Contains),
+		q.CreatedFrom != nil && r.CreatedAt.Before(*q.CreatedFrom),
+		q.CreatedTo != nil && !r.CreatedAt.Before(*q.CreatedTo),
+		q.Tag != "" && !slices.Contains(r.Tags, q.Tag):
+		return false
+	}
+	return true
+}
+
+// URLPage is a page of the URL records of a user.
+type URLPage struct {
+	Records []URLRecord  // Records are the records of the page in the order of the query.
+	Total   int          // Total is the number of records matching the filters on all pages.
+	Next    *URLPosition // Next is the position the next page starts after, nil if it is the last page.
+}
+
+// IsExpired reports whether the URL has expired by the given time.
+func (r *URLRecord) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// IsNotYetActive reports whether the URL is not active yet by the given time.
+func (r *URLRecord) IsNotYetActive(now time.Time) bool {
+	return r.NotBefore != nil && now.Before(*r.NotBefore)
+}
+
+// IsNoLongerActive reports whether the activation window of the URL has ended by the given time.
+func (r *URLRecord) IsNoLongerActive(now time.Time) bool {
+	return r.NotAfter != nil && !now.Before(*r.NotAfter)
+}
+
+// URLRevision is a change of the original URL a short URL redirects to.
+type URLRevision struct {
+	ShortURL  string    `json:"short_url"`  // ShortURL is the changed short URL.
+	UserUUID  string    `json:"user_uuid"`  // UserUUID is the user who made the change.
+	OldURL    string    `json:"old_url"`    // OldURL is the original URL before the change.
+	NewURL    string    `json:"new_url"`    // NewURL is the original URL after the change.
+	ChangedAt time.Time `json:"changed_at"` // ChangedAt is the time of the change.
+}
+
+// SaveResult is the outcome of saving a single record of a batch.
+type SaveResult struct {
+	// ShortURL is the saved short URL or the existing one if the original URL is a duplicate.
+	ShortURL string
+	// Err is nil if the record is saved, otherwise the reason it is not,
+	// the same error saving the record alone would result in.
+	Err error
 }
 
 // Producer is responsible for writing URL records to a file.
@@ -103,43 +237,6 @@ func (c *Consumer) ReadURLRecord() (*URLRecord, error) {
 	return URLRecord, nil
 }
 
-// FindOriginalURLByShortURL searches for the original URL corresponding to a given short URL.
-// It iterates through all URL records in the specified file to find a match.
-//
-// Parameters:
-// - shortURL: The short URL to search for.
-// - fileName: The name of the file containing URL records.
-//
-// Returns:
-// - The original URL if found.
-// - A boolean indicating whether the URL was found.
-// - An error if file operations fail.
-func FindOriginalURLByShortURL(shortURL, fileName string) (string, bool, error) {
-	// Initialize a new Consumer for the file storage.
-	consumer, err := NewConsumer(fileName)
-	if err != nil {
-		return "", false, err
-	}
-	defer consumer.File.Close()
-
-	// Iterate through all URL records in the specified file to find a match.
-	for {
-		rec, err := consumer.ReadURLRecord()
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return "", false, err
-		}
-
-		if rec.ShortURL == shortURL {
-			return rec.OriginalURL, rec.DeletedFlag, nil
-		}
-	}
-
-	return "", false, os.ErrProcessDone
-}
-
 // SaveURLRecord writes a single URLRecord to the specified file.
 // It initializes a Producer and delegates the writing process.
 //
@@ -158,187 +255,3 @@ func SaveURLRecord(url *URLRecord, fileName string) error {
 
 	return producer.WriteURLRecord(url)
 }
-
-// FileStore provides a file-based implementation of the URLStore interface.
-// It manages URL records by reading from and writing to a specified file.
-type FileStore struct {
-	fileName string // fileName is the path to the file used for storing URL records.
-}
-
-// NewFileStore initializes and returns a new FileStore for the specified file.
-// It sets the file name for future read and write operations.
-func NewFileStore(fileName string) *FileStore {
-	return &FileStore{fileName: fileName}
-}
-
-// SaveURLRecord saves a URLRecord to the file.
-// It delegates the saving process to the SaveURLRecord function.
-func (store *FileStore) SaveURLRecord(urlRecord *URLRecord) (string, error) {
-	return "", SaveURLRecord(urlRecord, store.fileName)
-}
-
-// GetOriginalURL retrieves the original URL and its deletion status based on the provided short URL.
-// It delegates the retrieval process to the FindOriginalURLByShortURL function.
-func (store *FileStore) GetOriginalURL(shortURL string) (string, bool, error) {
-	return FindOriginalURLByShortURL(shortURL, store.fileName)
-}
-
-// GetUserURLs retrieves all URL records associated with a specific user ID.
-// It iterates through the file to collect URLs belonging to the given user.
-//
-// Parameters:
-// - userID: The user ID whose URLs are to be retrieved.
-//
-// Returns:
-// - A slice of URLRecord containing the user's URLs.
-// - An error if file operations fail.
-func (store *FileStore) GetUserURLs(userID string) ([]URLRecord, error) {
-	var records []URLRecord
-
-	// Initiate a new consumer.
-	consumer, err := NewConsumer(store.fileName)
-	if err != nil {
-		return nil, err
-	}
-	defer consumer.File.Close()
-
-	// Iterate through the file to collect URLs belonging to the given user.
-	for {
-		rec, err := consumer.ReadURLRecord()
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return nil, err
-		}
-
-		if rec.UserUUID == userID {
-			records = append(records, URLRecord{
-				ShortURL:    rec.ShortURL,
-				OriginalURL: rec.OriginalURL,
-			})
-		}
-	}
-
-	return records, nil
-}
-
-// BatchUpdateDeleteFlag marks multiple URL records as deleted based on the provided URL ID and user ID.
-// It reads all records, updates the deletion flag where applicable, and rewrites the entire file.
-//
-// Parameters:
-// - urlID: The UUID of the URL record to be marked as deleted.
-// - userID: The user ID associated with the URL record.
-//
-// Returns:
-// - An error if reading or writing records fails.
-func (store *FileStore) BatchUpdateDeleteFlag(urlID string, userID string) error {
-	// Initiate a new consumer.
-	consumer, err := NewConsumer(store.fileName)
-	if err != nil {
-		return err
-	}
-	defer consumer.File.Close()
-
-	var updatedRecords []URLRecord
-	//Read all records, updates the deletion flag where applicable.
-	for {
-		rec, err := consumer.ReadURLRecord()
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return err
-		}
-
-		if rec.UUID == urlID && rec.UserUUID == userID {
-			rec.DeletedFlag = true
-		}
-
-		updatedRecords = append(updatedRecords, *rec)
-	}
-
-	return store.SaveAllRecords(updatedRecords)
-}
-
-// SaveAllRecords writes all provided URLRecords to the file.
-// It overwrites the existing file with the new set of records.
-//
-// Parameters:
-// - records: A slice of URLRecord to be saved.
-//
-// Returns:
-// - An error if the Producer cannot be initialized or if writing any record fails.
-func (store *FileStore) SaveAllRecords(records []URLRecord) error {
-	// Initiate a producer.
-	producer, err := NewProducer(store.fileName)
-	if err != nil {
-		return err
-	}
-	defer producer.File.Close()
-
-	// Overwrite the existing file with the new set of records.
-	for _, record := range records {
-		if err := producer.WriteURLRecord(&record); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// GetURLsCount counts shortened URLs in the file.
-//
-// Returns:
-// - A number of shortened URLs.
-// - An error if reading fails.
-func (store *FileStore) GetURLsCount() (int, error) {
-	consumer, err := NewConsumer(store.fileName)
-	if err != nil {
-		return 0, err
-	}
-	defer consumer.File.Close()
-
-	count := 0
-	for {
-		_, err := consumer.ReadURLRecord()
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return 0, err
-		}
-		count++
-	}
-
-	return count, nil
-}
-
-// GetUsersCount counts unique users
-// by reading all records in the file and collecting userUUID.
-//
-// Returns:
-// - A number of unique users.
-// - An error if reading fails.
-func (store *FileStore) GetUsersCount() (int, error) {
-	consumer, err := NewConsumer(store.fileName)
-	if err != nil {
-		return 0, err
-	}
-	defer consumer.File.Close()
-
-	usersSet := make(map[string]struct{})
-	for {
-		rec, err := consumer.ReadURLRecord()
-		if err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
-			return 0, err
-		}
-
-		usersSet[rec.UserUUID] = struct{}{}
-	}
-
-	return len(usersSet), nil
-}
@@ -0,0 +1,1126 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+)
+
+// Operations recorded in the storage log.
+const (
+	opCreate  = "create"  // opCreate adds a new URL record.
+	opDelete  = "delete"  // opDelete marks a URL record as deleted (tombstone).
+	opPurge   = "purge"   // opPurge removes an expired URL record.
+	opUpdate  = "update"  // opUpdate changes the original URL of a URL record and records the revision.
+	opRestore = "restore" // opRestore clears the deletion mark of a URL record.
+	opTags    = "tags"    // opTags replaces the tags of a URL record.
+	opClick   = "click"   // opClick counts a redirect of a URL record against its click limit.
+	opWindow  = "window"  // opWindow changes the activation window of a URL record.
+	opRules   = "rules"   // opRules replaces the redirect rules of a URL record.
+	opSeq     = "seq"     // opSeq records the last number of the index sequence, it starts a compacted log.
+)
+
+// Suffixes appended to the log file name to get the names of the auxiliary files.
+const (
+	archiveSuffix = ".archive" // archiveSuffix names the file expired records are archived to.
+	clicksSuffix  = ".clicks"  // clicksSuffix names the append-only file of click events.
+	jobsSuffix    = ".jobs"    // jobsSuffix names the append-only journal of deletion jobs.
+)
+
+// logEntry is a single line of the append-only storage log.
+// The URLRecord fields are inlined, so a creation entry is a valid URLRecord line as well.
+// Entries written before the log was introduced have no operation and are treated as creations.
+type logEntry struct {
+	Op string `json:"op,omitempty"` // Op is the operation recorded by the entry.
+	URLRecord
+	Revision *URLRevision `json:"revision,omitempty"` // Revision is the change recorded by an update entry.
+	Seq      int64        `json:"seq,omitempty"`      // Seq is the last number of the sequence recorded by a sequence entry.
+}
+
+// jobEntry is a single line of the deletion jobs journal.
+// It holds either a new job, the final statuses of some of the job items
+// or the last number of the index sequence starting a compacted journal.
+type jobEntry struct {
+	Job   *deletion.Job   `json:"job,omitempty"`    // Job is a new job.
+	JobID string          `json:"job_id,omitempty"` // JobID is the ID of the job the items belong to.
+	Items []deletion.Item `json:"items,omitempty"`  // Items are the items with their new statuses.
+	Seq   int64           `json:"seq,omitempty"`    // Seq keeps the IDs of the dropped jobs from being assigned again.
+}
+
+// Index is the in-memory index FileStore serves all reads from.
+// The log itself is read only once at startup to rebuild the index.
+type Index interface {
+	// SaveURLRecord adds a record to the index.
+	// It returns the existing short URL and an error if the original URL is already indexed.
+	SaveURLRecord(ctx context.Context, urlRecord *URLRecord) (string, error)
+	// SaveURLRecords adds a batch of records to the index at once and returns the result of every record.
+	// If atomic is set and any record cannot be saved, nothing is added and an error is returned.
+	SaveURLRecords(ctx context.Context, records []*URLRecord, atomic bool) ([]SaveResult, error)
+	// GetURLRecord returns the record for the short URL.
+	GetURLRecord(ctx context.Context, shortURL string) (*URLRecord, error)
+	// GetUserURLs returns a page of the records created by the user which match the query.
+	GetUserURLs(ctx context.Context, userID string, q URLQuery) (URLPage, error)
+	// MarkDeleted marks the records as deleted at the given time if they belong to the user.
+	MarkDeleted(urlIDs []string, userID string, deletedAt time.Time)
+	// MarkRestored clears the deletion mark of the user's records deleted since deletedSince
+	// at the given time and returns the restored short URLs.
+	MarkRestored(urlIDs []string, userID string, deletedSince, restoredAt time.Time) []string
+	// DeletedBefore returns the short URLs of the records deleted before the given time.
+	DeletedBefore(deletedBefore time.Time) []string
+	// GetURLsCount counts indexed records.
+	GetURLsCount(ctx context.Context) (int, error)
+	// GetUsersCount counts unique users.
+	GetUsersCount(ctx context.Context) (int, error)
+	// Restore adds a record read from the log as is, without duplicate detection.
+	Restore(urlRecord *URLRecord)
+	// Remove removes the records with the given short URLs.
+	Remove(shortURLs ...string)
+	// Revert puts back copies of a record and of its revisions taken before a change.
+	Revert(urlRecord *URLRecord, revisions []URLRevision)
+	// SaveClicks adds click events to the index.
+	SaveClicks(ctx context.Context, events []clicks.Click) error
+	// Clicks returns copies of all indexed clicks.
+	Clicks() []clicks.Click
+	// GetClickStats aggregates indexed clicks of a short URL.
+	GetClickStats(ctx context.Context, q clicks.Query) (clicks.Stats, error)
+	// MarkTags replaces the tags of the user's record and records the time it was changed at,
+	// a zero time keeps the previous one.
+	MarkTags(shortURL, userID string, tags []string, updatedAt time.Time) error
+	// GetTagStats counts the user's records and their clicks by tags.
+	GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error)
+	// MarkActiveWindow changes the activation window of the user's record and records the time it was changed at,
+	// a zero time keeps the previous one.
+	MarkActiveWindow(shortURL, userID string, notBefore, notAfter *time.Time, updatedAt time.Time) error
+	// MarkRules replaces the redirect rules of the user's record and records the time it was changed at,
+	// a zero time keeps the previous one.
+	MarkRules(shortURL, userID string, rules []RedirectRule, updatedAt time.Time) error
+	// UseClick counts a redirect of the record against its click limit.
+	UseClick(ctx context.Context, shortURL string) error
+	// NextSequence returns the next number of the sequence, it must be above the restored record UUIDs.
+	NextSequence(ctx context.Context) (int64, error)
+	// Sequence returns the last number of the sequence.
+	Sequence() int64
+	// RestoreSequence advances the sequence to at least the given number read from the log.
+	RestoreSequence(seq int64)
+	// Records returns copies of all indexed records in creation order.
+	Records() []URLRecord
+	// UpdateOriginalURL changes the original URL of the user's short URL and records the revision.
+	UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*URLRevision, error)
+	// RestoreRevision applies a revision read from the log as is.
+	RestoreRevision(rev *URLRevision)
+	// GetURLHistory returns the revisions of the user's short URL.
+	GetURLHistory(ctx context.Context, shortURL, userID string) ([]URLRevision, error)
+	// Revisions returns copies of the revisions of all indexed records,
+	// the revisions of every record in the order they were made.
+	Revisions() []URLRevision
+	// RestoreDeleteJob adds a deletion job with the assigned ID as is.
+	RestoreDeleteJob(job *deletion.Job)
+	// UpdateDeleteJobItems replaces the items of the deletion job with the same short URLs.
+	UpdateDeleteJobItems(ctx context.Context, jobID string, items []deletion.Item) error
+	// GetDeleteJob returns the deletion job with the ID.
+	GetDeleteJob(ctx context.Context, id string) (*deletion.Job, error)
+	// PendingDeleteJobs returns the deletion jobs having pending items in creation order.
+	PendingDeleteJobs(ctx context.Context) ([]deletion.Job, error)
+}
+
+// FileStore provides a file-based implementation of the URLStore interface.
+// Every change is appended to the log file and applied to the in-memory index,
+// so lookups never touch the disk.
+type FileStore struct {
+	fileName string // fileName is the path to the log file.
+	index    Index  // index serves all reads.
+
+	mu    sync.Mutex // mu serializes writes to the log and compaction.
+	log   *os.File   // log is the log file opened for appending.
+	stale int        // stale counts log entries the next compaction will drop.
+
+	clicksMu  sync.Mutex // clicksMu serializes writes to the clicks file.
+	clicksLog *os.File   // clicksLog is the clicks file opened for appending.
+
+	jobsMu    sync.Mutex // jobsMu serializes writes to the deletion jobs journal and its compaction.
+	jobsLog   *os.File   // jobsLog is the deletion jobs journal opened for appending.
+	jobsStale int        // jobsStale counts journal entries the next compaction will fold into the jobs.
+
+	done chan struct{}  // done stops the background compaction.
+	wg   sync.WaitGroup // wg waits for the background compaction to stop.
+}
+
+// NewFileStore opens the log file, replays it into the index and returns a new FileStore.
+// If compactInterval is positive, the log is compacted in the background at that interval.
+//
+// Parameters:
+// - fileName: The path to the log file, it is created if it does not exist.
+// - index: An empty in-memory index to serve reads from.
+// - compactInterval: The interval between background compactions, zero disables them.
+//
+// Returns:
+// - A pointer to a FileStore instance.
+// - An error if the log cannot be opened or replayed.
+func NewFileStore(fileName string, index Index, compactInterval time.Duration) (*FileStore, error) {
+	store := &FileStore{
+		fileName: fileName,
+		index:    index,
+		done:     make(chan struct{}),
+	}
+
+	if err := store.replay(); err != nil {
+		return nil, err
+	}
+
+	log, err := openLog(fileName)
+	if err != nil {
+		return nil, err
+	}
+	store.log = log
+
+	clicksLog, err := openLog(fileName + clicksSuffix)
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+	store.clicksLog = clicksLog
+
+	jobsLog, err := openLog(fileName + jobsSuffix)
+	if err != nil {
+		clicksLog.Close()
+		log.Close()
+		return nil, err
+	}
+	store.jobsLog = jobsLog
+
+	if compactInterval > 0 {
+		store.wg.Add(1)
+		go store.compactLoop(compactInterval)
+	}
+
+	return store, nil
+}
+
+// openLog opens the log file for appending.
+func openLog(fileName string) (*os.File, error) {
+	return os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+}
+
+// replay reads the log, the clicks file and the deletion jobs journal
+// and applies every entry to the index.
+func (store *FileStore) replay() error {
+	ctx := context.Background()
+
+	// Short URLs purged and not created again, their clicks may be left in the clicks file.
+	purged := make(map[string]struct{})
+	err := replayFile(store.fileName, func(entry *logEntry) error {
+		switch entry.Op {
+		case opDelete:
+			// Tombstones written before the deletion time was kept have none.
+			deletedAt := time.Now()
+			if entry.DeletedAt != nil {
+				deletedAt = *entry.DeletedAt
+			}
+			store.index.MarkDeleted([]string{entry.ShortURL}, entry.UserUUID, deletedAt)
+			store.stale++
+		case opRestore:
+			// The record was restored within the retention, so restore it regardless of the deletion time.
+			// Restore entries written before the restoration time was kept have none.
+			restoredAt := time.Now()
+			if entry.UpdatedAt != nil {
+				restoredAt = *entry.UpdatedAt
+			}
+			store.index.MarkRestored([]string{entry.ShortURL}, entry.UserUUID, time.Time{}, restoredAt)
+			store.stale++
+		case opPurge:
+			store.index.Remove(entry.ShortURL)
+			purged[entry.ShortURL] = struct{}{}
+			// Both the creation and the purge entries are dropped by compaction.
+			store.stale += 2
+		case opTags:
+			// The record may have been purged since.
+			// Entries written before the change time was kept have none.
+			store.index.MarkTags(entry.ShortURL, entry.UserUUID, entry.Tags, updatedAt(entry))
+			store.stale++
+		case opWindow:
+			// The record may have been purged since.
+			store.index.MarkActiveWindow(entry.ShortURL, entry.UserUUID, entry.NotBefore, entry.NotAfter, updatedAt(entry))
+			store.stale++
+		case opRules:
+			// The record may have been purged since.
+			store.index.MarkRules(entry.ShortURL, entry.UserUUID, entry.Rules, updatedAt(entry))
+			store.stale++
+		case opClick:
+			// The record may have been purged since.
+			store.index.UseClick(ctx, entry.ShortURL)
+			store.stale++
+		case opUpdate:
+			if entry.Revision != nil {
+				store.index.RestoreRevision(entry.Revision)
+			}
+		case opSeq:
+			store.index.RestoreSequence(entry.Seq)
+		default:
+			store.index.Restore(&entry.URLRecord)
+			delete(purged, entry.ShortURL)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = replayFile(store.fileName+clicksSuffix, func(c *clicks.Click) error {
+		// Skip the clicks of purged records left in the file.
+		if _, ok := purged[c.ShortURL]; ok {
+			return nil
+		}
+		return store.index.SaveClicks(ctx, []clicks.Click{*c})
+	})
+	if err != nil {
+		return err
+	}
+
+	return replayFile(store.fileName+jobsSuffix, func(entry *jobEntry) error {
+		switch {
+		case entry.Job != nil:
+			store.index.RestoreDeleteJob(entry.Job)
+		case len(entry.Items) > 0:
+			store.jobsStale++
+			return store.index.UpdateDeleteJobItems(ctx, entry.JobID, entry.Items)
+		case entry.Seq > 0:
+			store.index.RestoreSequence(entry.Seq)
+		}
+		return nil
+	})
+}
+
+// updatedAt returns the change time of the log entry, or a zero time if it has none.
+func updatedAt(entry *logEntry) time.Time {
+	if entry.UpdatedAt == nil {
+		return time.Time{}
+	}
+	return *entry.UpdatedAt
+}
+
+// replayFile decodes every JSON line of the file and passes it to apply.
+// The file is created if it does not exist.
+// A partially written last line left by a crash is cut off the file.
+func replayFile[T any](fileName string, apply func(*T) error) error {
+	file, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	var offset int64
+	for {
+		var entry T
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			logging.Sugar.Warnw("Truncating incomplete log entry", "file", fileName, "offset", offset)
+			return file.Truncate(offset)
+		}
+		if err != nil {
+			return err
+		}
+		offset = decoder.InputOffset()
+
+		if err := apply(&entry); err != nil {
+			return err
+		}
+	}
+}
+
+// appendEntries writes the entries to the end of the log and flushes them to disk.
+// All entries are written at once so a batch is never interleaved with other writes.
+// The caller must hold store.mu.
+func (store *FileStore) appendEntries(entries ...logEntry) error {
+	return appendLines(store.log, entries)
+}
+
+// snapshot returns copies of the indexed record and of its revisions, or nil if the short URL is unknown,
+// so a change of the record can be reverted if it cannot be written to the log.
+// The caller must hold store.mu.
+func (store *FileStore) snapshot(ctx context.Context, shortURL string) (*URLRecord, []URLRevision) {
+	rec, err := store.index.GetURLRecord(ctx, shortURL)
+	if err != nil {
+		return nil, nil
+	}
+	revisions, _ := store.index.GetURLHistory(ctx, shortURL, rec.UserUUID)
+	return rec, revisions
+}
+
+// SaveURLRecord saves a URLRecord to the index and appends it to the log
+// together with the UUID assigned by the index.
+// If the original URL already exists, it returns the existing short URL and the index error.
+// If the log cannot be written, the record is removed from the index.
+func (store *FileStore) SaveURLRecord(ctx context.Context, urlRecord *URLRecord) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	shortURL, err := store.index.SaveURLRecord(ctx, urlRecord)
+	if err != nil {
+		return shortURL, err
+	}
+
+	if err := store.appendEntries(logEntry{Op: opCreate, URLRecord: *urlRecord}); err != nil {
+		store.index.Remove(urlRecord.ShortURL)
+		return "", err
+	}
+	return shortURL, nil
+}
+
+// SaveURLRecords saves a batch of URLRecords to the index and appends the saved ones
+// to the log in a single write together with the UUIDs assigned by the index.
+// The results and the errors are the ones of the index.
+// If the log cannot be written, the saved records are removed from the index.
+func (store *FileStore) SaveURLRecords(ctx context.Context, records []*URLRecord, atomic bool) ([]SaveResult, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := store.index.SaveURLRecords(ctx, records, atomic)
+	if err != nil {
+		return results, err
+	}
+
+	entries := make([]logEntry, 0, len(records))
+	saved := make([]string, 0, len(records))
+	for i, rec := range records {
+		if results[i].Err == nil {
+			entries = append(entries, logEntry{Op: opCreate, URLRecord: *rec})
+			saved = append(saved, rec.ShortURL)
+		}
+	}
+	if len(entries) > 0 {
+		if err := store.appendEntries(entries...); err != nil {
+			store.index.Remove(saved...)
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// NextSequence returns the next number of the index sequence.
+// The sequence is restored from the record UUIDs in the log and from the last number
+// recorded by the compaction, so the numbers of the records purged since are not reused.
+func (store *FileStore) NextSequence(ctx context.Context) (int64, error) {
+	return store.index.NextSequence(ctx)
+}
+
+// GetURLRecord retrieves the URL record from the index.
+func (store *FileStore) GetURLRecord(ctx context.Context, shortURL string) (*URLRecord, error) {
+	return store.index.GetURLRecord(ctx, shortURL)
+}
+
+// GetUserURLs retrieves a page of the URL records associated with a specific user ID from the index.
+func (store *FileStore) GetUserURLs(ctx context.Context, userID string, q URLQuery) (URLPage, error) {
+	return store.index.GetUserURLs(ctx, userID, q)
+}
+
+// BatchUpdateDeleteFlag appends tombstones for all short URLs to the log in a single write
+// and marks the records as deleted in the index if they belong to the user.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - urlIDs: The short URLs of the records to be marked as deleted.
+// - userID: The user ID associated with the URL records.
+//
+// Returns:
+// - An error if writing the tombstones fails.
+func (store *FileStore) BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error {
+	if len(urlIDs) == 0 {
+		return nil
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	tombstones := make([]logEntry, 0, len(urlIDs))
+	for _, urlID := range urlIDs {
+		tombstones = append(tombstones, logEntry{Op: opDelete, URLRecord: URLRecord{ShortURL: urlID, UserUUID: userID, DeletedAt: &now}})
+	}
+	if err := store.appendEntries(tombstones...); err != nil {
+		return err
+	}
+	store.stale += len(tombstones)
+
+	store.index.MarkDeleted(urlIDs, userID, now)
+	return nil
+}
+
+// RestoreURLs clears the deletion mark of the user's records in the index
+// and appends restore entries for the restored ones to the log in a single write.
+// If the log cannot be written, the restoration is reverted in the index.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - urlIDs: The short URLs of the records to be restored.
+// - userID: The user ID associated with the URL records.
+// - deletedSince: The earliest deletion time of the records that can be restored.
+//
+// Returns:
+// - The short URLs of the restored records.
+// - An error if writing the log fails.
+func (store *FileStore) RestoreURLs(ctx context.Context, urlIDs []string, userID string, deletedSince time.Time) ([]string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	type recordSnapshot struct {
+		rec       *URLRecord
+		revisions []URLRevision
+	}
+	snapshots := make(map[string]recordSnapshot, len(urlIDs))
+	for _, urlID := range urlIDs {
+		if rec, revisions := store.snapshot(ctx, urlID); rec != nil {
+			snapshots[urlID] = recordSnapshot{rec: rec, revisions: revisions}
+		}
+	}
+
+	now := time.Now()
+	restored := store.index.MarkRestored(urlIDs, userID, deletedSince, now)
+	if len(restored) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]logEntry, 0, len(restored))
+	for _, shortURL := range restored {
+		entries = append(entries, logEntry{Op: opRestore, URLRecord: URLRecord{ShortURL: shortURL, UserUUID: userID, UpdatedAt: &now}})
+	}
+	if err := store.appendEntries(entries...); err != nil {
+		for _, shortURL := range restored {
+			store.index.Revert(snapshots[shortURL].rec, snapshots[shortURL].revisions)
+		}
+		return nil, err
+	}
+	store.stale += len(entries)
+	return restored, nil
+}
+
+// PurgeDeleted removes the records deleted before the given time from the index, appends purge entries
+// to the log and rewrites the clicks file without their clicks.
+// If the clicks file cannot be rewritten, the clicks of the purged records are skipped on restart.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - deletedBefore: The time the records must have been deleted before.
+//
+// Returns:
+// - A number of removed records.
+// - An error if writing the log or the clicks file fails.
+func (store *FileStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := store.index.DeletedBefore(deletedBefore)
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	entries := make([]logEntry, 0, len(purged))
+	for _, shortURL := range purged {
+		entries = append(entries, logEntry{Op: opPurge, URLRecord: URLRecord{ShortURL: shortURL}})
+	}
+	if err := store.appendEntries(entries...); err != nil {
+		return 0, err
+	}
+	store.stale += 2 * len(purged)
+
+	store.index.Remove(purged...)
+	return len(purged), store.rewriteClicks()
+}
+
+// rewriteClicks replaces the clicks file with a snapshot of the indexed clicks
+// the same way Compact replaces the log.
+func (store *FileStore) rewriteClicks() error {
+	store.clicksMu.Lock()
+	defer store.clicksMu.Unlock()
+
+	fileName := store.fileName + clicksSuffix
+	tmpName := fileName + ".tmp"
+	if err := writeLines(tmpName, store.index.Clicks()); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, fileName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := syncDir(filepath.Dir(fileName)); err != nil {
+		return err
+	}
+
+	clicksLog, err := openLog(fileName)
+	if err != nil {
+		return err
+	}
+	store.clicksLog.Close()
+	store.clicksLog = clicksLog
+	return nil
+}
+
+// UpdateOriginalURL changes the original URL of the user's short URL in the index
+// and appends the revision to the log. The errors are the ones of the index.
+// If the log cannot be written, the change is reverted in the index.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - originalURL: The new original URL.
+//
+// Returns:
+// - The recorded revision.
+// - An error if the short URL cannot be changed or writing the log fails.
+func (store *FileStore) UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*URLRevision, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prev, revisions := store.snapshot(ctx, shortURL)
+	rev, err := store.index.UpdateOriginalURL(ctx, shortURL, userID, originalURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.appendEntries(logEntry{Op: opUpdate, Revision: rev}); err != nil {
+		store.index.Revert(prev, revisions)
+		return nil, err
+	}
+	return rev, nil
+}
+
+// SetTags replaces the tags of the user's short URL in the index and appends the change to the log.
+// The errors are the ones of the index.
+// If the log cannot be written, the change is reverted in the index.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be tagged.
+// - userID: The user ID tagging the short URL, it must own the short URL.
+// - tags: The new tags of the short URL.
+//
+// Returns:
+// - An error if the short URL cannot be tagged or writing the log fails.
+func (store *FileStore) SetTags(ctx context.Context, shortURL, userID string, tags []string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	prev, revisions := store.snapshot(ctx, shortURL)
+	now := time.Now()
+	if err := store.index.MarkTags(shortURL, userID, tags, now); err != nil {
+		return err
+	}
+
+	entry := logEntry{Op: opTags, URLRecord: URLRecord{ShortURL: shortURL, UserUUID: userID, Tags: tags, UpdatedAt: &now}}
+	if err := store.appendEntries(entry); err != nil {
+		store.index.Revert(prev, revisions)
+		return err
+	}
+	store.stale++
+	return nil
+}
+
+// SetActiveWindow changes the activation window of the user's short URL in the index
+// and appends the change to the log. The errors are the ones of the index.
+// If the log cannot be written, the change is reverted in the index.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - notBefore: The time the short URL becomes active at, nil if it is active right away.
+// - notAfter: The time the short URL stops being active at, nil if it stays active.
+//
+// Returns:
+// - An error if the short URL cannot be changed or writing the log fails.
+func (store *FileStore) SetActiveWindow(ctx context.Context, shortURL, userID string, notBefore, notAfter *time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	prev, revisions := store.snapshot(ctx, shortURL)
+	now := time.Now()
+	if err := store.index.MarkActiveWindow(shortURL, userID, notBefore, notAfter, now); err != nil {
+		return err
+	}
+
+	entry := logEntry{Op: opWindow, URLRecord: URLRecord{
+		ShortURL: shortURL, UserUUID: userID, NotBefore: notBefore, NotAfter: notAfter, UpdatedAt: &now,
+	}}
+	if err := store.appendEntries(entry); err != nil {
+		store.index.Revert(prev, revisions)
+		return err
+	}
+	store.stale++
+	return nil
+}
+
+// SetRules replaces the redirect rules of the user's short URL in the index and appends the change to the log.
+// The errors are the ones of the index.
+// If the log cannot be written, the change is reverted in the index.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - shortURL: The short URL to be changed.
+// - userID: The user ID changing the short URL, it must own the short URL.
+// - rules: The new redirect rules of the short URL.
+//
+// Returns:
+// - An error if the short URL cannot be changed or writing the log fails.
+func (store *FileStore) SetRules(ctx context.Context, shortURL, userID string, rules []RedirectRule) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	prev, revisions := store.snapshot(ctx, shortURL)
+	now := time.Now()
+	if err := store.index.MarkRules(shortURL, userID, rules, now); err != nil {
+		return err
+	}
+
+	entry := logEntry{Op: opRules, URLRecord: URLRecord{ShortURL: shortURL, UserUUID: userID, Rules: rules, UpdatedAt: &now}}
+	if err := store.appendEntries(entry); err != nil {
+		store.index.Revert(prev, revisions)
+		return err
+	}
+	store.stale++
+	return nil
+}
+
+// UseClick counts a redirect of the short URL against its click limit in the index
+// and appends the redirect to the log. The check and the count are made under the log lock,
+// so concurrent redirects never exceed the limit. Redirects of short URLs without a limit are not logged.
+// If the log cannot be written, the redirect is not counted.
+// It returns os.ErrProcessDone if the short URL is unknown and database.ErrorExhausted
+// if the limit is already reached.
+func (store *FileStore) UseClick(ctx context.Context, shortURL string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rec, err := store.index.GetURLRecord(ctx, shortURL)
+	if err != nil {
+		return err
+	}
+	if rec.MaxClicks == 0 {
+		return nil
+	}
+	revisions, _ := store.index.GetURLHistory(ctx, shortURL, rec.UserUUID)
+	if err := store.index.UseClick(ctx, shortURL); err != nil {
+		return err
+	}
+
+	if err := store.appendEntries(logEntry{Op: opClick, URLRecord: URLRecord{ShortURL: shortURL}}); err != nil {
+		store.index.Revert(rec, revisions)
+		return err
+	}
+	store.stale++
+	return nil
+}
+
+// GetTagStats counts the user's short URLs and their clicks by tags in the index.
+func (store *FileStore) GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error) {
+	return store.index.GetTagStats(ctx, userID)
+}
+
+// GetURLHistory retrieves the revisions of the user's short URL from the index.
+func (store *FileStore) GetURLHistory(ctx context.Context, shortURL, userID string) ([]URLRevision, error) {
+	return store.index.GetURLHistory(ctx, shortURL, userID)
+}
+
+// GetURLsCount counts shortened URLs in the index.
+func (store *FileStore) GetURLsCount(ctx context.Context) (int, error) {
+	return store.index.GetURLsCount(ctx)
+}
+
+// GetUsersCount counts unique users in the index.
+func (store *FileStore) GetUsersCount(ctx context.Context) (int, error) {
+	return store.index.GetUsersCount(ctx)
+}
+
+// DeleteExpired removes the records expired by now from the index and appends purge entries to the log.
+// With the archive policy the records are first appended to the archive file next to the log,
+// so a crash in between may archive a record twice but never loses it.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - now: The time to check expiration against.
+// - policy: One of config.Expired* values, whether the records are deleted or archived.
+//
+// Returns:
+// - A number of removed records.
+// - An error if writing the archive or the log fails.
+func (store *FileStore) DeleteExpired(ctx context.Context, now time.Time, policy string) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Give up if the context expired while waiting for a compaction.
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var expired []URLRecord
+	for _, rec := range store.index.Records() {
+		if rec.IsExpired(now) {
+			expired = append(expired, rec)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	if policy == config.ExpiredArchive {
+		if err := appendArchive(store.fileName+archiveSuffix, expired); err != nil {
+			return 0, err
+		}
+	}
+
+	entries := make([]logEntry, 0, len(expired))
+	shortURLs := make([]string, 0, len(expired))
+	for _, rec := range expired {
+		entries = append(entries, logEntry{Op: opPurge, URLRecord: URLRecord{ShortURL: rec.ShortURL}})
+		shortURLs = append(shortURLs, rec.ShortURL)
+	}
+	if err := store.appendEntries(entries...); err != nil {
+		return 0, err
+	}
+	store.stale += 2 * len(expired)
+
+	store.index.Remove(shortURLs...)
+	return len(expired), nil
+}
+
+// appendArchive appends the records to the archive file and flushes it to disk.
+func appendArchive(fileName string, records []URLRecord) error {
+	file, err := openLog(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return appendLines(file, records)
+}
+
+// appendLines writes the values to the end of the file as JSON lines at once
+// and flushes them to disk.
+func appendLines[T any](file *os.File, values []T) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// SaveClicks appends the click events to the clicks file and adds them to the index.
+func (store *FileStore) SaveClicks(ctx context.Context, events []clicks.Click) error {
+	store.clicksMu.Lock()
+	defer store.clicksMu.Unlock()
+
+	if err := appendLines(store.clicksLog, events); err != nil {
+		return err
+	}
+	return store.index.SaveClicks(ctx, events)
+}
+
+// GetClickStats aggregates the clicks of the short URL from the index.
+func (store *FileStore) GetClickStats(ctx context.Context, q clicks.Query) (clicks.Stats, error) {
+	return store.index.GetClickStats(ctx, q)
+}
+
+// SaveDeleteJob assigns the deletion job an ID from the index sequence,
+// appends it to the deletion jobs journal and adds it to the index.
+func (store *FileStore) SaveDeleteJob(ctx context.Context, job *deletion.Job) error {
+	store.jobsMu.Lock()
+	defer store.jobsMu.Unlock()
+
+	id, err := store.index.NextSequence(ctx)
+	if err != nil {
+		return err
+	}
+	job.ID = strconv.FormatInt(id, 10)
+
+	if err := appendLines(store.jobsLog, []jobEntry{{Job: job}}); err != nil {
+		return err
+	}
+	store.index.RestoreDeleteJob(job)
+	return nil
+}
+
+// UpdateDeleteJobItems appends the new item statuses to the deletion jobs journal as a single entry
+// and applies them to the index.
+func (store *FileStore) UpdateDeleteJobItems(ctx context.Context, jobID string, items []deletion.Item) error {
+	store.jobsMu.Lock()
+	defer store.jobsMu.Unlock()
+
+	if err := appendLines(store.jobsLog, []jobEntry{{JobID: jobID, Items: items}}); err != nil {
+		return err
+	}
+	store.jobsStale++
+	return store.index.UpdateDeleteJobItems(ctx, jobID, items)
+}
+
+// GetDeleteJob retrieves the deletion job from the index.
+func (store *FileStore) GetDeleteJob(ctx context.Context, id string) (*deletion.Job, error) {
+	return store.index.GetDeleteJob(ctx, id)
+}
+
+// PendingDeleteJobs retrieves the deletion jobs having pending items from the index.
+func (store *FileStore) PendingDeleteJobs(ctx context.Context) ([]deletion.Job, error) {
+	return store.index.PendingDeleteJobs(ctx)
+}
+
+// Compact rewrites the log as a snapshot holding the last number of the index sequence,
+// one creation entry per record and the update entries of their revisions, which keep the history.
+// The snapshot is written to a temporary file, flushed to disk and atomically renamed
+// over the log, so a crash leaves either the old or the new log intact.
+// Compaction is skipped if the log has no stale entries.
+// The deletion jobs journal is compacted as well.
+func (store *FileStore) Compact() error {
+	if err := store.compactJobs(); err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.stale == 0 {
+		return nil
+	}
+
+	tmpName := store.fileName + ".tmp"
+	if err := writeSnapshot(tmpName, store.index.Sequence(), store.index.Records(), store.index.Revisions()); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, store.fileName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	// Persist the rename itself.
+	if err := syncDir(filepath.Dir(store.fileName)); err != nil {
+		return err
+	}
+
+	// Switch appends to the new log.
+	log, err := openLog(store.fileName)
+	if err != nil {
+		return err
+	}
+	store.log.Close()
+	store.log = log
+	store.stale = 0
+
+	return nil
+}
+
+// compactJobs rewrites the deletion jobs journal the same way Compact rewrites the log,
+// keeping only the jobs having pending items with their current item statuses.
+// The finished jobs are dropped, they are only available until restart.
+// Compaction is skipped if no item statuses were appended since the last one.
+func (store *FileStore) compactJobs() error {
+	store.jobsMu.Lock()
+	defer store.jobsMu.Unlock()
+
+	if store.jobsStale == 0 {
+		return nil
+	}
+
+	pending, err := store.index.PendingDeleteJobs(context.Background())
+	if err != nil {
+		return err
+	}
+	entries := make([]jobEntry, 0, len(pending)+1)
+	entries = append(entries, jobEntry{Seq: store.index.Sequence()})
+	for i := range pending {
+		entries = append(entries, jobEntry{Job: &pending[i]})
+	}
+
+	fileName := store.fileName + jobsSuffix
+	tmpName := fileName + ".tmp"
+	if err := writeLines(tmpName, entries); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, fileName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := syncDir(filepath.Dir(fileName)); err != nil {
+		return err
+	}
+
+	jobsLog, err := openLog(fileName)
+	if err != nil {
+		return err
+	}
+	store.jobsLog.Close()
+	store.jobsLog = jobsLog
+	store.jobsStale = 0
+	return nil
+}
+
+// writeSnapshot writes the last number of the sequence, the records and then the revisions to a new file
+// and flushes it to disk. Replaying the revisions of a record in order results in its current original URL.
+func writeSnapshot(fileName string, seq int64, records []URLRecord, revisions []URLRevision) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(logEntry{Op: opSeq, Seq: seq}); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		if err := encoder.Encode(logEntry{Op: opCreate, URLRecord: rec}); err != nil {
+			return err
+		}
+	}
+	for i := range revisions {
+		if err := encoder.Encode(logEntry{Op: opUpdate, Revision: &revisions[i]}); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// writeLines writes the values to a new file as JSON lines and flushes it to disk.
+func writeLines[T any](fileName string, values []T) error {
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// syncDir flushes the directory entry changes to disk.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// compactLoop compacts the log at the given interval until the store is closed.
+func (store *FileStore) compactLoop(interval time.Duration) {
+	defer store.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-store.done:
+			return
+		case <-ticker.C:
+			if err := store.Compact(); err != nil {
+				logging.Sugar.Errorw("Failed to compact storage log", "file", store.fileName, "error", err)
+			}
+		}
+	}
+}
+
+// Close stops the background compaction and closes the log, clicks and deletion jobs files.
+func (store *FileStore) Close() error {
+	close(store.done)
+	store.wg.Wait()
+
+	store.clicksMu.Lock()
+	store.clicksLog.Close()
+	store.clicksMu.Unlock()
+
+	store.jobsMu.Lock()
+	store.jobsLog.Close()
+	store.jobsMu.Unlock()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.log.Close()
+}
@@ -0,0 +1,528 @@
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+	"github.com/KirillZiborov/lnkshortener/internal/logging"
+	"github.com/KirillZiborov/lnkshortener/internal/memory"
+)
+
+func openStore(t *testing.T, fileName string) *file.FileStore {
+	store, err := file.NewFileStore(fileName, memory.NewMemoryStore(config.DedupGlobal), 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func countLines(t *testing.T, fileName string) int {
+	data, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+	count := 0
+	for _, b := range data {
+		if b == '\n' {
+			count++
+		}
+	}
+	return count
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	for _, rec := range []file.URLRecord{
+		{UUID: "1", ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{UUID: "2", ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1"},
+		{UUID: "3", ShortURL: "http://localhost:8080/c", OriginalURL: "https://c.ru", UserUUID: "user2"},
+	} {
+		_, err := store.SaveURLRecord(ctx, &rec)
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/a"}, "user1"))
+	// Tombstones for foreign URLs are written but have no effect.
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/c"}, "user1"))
+	assert.Equal(t, 5, countLines(t, fileName))
+
+	t.Run("replay", func(t *testing.T) {
+		replayed := openStore(t, fileName)
+
+		rec, err := replayed.GetURLRecord(ctx, "http://localhost:8080/a")
+		require.NoError(t, err)
+		assert.Equal(t, "https://a.ru", rec.OriginalURL)
+		assert.True(t, rec.DeletedFlag)
+
+		rec, err = replayed.GetURLRecord(ctx, "http://localhost:8080/c")
+		require.NoError(t, err)
+		assert.False(t, rec.DeletedFlag)
+
+		page, err := replayed.GetUserURLs(ctx, "user1", file.URLQuery{})
+		require.NoError(t, err)
+		assert.Len(t, page.Records, 2)
+	})
+
+	t.Run("compaction", func(t *testing.T) {
+		// The sequence entry and the three records.
+		require.NoError(t, store.Compact())
+		assert.Equal(t, 4, countLines(t, fileName))
+
+		// Appends after compaction go to the new log.
+		_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+			UUID: "4", ShortURL: "http://localhost:8080/d", OriginalURL: "https://d.ru", UserUUID: "user2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, countLines(t, fileName))
+
+		compacted := openStore(t, fileName)
+		rec, err := compacted.GetURLRecord(ctx, "http://localhost:8080/a")
+		require.NoError(t, err)
+		assert.True(t, rec.DeletedFlag)
+
+		count, err := compacted.GetURLsCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+}
+
+func TestFileStoreTornWrite(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	// A log written by an older version followed by an entry cut off by a crash.
+	data := `{"uuid":"1","short_url":"http://localhost:8080/a","original_url":"https://a.ru","user_uuid":"user1","deleted":false}
+{"op":"create","uuid":"2","short_url":"http://loc`
+	require.NoError(t, os.WriteFile(fileName, []byte(data), 0666))
+
+	store := openStore(t, fileName)
+	count, err := store.GetURLsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+		UUID: "2", ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1",
+	})
+	require.NoError(t, err)
+
+	replayed := openStore(t, fileName)
+	count, err = replayed.GetURLsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestFileStoreDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Hour)
+
+	store := openStore(t, fileName)
+	for _, rec := range []file.URLRecord{
+		{UUID: "1", ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1", ExpiresAt: &past},
+		{UUID: "2", ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1", ExpiresAt: &future},
+		{UUID: "3", ShortURL: "http://localhost:8080/c", OriginalURL: "https://c.ru", UserUUID: "user2"},
+	} {
+		_, err := store.SaveURLRecord(ctx, &rec)
+		require.NoError(t, err)
+	}
+
+	n, err := store.DeleteExpired(ctx, now, config.ExpiredArchive)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, countLines(t, fileName+".archive"))
+
+	_, err = store.GetURLRecord(ctx, "http://localhost:8080/a")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+
+	// The purge survives a restart.
+	replayed := openStore(t, fileName)
+	count, err := replayed.GetURLsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Compaction drops both the creation and the purge entries.
+	require.NoError(t, store.Compact())
+	assert.Equal(t, 3, countLines(t, fileName))
+}
+
+func TestFileStoreSequence(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	expiresAt := time.Now().Add(-time.Minute)
+	_, err := store.SaveURLRecords(ctx, []*file.URLRecord{
+		{ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1", ExpiresAt: &expiresAt},
+	}, true)
+	require.NoError(t, err)
+	last, err := store.NextSequence(ctx)
+	require.NoError(t, err)
+
+	// The numbers of the purged records are not reused after a compaction and a restart.
+	_, err = store.DeleteExpired(ctx, time.Now(), config.ExpiredPurge)
+	require.NoError(t, err)
+	require.NoError(t, store.Compact())
+	next, err := openStore(t, fileName).NextSequence(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, next, last)
+}
+
+func TestFileStoreClicks(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	now := time.Now().UTC()
+	store := openStore(t, fileName)
+	require.NoError(t, store.SaveClicks(ctx, []clicks.Click{
+		{ShortURL: "http://localhost:8080/a", Time: now, IP: "10.0.0.0"},
+		{ShortURL: "http://localhost:8080/a", Time: now},
+		{ShortURL: "http://localhost:8080/b", Time: now},
+	}))
+
+	// Clicks survive a restart.
+	replayed := openStore(t, fileName)
+	stats, err := replayed.GetClickStats(ctx, clicks.Query{ShortURL: "http://localhost:8080/a", Bucket: clicks.BucketDay})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Total)
+	require.Len(t, stats.Buckets, 1)
+	assert.Equal(t, 2, stats.Buckets[0].Clicks)
+}
+
+func TestFileStoreDeleteJobs(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	job := deletion.NewJob("user1", []string{"http://localhost:8080/a", "http://localhost:8080/b"}, time.Now().UTC())
+	require.NoError(t, store.SaveDeleteJob(ctx, job))
+	require.NotEmpty(t, job.ID)
+	require.NoError(t, store.UpdateDeleteJobItems(ctx, job.ID,
+		[]deletion.Item{{ShortURL: "http://localhost:8080/a", Status: deletion.StatusDone}}))
+
+	// Jobs and item statuses survive a restart.
+	replayed := openStore(t, fileName)
+	got, err := replayed.GetDeleteJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", got.UserID)
+	assert.Equal(t, deletion.Counts{Pending: 1, Done: 1}, got.Counts())
+
+	pending, err := replayed.PendingDeleteJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, job.ID, pending[0].ID)
+
+	// Restored job IDs are not assigned again.
+	next := deletion.NewJob("user1", []string{"http://localhost:8080/c"}, time.Now().UTC())
+	require.NoError(t, replayed.SaveDeleteJob(ctx, next))
+	assert.NotEqual(t, job.ID, next.ID)
+}
+
+func TestFileStoreCompactDeleteJobs(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	pending := deletion.NewJob("user1", []string{"http://localhost:8080/b", "http://localhost:8080/c"}, time.Now().UTC())
+	require.NoError(t, store.SaveDeleteJob(ctx, pending))
+	finished := deletion.NewJob("user1", []string{"http://localhost:8080/a"}, time.Now().UTC())
+	require.NoError(t, store.SaveDeleteJob(ctx, finished))
+	require.NoError(t, store.UpdateDeleteJobItems(ctx, finished.ID,
+		[]deletion.Item{{ShortURL: "http://localhost:8080/a", Status: deletion.StatusDone}}))
+	require.NoError(t, store.UpdateDeleteJobItems(ctx, pending.ID,
+		[]deletion.Item{{ShortURL: "http://localhost:8080/b", Status: deletion.StatusDone}}))
+	assert.Equal(t, 4, countLines(t, fileName+".jobs"))
+
+	// Only the sequence and the pending job with its current item statuses are kept.
+	require.NoError(t, store.Compact())
+	assert.Equal(t, 2, countLines(t, fileName+".jobs"))
+
+	// Appends go to the compacted journal.
+	require.NoError(t, store.UpdateDeleteJobItems(ctx, pending.ID,
+		[]deletion.Item{{ShortURL: "http://localhost:8080/c", Status: deletion.StatusFailed, Error: "failed"}}))
+	assert.Equal(t, 3, countLines(t, fileName+".jobs"))
+
+	replayed := openStore(t, fileName)
+	_, err := replayed.GetDeleteJob(ctx, finished.ID)
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+	got, err := replayed.GetDeleteJob(ctx, pending.ID)
+	require.NoError(t, err)
+	assert.Equal(t, deletion.Counts{Done: 1, Failed: 1}, got.Counts())
+
+	// The ID of the dropped job, the last one assigned, is not assigned again.
+	next := deletion.NewJob("user1", []string{"http://localhost:8080/d"}, time.Now().UTC())
+	require.NoError(t, replayed.SaveDeleteJob(ctx, next))
+	assert.NotEqual(t, finished.ID, next.ID)
+}
+
+func TestFileStoreLogWriteError(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1", MaxClicks: 2, Tags: []string{"news"},
+	})
+	require.NoError(t, err)
+	_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/d", OriginalURL: "https://d.ru", UserUUID: "user1",
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/d"}, "user1"))
+	before, err := store.GetURLRecord(ctx, "http://localhost:8080/a")
+	require.NoError(t, err)
+
+	// Changes that cannot be written to the log are not applied to the index either.
+	file.CloseLog(store)
+
+	_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1",
+	})
+	require.Error(t, err)
+	_, err = store.SaveURLRecords(ctx, []*file.URLRecord{
+		{ShortURL: "http://localhost:8080/c", OriginalURL: "https://c.ru", UserUUID: "user1"},
+	}, false)
+	require.Error(t, err)
+	for _, shortURL := range []string{"http://localhost:8080/b", "http://localhost:8080/c"} {
+		_, err = store.GetURLRecord(ctx, shortURL)
+		assert.ErrorIs(t, err, os.ErrProcessDone)
+	}
+
+	_, err = store.UpdateOriginalURL(ctx, "http://localhost:8080/a", "user1", "https://e.ru")
+	require.Error(t, err)
+	require.Error(t, store.SetTags(ctx, "http://localhost:8080/a", "user1", []string{"docs"}))
+	notAfter := time.Now().Add(time.Hour)
+	require.Error(t, store.SetActiveWindow(ctx, "http://localhost:8080/a", "user1", nil, &notAfter))
+	require.Error(t, store.SetRules(ctx, "http://localhost:8080/a", "user1",
+		[]file.RedirectRule{{Device: "ios", Target: "https://m.a.ru"}}))
+	require.Error(t, store.UseClick(ctx, "http://localhost:8080/a"))
+
+	rec, err := store.GetURLRecord(ctx, "http://localhost:8080/a")
+	require.NoError(t, err)
+	assert.Equal(t, before, rec)
+	history, err := store.GetURLHistory(ctx, "http://localhost:8080/a", "user1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	stats, err := store.GetTagStats(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, []clicks.TagStats{{Tag: "news", URLs: 1}}, stats)
+	// The original URL is still indexed for deduplication.
+	shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/f", OriginalURL: "https://a.ru", UserUUID: "user2",
+	})
+	assert.ErrorIs(t, err, database.ErrorDuplicate)
+	assert.Equal(t, "http://localhost:8080/a", shortURL)
+
+	_, err = store.RestoreURLs(ctx, []string{"http://localhost:8080/d"}, "user1", time.Now().Add(-time.Hour))
+	require.Error(t, err)
+	rec, err = store.GetURLRecord(ctx, "http://localhost:8080/d")
+	require.NoError(t, err)
+	assert.True(t, rec.DeletedFlag)
+	assert.NotNil(t, rec.DeletedAt)
+}
+
+func TestFileStoreUpdateOriginalURL(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1",
+	})
+	require.NoError(t, err)
+	_, err = store.UpdateOriginalURL(ctx, "http://localhost:8080/a", "user1", "https://b.ru")
+	require.NoError(t, err)
+	_, err = store.UpdateOriginalURL(ctx, "http://localhost:8080/a", "user1", "https://c.ru")
+	require.NoError(t, err)
+
+	check := func(t *testing.T, store *file.FileStore) {
+		rec, err := store.GetURLRecord(ctx, "http://localhost:8080/a")
+		require.NoError(t, err)
+		assert.Equal(t, "https://c.ru", rec.OriginalURL)
+
+		history, err := store.GetURLHistory(ctx, "http://localhost:8080/a", "user1")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "https://a.ru", history[0].OldURL)
+		assert.Equal(t, "https://c.ru", history[1].NewURL)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		check(t, openStore(t, fileName))
+	})
+
+	t.Run("compaction", func(t *testing.T) {
+		// Make the log stale, so it is compacted.
+		require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/unknown"}, "user1"))
+		require.NoError(t, store.Compact())
+		assert.Equal(t, 4, countLines(t, fileName))
+		check(t, openStore(t, fileName))
+	})
+}
+
+func TestFileStoreRestoreAndPurge(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	for _, rec := range []file.URLRecord{
+		{UUID: "1", ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{UUID: "2", ShortURL: "http://localhost:8080/b", OriginalURL: "https://b.ru", UserUUID: "user1"},
+	} {
+		_, err := store.SaveURLRecord(ctx, &rec)
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.SaveClicks(ctx, []clicks.Click{
+		{ShortURL: "http://localhost:8080/a", Time: time.Now()},
+		{ShortURL: "http://localhost:8080/b", Time: time.Now()},
+	}))
+	shortURLs := []string{"http://localhost:8080/a", "http://localhost:8080/b"}
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, shortURLs, "user1"))
+
+	// Records deleted before the retention cannot be restored, foreign ones are never restored.
+	restored, err := store.RestoreURLs(ctx, shortURLs, "user1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+	restored, err = store.RestoreURLs(ctx, shortURLs, "user2", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, restored)
+
+	restored, err = store.RestoreURLs(ctx, shortURLs[:1], "user1", time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, shortURLs[:1], restored)
+
+	n, err := store.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, countLines(t, fileName+".clicks"))
+
+	// The restoration and the purge survive a restart.
+	replayed := openStore(t, fileName)
+	rec, err := replayed.GetURLRecord(ctx, "http://localhost:8080/a")
+	require.NoError(t, err)
+	assert.False(t, rec.DeletedFlag)
+	assert.Nil(t, rec.DeletedAt)
+	assert.NotNil(t, rec.UpdatedAt)
+	_, err = replayed.GetURLRecord(ctx, "http://localhost:8080/b")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+
+	stats, err := replayed.GetClickStats(ctx, clicks.Query{ShortURL: "http://localhost:8080/b", Bucket: clicks.BucketDay})
+	require.NoError(t, err)
+	assert.Zero(t, stats.Total)
+}
+
+func TestFileStoreMetadata(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1",
+		Title: "A", Notes: "Notes on A",
+	})
+	require.NoError(t, err)
+	clickedAt := time.Now().Add(-time.Minute).UTC()
+	notBefore := time.Now().Add(time.Hour).UTC()
+	rules := []file.RedirectRule{
+		{Device: "ios", Target: "https://apps.apple.com/a"},
+		{Language: "de", Query: map[string]string{"ref": ""}, Target: "https://a.de"},
+	}
+	require.NoError(t, store.SaveClicks(ctx, []clicks.Click{{ShortURL: "http://localhost:8080/a", Time: clickedAt}}))
+	var updatedAt time.Time
+
+	check := func(t *testing.T, store *file.FileStore) {
+		rec, err := store.GetURLRecord(ctx, "http://localhost:8080/a")
+		require.NoError(t, err)
+		assert.Equal(t, "A", rec.Title)
+		assert.Equal(t, "Notes on A", rec.Notes)
+		assert.False(t, rec.CreatedAt.IsZero())
+		require.NotNil(t, rec.LastAccessedAt)
+		assert.True(t, clickedAt.Equal(*rec.LastAccessedAt))
+		assert.Equal(t, []string{"docs", "news"}, rec.Tags)
+		require.NotNil(t, rec.NotBefore)
+		assert.True(t, notBefore.Equal(*rec.NotBefore))
+		assert.Nil(t, rec.NotAfter)
+		assert.Equal(t, rules, rec.Rules)
+		require.NotNil(t, rec.UpdatedAt)
+		assert.True(t, updatedAt.Equal(*rec.UpdatedAt))
+
+		page, err := store.GetUserURLs(ctx, "user1", file.URLQuery{Tag: "news"})
+		require.NoError(t, err)
+		assert.Len(t, page.Records, 1)
+	}
+	require.NoError(t, store.SetTags(ctx, "http://localhost:8080/a", "user1", []string{"docs", "news"}))
+	require.NoError(t, store.SetActiveWindow(ctx, "http://localhost:8080/a", "user1", &notBefore, nil))
+	assert.ErrorIs(t, store.SetActiveWindow(ctx, "http://localhost:8080/a", "user2", nil, nil), database.ErrorNotOwner)
+	require.NoError(t, store.SetRules(ctx, "http://localhost:8080/a", "user1", rules))
+	assert.ErrorIs(t, store.SetRules(ctx, "http://localhost:8080/a", "user2", nil), database.ErrorNotOwner)
+	rec, err := store.GetURLRecord(ctx, "http://localhost:8080/a")
+	require.NoError(t, err)
+	require.NotNil(t, rec.UpdatedAt)
+	updatedAt = *rec.UpdatedAt
+	check(t, store)
+
+	// The metadata survives a restart and a compaction.
+	check(t, openStore(t, fileName))
+	require.NoError(t, store.Compact())
+	check(t, openStore(t, fileName))
+}
+
+func TestFileStoreUseClick(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, logging.Initialize())
+	fileName := filepath.Join(t.TempDir(), "storage.json")
+
+	store := openStore(t, fileName)
+	for _, rec := range []*file.URLRecord{
+		{ShortURL: "http://localhost:8080/once", OriginalURL: "https://a.ru", UserUUID: "user1", MaxClicks: 2},
+		{ShortURL: "http://localhost:8080/free", OriginalURL: "https://b.ru", UserUUID: "user1"},
+	} {
+		_, err := store.SaveURLRecord(ctx, rec)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.UseClick(ctx, "http://localhost:8080/once"))
+	require.NoError(t, store.UseClick(ctx, "http://localhost:8080/free"))
+	assert.ErrorIs(t, store.UseClick(ctx, "http://localhost:8080/none"), os.ErrProcessDone)
+
+	// The used clicks survive a restart and a compaction.
+	usedClicks := func(store *file.FileStore) int {
+		rec, err := store.GetURLRecord(ctx, "http://localhost:8080/once")
+		require.NoError(t, err)
+		return rec.UsedClicks
+	}
+	assert.Equal(t, 1, usedClicks(openStore(t, fileName)))
+	require.NoError(t, store.Compact())
+	reopened := openStore(t, fileName)
+	assert.Equal(t, 1, usedClicks(reopened))
+
+	require.NoError(t, reopened.UseClick(ctx, "http://localhost:8080/once"))
+	assert.ErrorIs(t, reopened.UseClick(ctx, "http://localhost:8080/once"), database.ErrorExhausted)
+
+	rec, err := reopened.GetURLRecord(ctx, "http://localhost:8080/free")
+	require.NoError(t, err)
+	assert.Zero(t, rec.UsedClicks)
+}
@@ -0,0 +1,66 @@
+// Package idgen provides generators of short URL IDs.
+//
+// Random generates random base62 IDs of a fixed length, Sequence encodes numbers
+// taken from a storage sequence into obfuscated base62 IDs, and Retry wraps
+// any generator to skip IDs that are already taken.
+package idgen
+
+import (
+	"context"
+	"errors"
+)
+
+// base62 is the alphabet of generated IDs, it is safe to use in URL paths as is.
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Generator generates short URL IDs.
+type Generator interface {
+	// NextID returns a new short URL ID.
+	NextID(ctx context.Context) (string, error)
+}
+
+// ErrNoFreeID is returned by Retry when every generated ID is taken.
+var ErrNoFreeID = errors.New("no free ID found")
+
+// Retry is a Generator skipping IDs that are already taken.
+// It does not reserve the returned ID, so the storage must still reject duplicates.
+type Retry struct {
+	next     Generator
+	taken    func(ctx context.Context, id string) (bool, error)
+	attempts int
+}
+
+// NewRetry wraps the generator to generate up to attempts IDs until the taken check passes.
+//
+// Parameters:
+// - next: The generator of candidate IDs.
+// - taken: The check whether an ID is already used.
+// - attempts: The maximum number of candidates, values less than 1 mean a single attempt.
+//
+// Returns:
+// - A pointer to a Retry instance.
+func NewRetry(next Generator, taken func(ctx context.Context, id string) (bool, error), attempts int) *Retry {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &Retry{next: next, taken: taken, attempts: attempts}
+}
+
+// NextID returns the first generated ID that is not taken,
+// or ErrNoFreeID if all attempts are exhausted.
+func (g *Retry) NextID(ctx context.Context) (string, error) {
+	for i := 0; i < g.attempts; i++ {
+		id, err := g.next.NextID(ctx)
+		if err != nil {
+			return "", err
+		}
+		taken, err := g.taken(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return id, nil
+		}
+	}
+	return "", ErrNoFreeID
+}
@@ -0,0 +1,98 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter is a SequenceSource counting from 1.
+type counter struct {
+	n int64
+}
+
+func (c *counter) NextSequence(ctx context.Context) (int64, error) {
+	c.n++
+	return c.n, nil
+}
+
+// fixed is a Generator returning the given IDs in order.
+type fixed struct {
+	ids []string
+}
+
+func (g *fixed) NextID(ctx context.Context) (string, error) {
+	id := g.ids[0]
+	g.ids = g.ids[1:]
+	return id, nil
+}
+
+func TestRandom(t *testing.T) {
+	gen := NewRandom(8)
+	seen := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		id, err := gen.NextID(context.Background())
+		require.NoError(t, err)
+		require.Len(t, id, 8)
+		for _, c := range id {
+			require.True(t, strings.ContainsRune(base62, c), "unexpected character %q in %q", c, id)
+		}
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, 1000)
+}
+
+func TestSequence(t *testing.T) {
+	t.Run("unique IDs of minimum length", func(t *testing.T) {
+		// A small space checks the encoding is a bijection below 62^minLength.
+		gen := NewSequence(&counter{}, "salt", 2)
+		seen := make(map[string]struct{})
+		for i := 0; i < 62*62+100; i++ {
+			id, err := gen.NextID(context.Background())
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, len(id), 2)
+			seen[id] = struct{}{}
+		}
+		assert.Len(t, seen, 62*62+100)
+	})
+
+	t.Run("salt changes IDs", func(t *testing.T) {
+		a := NewSequence(&counter{}, "first", 6)
+		b := NewSequence(&counter{}, "second", 6)
+		assert.Equal(t, a.Encode(42), NewSequence(&counter{}, "first", 6).Encode(42))
+		assert.NotEqual(t, a.Encode(42), b.Encode(42))
+	})
+}
+
+func TestRetry(t *testing.T) {
+	used := map[string]bool{"a": true, "b": true}
+	taken := func(ctx context.Context, id string) (bool, error) {
+		return used[id], nil
+	}
+
+	t.Run("skips taken IDs", func(t *testing.T) {
+		gen := NewRetry(&fixed{ids: []string{"a", "b", "c"}}, taken, 3)
+		id, err := gen.NextID(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "c", id)
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		gen := NewRetry(&fixed{ids: []string{"a", "b", "c"}}, taken, 2)
+		_, err := gen.NextID(context.Background())
+		assert.ErrorIs(t, err, ErrNoFreeID)
+	})
+
+	t.Run("returns check errors", func(t *testing.T) {
+		errCheck := errors.New("check failed")
+		gen := NewRetry(&fixed{ids: []string{"a"}}, func(ctx context.Context, id string) (bool, error) {
+			return false, errCheck
+		}, 3)
+		_, err := gen.NextID(context.Background())
+		assert.ErrorIs(t, err, errCheck)
+	})
+}
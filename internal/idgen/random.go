@@ -0,0 +1,37 @@
+package idgen
+
+import (
+	"context"
+	"crypto/rand"
+)
+
+// maxUnbiasedByte is the exclusive upper bound of random bytes mapped onto the alphabet.
+// Bytes above it are dropped so every character is equally likely.
+const maxUnbiasedByte = 256 - 256%len(base62)
+
+// Random is a Generator of random base62 IDs of a fixed length.
+type Random struct {
+	length int
+}
+
+// NewRandom creates a Generator of random base62 IDs of the given length.
+func NewRandom(length int) *Random {
+	return &Random{length: length}
+}
+
+// NextID returns a new random ID.
+func (g *Random) NextID(ctx context.Context) (string, error) {
+	id := make([]byte, 0, g.length)
+	buf := make([]byte, g.length+g.length/4+1)
+	for len(id) < g.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < maxUnbiasedByte && len(id) < g.length {
+				id = append(id, base62[int(b)%len(base62)])
+			}
+		}
+	}
+	return string(id), nil
+}
@@ -0,0 +1,113 @@
+package idgen
+
+import (
+	"context"
+	"hash/fnv"
+	"math/bits"
+)
+
+// SequenceSource provides unique increasing numbers.
+type SequenceSource interface {
+	// NextSequence returns the next number of the sequence.
+	NextSequence(ctx context.Context) (int64, error)
+}
+
+// maxSequenceLength limits the minimum length of sequence IDs,
+// so the space of IDs of that length fits into uint64.
+const maxSequenceLength = 10
+
+// multiplier scatters consecutive numbers over the ID space.
+// It is odd and not divisible by 31, so it is coprime with any power of 62.
+const multiplier = 0x5DEECE66D
+
+// Sequence is a Generator encoding numbers from a SequenceSource into base62 IDs.
+// In the style of hashids, the alphabet is shuffled with a salt and numbers are scattered
+// over the space of IDs of the minimum length, so consecutive IDs do not look consecutive.
+// Numbers that do not fit into the minimum length produce longer IDs.
+// Distinct numbers always produce distinct IDs.
+type Sequence struct {
+	source    SequenceSource
+	alphabet  string
+	minLength int
+	space     uint64 // space is the number of IDs of the minimum length.
+	offset    uint64 // offset shifts scattered numbers depending on the salt.
+}
+
+// NewSequence creates a Generator of IDs encoding numbers from the source.
+//
+// Parameters:
+// - source: The source of unique numbers.
+// - salt: The secret making the IDs of the same numbers differ between installations.
+// - minLength: The minimum ID length, it is limited to 10 characters.
+//
+// Returns:
+// - A pointer to a Sequence instance.
+func NewSequence(source SequenceSource, salt string, minLength int) *Sequence {
+	minLength = max(1, min(minLength, maxSequenceLength))
+
+	space := uint64(1)
+	for i := 0; i < minLength; i++ {
+		space *= uint64(len(base62))
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(salt))
+
+	return &Sequence{
+		source:    source,
+		alphabet:  shuffle(base62, salt),
+		minLength: minLength,
+		space:     space,
+		offset:    h.Sum64() % space,
+	}
+}
+
+// NextID returns the ID of the next number of the sequence.
+func (g *Sequence) NextID(ctx context.Context) (string, error) {
+	n, err := g.source.NextSequence(ctx)
+	if err != nil {
+		return "", err
+	}
+	return g.Encode(uint64(n)), nil
+}
+
+// Encode returns the ID of the number.
+func (g *Sequence) Encode(n uint64) string {
+	if n >= g.space {
+		return g.encode(n, 0)
+	}
+
+	// (n * multiplier + offset) mod space is a bijection on [0, space).
+	hi, lo := bits.Mul64(n, multiplier%g.space)
+	scattered := bits.Rem64(hi, lo, g.space)
+	scattered = (scattered + g.offset) % g.space
+	return g.encode(scattered, g.minLength)
+}
+
+// encode writes the number in the shuffled alphabet padded to the given length.
+func (g *Sequence) encode(n uint64, length int) string {
+	base := uint64(len(g.alphabet))
+	var id []byte
+	for n > 0 || len(id) < length {
+		id = append(id, g.alphabet[n%base])
+		n /= base
+	}
+	return string(id)
+}
+
+// shuffle deterministically permutes the alphabet with the salt
+// using the consistent shuffle of hashids. An empty salt keeps the alphabet as is.
+func shuffle(alphabet, salt string) string {
+	if salt == "" {
+		return alphabet
+	}
+
+	a := []byte(alphabet)
+	for i, v, p := len(a)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		a[i], a[j] = a[j], a[i]
+	}
+	return string(a)
+}
@@ -0,0 +1,955 @@
+// Package memory provides an in-memory implementation of the URL storage.
+// It keeps URL records in maps indexed by short URL, original URL and user ID,
+// so every lookup is served without touching a disk or a database.
+// The operations never block on I/O, so they ignore the context cancellation.
+// MemoryStore can be used as a standalone backend for tests and development
+// or as the hot index in front of a persistent store.
+package memory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KirillZiborov/lnkshortener/internal/clicks"
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/deletion"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+// MemoryStore provides a concurrency-safe in-memory implementation of the URLStore interface.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	dedup      string                         // dedup is the deduplication policy, one of config.Dedup* values.
+	byShortURL map[string]*file.URLRecord     // byShortURL maps a short URL to its record.
+	byOriginal map[string]string              // byOriginal maps a deduplication key of an original URL to its short URL.
+	byUser     map[string][]string            // byUser maps a user ID to short URLs in creation order.
+	byTag      map[string]map[string]struct{} // byTag maps a user ID and a tag to the tagged short URLs of the user.
+	order      []string                       // order holds all short URLs in creation order.
+	archive    []file.URLRecord               // archive holds expired records removed with the archive policy.
+	clickLog   map[string][]clicks.Click      // clickLog maps a short URL to its clicks in the order they were saved.
+	revisions  map[string][]file.URLRevision  // revisions maps a short URL to its revisions in the order they were made.
+	seq        int64                          // seq is the last number assigned to a record or taken from the sequence.
+	jobs       map[string]*deletion.Job       // jobs maps a deletion job ID to the job.
+	jobOrder   []string                       // jobOrder holds all deletion job IDs in creation order.
+}
+
+// NewMemoryStore initializes and returns a pointer to a new empty MemoryStore.
+// The dedup policy defines which original URLs SaveURLRecord reports as duplicates.
+func NewMemoryStore(dedup string) *MemoryStore {
+	return &MemoryStore{
+		dedup:      dedup,
+		byShortURL: make(map[string]*file.URLRecord),
+		byOriginal: make(map[string]string),
+		byUser:     make(map[string][]string),
+		byTag:      make(map[string]map[string]struct{}),
+		clickLog:   make(map[string][]clicks.Click),
+		revisions:  make(map[string][]file.URLRevision),
+		jobs:       make(map[string]*deletion.Job),
+	}
+}
+
+// dedupKey returns the key of the original URL in the byOriginal index
+// and false if the deduplication policy allows duplicates.
+func (store *MemoryStore) dedupKey(originalURL, userID string) (string, bool) {
+	switch store.dedup {
+	case config.DedupNone:
+		return "", false
+	case config.DedupPerUser:
+		return userID + " " + originalURL, true
+	default:
+		return originalURL, true
+	}
+}
+
+// SaveURLRecord saves a copy of the URLRecord in memory.
+// If the original URL is already shortened according to the deduplication policy,
+// it returns the existing short URL and database.ErrorDuplicate the same way DBStore does.
+// Deleted and expired URLs are not considered duplicates.
+// Records with user-chosen aliases skip deduplication.
+// If the short URL is already used, it returns database.ErrorShortURLTaken.
+// The record is assigned a new UUID and the creation time if it has none.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - urlRecord: A pointer to the URLRecord to be saved.
+//
+// Returns:
+// - The short URL string if the insertion is successful.
+// - An error if the URL already exists.
+func (store *MemoryStore) SaveURLRecord(ctx context.Context, urlRecord *file.URLRecord) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+	if shortURL, err := store.check(urlRecord, now); err != nil {
+		return shortURL, err
+	}
+
+	store.seq++
+	urlRecord.UUID = strconv.FormatInt(store.seq, 10)
+	if urlRecord.CreatedAt.IsZero() {
+		urlRecord.CreatedAt = now
+	}
+	store.put(urlRecord)
+	return urlRecord.ShortURL, nil
+}
+
+// SaveURLRecords saves copies of a batch of URLRecords in memory at once.
+// Every record is checked the same way SaveURLRecord checks it, an original URL
+// repeated within the batch is a duplicate of its first occurrence.
+// The saved records are assigned new UUIDs and the creation time if they have none.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - records: The URLRecords to be saved.
+// - atomic: Whether nothing is saved if any short URL is already used.
+//
+// Returns:
+// - The result of every record in the order of the records.
+// - database.ErrorBatchRejected together with the results if the atomic batch is not saved.
+func (store *MemoryStore) SaveURLRecords(ctx context.Context, records []*file.URLRecord, atomic bool) ([]file.SaveResult, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+	results := make([]file.SaveResult, len(records))
+	pending := make(map[string]string) // pending maps the dedup keys of the batch to their short URLs.
+	taken := make(map[string]bool)     // taken holds the short URLs of the batch.
+	rejected := false
+	for i, rec := range records {
+		key, dedup := store.dedupKey(rec.OriginalURL, rec.UserUUID)
+		dedup = dedup && !rec.Alias
+		if shortURL, ok := pending[key]; dedup && ok {
+			results[i] = file.SaveResult{ShortURL: shortURL, Err: database.ErrorDuplicate}
+			continue
+		}
+		if taken[rec.ShortURL] {
+			results[i] = file.SaveResult{Err: database.ErrorShortURLTaken}
+			rejected = true
+			continue
+		}
+		shortURL, err := store.check(rec, now)
+		results[i] = file.SaveResult{ShortURL: shortURL, Err: err}
+		if errors.Is(err, database.ErrorShortURLTaken) {
+			rejected = true
+		}
+		if err != nil {
+			continue
+		}
+
+		taken[rec.ShortURL] = true
+		if dedup {
+			pending[key] = rec.ShortURL
+		}
+		results[i].ShortURL = rec.ShortURL
+	}
+	if atomic && rejected {
+		return results, database.ErrorBatchRejected
+	}
+
+	for i, rec := range records {
+		if results[i].Err != nil {
+			continue
+		}
+		store.seq++
+		rec.UUID = strconv.FormatInt(store.seq, 10)
+		if rec.CreatedAt.IsZero() {
+			rec.CreatedAt = now
+		}
+		store.put(rec)
+	}
+	return results, nil
+}
+
+// check reports whether the record can be saved by the given time.
+// It returns the existing short URL and database.ErrorDuplicate if the original URL is a duplicate,
+// or database.ErrorShortURLTaken if the short URL is already used.
+// The caller must hold the write lock.
+func (store *MemoryStore) check(urlRecord *file.URLRecord, now time.Time) (string, error) {
+	if key, ok := store.dedupKey(urlRecord.OriginalURL, urlRecord.UserUUID); ok && !urlRecord.Alias {
+		if existing, ok := store.byOriginal[key]; ok {
+			if !store.byShortURL[existing].IsExpired(now) {
+				return existing, database.ErrorDuplicate
+			}
+			// Let the new record take the place of the expired one.
+			delete(store.byOriginal, key)
+		}
+	}
+	if _, ok := store.byShortURL[urlRecord.ShortURL]; ok {
+		return "", database.ErrorShortURLTaken
+	}
+	return "", nil
+}
+
+// Restore puts a copy of the URLRecord in memory as is, skipping duplicate detection.
+// It is used to rebuild the index from a persistent store, which may contain records
+// saved before duplicate detection was in place.
+func (store *MemoryStore) Restore(urlRecord *file.URLRecord) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Never assign restored UUIDs again.
+	if id, err := strconv.ParseInt(urlRecord.UUID, 10, 64); err == nil && id > store.seq {
+		store.seq = id
+	}
+	// Records deleted before the deletion time was kept can be restored for the whole retention from now on.
+	if urlRecord.DeletedFlag && urlRecord.DeletedAt == nil {
+		now := time.Now()
+		urlRecord.DeletedAt = &now
+	}
+
+	if old, ok := store.byShortURL[urlRecord.ShortURL]; ok {
+		// The record is already indexed, only refresh its data.
+		rec := *urlRecord
+		rec.Tags = slices.Clone(rec.Tags)
+		store.unindexTags(old)
+		store.byShortURL[rec.ShortURL] = &rec
+		store.indexTags(&rec)
+		return
+	}
+	store.put(urlRecord)
+}
+
+// Revert puts back copies of a record and of its revisions taken before a change,
+// reindexing its tags and its original URL. Unknown short URLs are ignored.
+// FileStore uses it to undo a change it could not write to the log.
+func (store *MemoryStore) Revert(urlRecord *file.URLRecord, revisions []file.URLRevision) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	old, ok := store.byShortURL[urlRecord.ShortURL]
+	if !ok {
+		return
+	}
+	store.unindexTags(old)
+	if key, ok := store.dedupKey(old.OriginalURL, old.UserUUID); ok && store.byOriginal[key] == old.ShortURL {
+		delete(store.byOriginal, key)
+	}
+
+	rec := *urlRecord
+	rec.Tags = slices.Clone(rec.Tags)
+	store.byShortURL[rec.ShortURL] = &rec
+	store.indexTags(&rec)
+	if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && !rec.DeletedFlag && !rec.Alias {
+		if _, taken := store.byOriginal[key]; !taken {
+			store.byOriginal[key] = rec.ShortURL
+		}
+	}
+	if len(revisions) > 0 {
+		store.revisions[rec.ShortURL] = slices.Clone(revisions)
+	} else {
+		delete(store.revisions, rec.ShortURL)
+	}
+}
+
+// Records returns copies of all records in creation order.
+func (store *MemoryStore) Records() []file.URLRecord {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	records := make([]file.URLRecord, 0, len(store.order))
+	for _, shortURL := range store.order {
+		records = append(records, *store.byShortURL[shortURL])
+	}
+	return records
+}
+
+// Revisions returns copies of the revisions of all records in creation order of the records,
+// the revisions of every record in the order they were made.
+func (store *MemoryStore) Revisions() []file.URLRevision {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var revisions []file.URLRevision
+	for _, shortURL := range store.order {
+		revisions = append(revisions, store.revisions[shortURL]...)
+	}
+	return revisions
+}
+
+// put indexes a copy of the URLRecord. The caller must hold the write lock.
+func (store *MemoryStore) put(urlRecord *file.URLRecord) {
+	rec := *urlRecord
+	rec.Tags = slices.Clone(rec.Tags)
+	store.byShortURL[rec.ShortURL] = &rec
+	store.indexTags(&rec)
+	if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && !rec.DeletedFlag && !rec.Alias {
+		if _, ok := store.byOriginal[key]; !ok {
+			store.byOriginal[key] = rec.ShortURL
+		}
+	}
+	store.byUser[rec.UserUUID] = append(store.byUser[rec.UserUUID], rec.ShortURL)
+	store.order = append(store.order, rec.ShortURL)
+}
+
+// GetURLRecord retrieves a copy of the URL record based on the provided short URL.
+// It returns os.ErrProcessDone if the short URL is unknown, as FileStore does.
+func (store *MemoryStore) GetURLRecord(ctx context.Context, shortURL string) (*file.URLRecord, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	if !ok {
+		return nil, os.ErrProcessDone
+	}
+	found := *rec
+	return &found, nil
+}
+
+// GetUserURLs retrieves a page of copies of the URL records associated with a specific user ID
+// which match the filters of the query, ordered by creation time.
+//
+// Parameters:
+// - ctx: The context for managing cancellation and timeouts.
+// - userID: The user ID whose URLs are to be retrieved.
+// - q: The filters, the order and the position of the page.
+//
+// Returns:
+// - The page of the user's URLs with the total number of matching URLs.
+// - An error, which is always nil.
+func (store *MemoryStore) GetUserURLs(ctx context.Context, userID string, q file.URLQuery) (file.URLPage, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var matched []file.URLRecord
+	if q.Tag != "" {
+		for shortURL := range store.byTag[tagKey(userID, q.Tag)] {
+			if rec := store.byShortURL[shortURL]; q.Match(rec) {
+				matched = append(matched, *rec)
+			}
+		}
+	} else {
+		for _, shortURL := range store.byUser[userID] {
+			if rec := store.byShortURL[shortURL]; q.Match(rec) {
+				matched = append(matched, *rec)
+			}
+		}
+	}
+	slices.SortStableFunc(matched, func(a, b file.URLRecord) int {
+		return comparePositions(a.Position(), b.Position(), q.Desc)
+	})
+
+	page := file.URLPage{Total: len(matched)}
+	if q.After != nil {
+		start, _ := slices.BinarySearchFunc(matched, *q.After, func(rec file.URLRecord, after file.URLPosition) int {
+			// Records at the position of the cursor are on the previous page.
+			if c := comparePositions(rec.Position(), after, q.Desc); c != 0 {
+				return c
+			}
+			return -1
+		})
+		matched = matched[start:]
+	}
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+		next := matched[len(matched)-1].Position()
+		page.Next = &next
+	}
+	page.Records = matched
+	return page, nil
+}
+
+// tagKey returns the key of the user's tag in the byTag index.
+func tagKey(userID, tag string) string {
+	return userID + " " + tag
+}
+
+// indexTags adds the record to the byTag index. The caller must hold the write lock.
+func (store *MemoryStore) indexTags(rec *file.URLRecord) {
+	for _, tag := range rec.Tags {
+		key := tagKey(rec.UserUUID, tag)
+		if store.byTag[key] == nil {
+			store.byTag[key] = make(map[string]struct{})
+		}
+		store.byTag[key][rec.ShortURL] = struct{}{}
+	}
+}
+
+// unindexTags removes the record from the byTag index. The caller must hold the write lock.
+func (store *MemoryStore) unindexTags(rec *file.URLRecord) {
+	for _, tag := range rec.Tags {
+		key := tagKey(rec.UserUUID, tag)
+		delete(store.byTag[key], rec.ShortURL)
+		if len(store.byTag[key]) == 0 {
+			delete(store.byTag, key)
+		}
+	}
+}
+
+// SetTags replaces the tags of the user's short URL now.
+// It returns os.ErrProcessDone or database.ErrorNotOwner the same way DBStore does.
+func (store *MemoryStore) SetTags(ctx context.Context, shortURL, userID string, tags []string) error {
+	return store.MarkTags(shortURL, userID, tags, time.Now())
+}
+
+// MarkTags replaces the tags of the user's short URL the same way SetTags does
+// and records the given time as the time it was changed at, a zero time keeps the previous one.
+func (store *MemoryStore) MarkTags(shortURL, userID string, tags []string, updatedAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	switch {
+	case !ok:
+		return os.ErrProcessDone
+	case rec.UserUUID != userID:
+		return database.ErrorNotOwner
+	}
+	store.unindexTags(rec)
+	rec.Tags = slices.Clone(tags)
+	store.indexTags(rec)
+	markUpdated(rec, updatedAt)
+	return nil
+}
+
+// SetActiveWindow changes the activation window of the user's short URL now.
+// It returns os.ErrProcessDone or database.ErrorNotOwner the same way DBStore does.
+func (store *MemoryStore) SetActiveWindow(ctx context.Context, shortURL, userID string, notBefore, notAfter *time.Time) error {
+	return store.MarkActiveWindow(shortURL, userID, notBefore, notAfter, time.Now())
+}
+
+// MarkActiveWindow changes the activation window of the user's short URL the same way SetActiveWindow does
+// and records the given time as the time it was changed at, a zero time keeps the previous one.
+func (store *MemoryStore) MarkActiveWindow(shortURL, userID string, notBefore, notAfter *time.Time, updatedAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	switch {
+	case !ok:
+		return os.ErrProcessDone
+	case rec.UserUUID != userID:
+		return database.ErrorNotOwner
+	}
+	rec.NotBefore, rec.NotAfter = notBefore, notAfter
+	markUpdated(rec, updatedAt)
+	return nil
+}
+
+// SetRules replaces the redirect rules of the user's short URL now.
+// It returns os.ErrProcessDone or database.ErrorNotOwner the same way DBStore does.
+func (store *MemoryStore) SetRules(ctx context.Context, shortURL, userID string, rules []file.RedirectRule) error {
+	return store.MarkRules(shortURL, userID, rules, time.Now())
+}
+
+// MarkRules replaces the redirect rules of the user's short URL the same way SetRules does
+// and records the given time as the time it was changed at, a zero time keeps the previous one.
+func (store *MemoryStore) MarkRules(shortURL, userID string, rules []file.RedirectRule, updatedAt time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	switch {
+	case !ok:
+		return os.ErrProcessDone
+	case rec.UserUUID != userID:
+		return database.ErrorNotOwner
+	}
+	rec.Rules = slices.Clone(rules)
+	markUpdated(rec, updatedAt)
+	return nil
+}
+
+// UseClick counts a redirect of the short URL against its click limit.
+// Redirects of short URLs without a limit are not counted.
+// It returns os.ErrProcessDone if the short URL is unknown and database.ErrorExhausted
+// if the limit is already reached, the same way DBStore does.
+func (store *MemoryStore) UseClick(ctx context.Context, shortURL string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	switch {
+	case !ok:
+		return os.ErrProcessDone
+	case rec.MaxClicks == 0:
+		return nil
+	case rec.UsedClicks >= rec.MaxClicks:
+		return database.ErrorExhausted
+	}
+	rec.UsedClicks++
+	return nil
+}
+
+// GetTagStats counts the short URLs of the user and their clicks by tags.
+// It returns the statistics of every tag of the user ordered by tag.
+func (store *MemoryStore) GetTagStats(ctx context.Context, userID string) ([]clicks.TagStats, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	byTag := make(map[string]*clicks.TagStats)
+	for _, shortURL := range store.byUser[userID] {
+		for _, tag := range store.byShortURL[shortURL].Tags {
+			stats, ok := byTag[tag]
+			if !ok {
+				stats = &clicks.TagStats{Tag: tag}
+				byTag[tag] = stats
+			}
+			stats.URLs++
+			stats.Clicks += len(store.clickLog[shortURL])
+		}
+	}
+
+	stats := make([]clicks.TagStats, 0, len(byTag))
+	for _, s := range byTag {
+		stats = append(stats, *s)
+	}
+	slices.SortFunc(stats, func(a, b clicks.TagStats) int { return strings.Compare(a.Tag, b.Tag) })
+	return stats, nil
+}
+
+// comparePositions compares the positions in ascending order or in descending order if desc is true.
+func comparePositions(a, b file.URLPosition, desc bool) int {
+	c := 0
+	switch {
+	case a.Before(b):
+		c = -1
+	case b.Before(a):
+		c = 1
+	}
+	if desc {
+		return -c
+	}
+	return c
+}
+
+// BatchUpdateDeleteFlag marks the URL records with the given short URLs as deleted now
+// if they belong to the given user. Unknown or foreign URLs are ignored.
+func (store *MemoryStore) BatchUpdateDeleteFlag(ctx context.Context, urlIDs []string, userID string) error {
+	store.MarkDeleted(urlIDs, userID, time.Now())
+	return nil
+}
+
+// MarkDeleted marks the URL records with the given short URLs as deleted at the given time
+// if they belong to the given user. Unknown or foreign URLs are ignored,
+// the deletion time of already deleted records is kept.
+func (store *MemoryStore) MarkDeleted(urlIDs []string, userID string, deletedAt time.Time) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, urlID := range urlIDs {
+		rec, ok := store.byShortURL[urlID]
+		if !ok || rec.UserUUID != userID || rec.DeletedFlag {
+			continue
+		}
+		rec.DeletedFlag = true
+		at := deletedAt
+		rec.DeletedAt = &at
+		rec.UpdatedAt = &at
+		// Let the original URL be shortened again.
+		if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && store.byOriginal[key] == rec.ShortURL {
+			delete(store.byOriginal, key)
+		}
+	}
+}
+
+// RestoreURLs clears the deletion mark of the URL records with the given short URLs now
+// if they belong to the given user and were deleted since deletedSince.
+// Unknown, foreign, not deleted and too long ago deleted URLs are skipped.
+// A restored original URL is indexed for deduplication again unless another record took its place.
+func (store *MemoryStore) RestoreURLs(ctx context.Context, urlIDs []string, userID string, deletedSince time.Time) ([]string, error) {
+	return store.MarkRestored(urlIDs, userID, deletedSince, time.Now()), nil
+}
+
+// MarkRestored clears the deletion mark of the URL records the same way RestoreURLs does
+// and records the given time as the time they were changed at.
+// It returns the short URLs of the restored records.
+func (store *MemoryStore) MarkRestored(urlIDs []string, userID string, deletedSince, restoredAt time.Time) []string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var restored []string
+	for _, urlID := range urlIDs {
+		rec, ok := store.byShortURL[urlID]
+		if !ok || rec.UserUUID != userID || !rec.DeletedFlag {
+			continue
+		}
+		if rec.DeletedAt != nil && rec.DeletedAt.Before(deletedSince) {
+			continue
+		}
+		rec.DeletedFlag = false
+		rec.DeletedAt = nil
+		at := restoredAt
+		rec.UpdatedAt = &at
+		if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && !rec.Alias {
+			if _, taken := store.byOriginal[key]; !taken {
+				store.byOriginal[key] = rec.ShortURL
+			}
+		}
+		restored = append(restored, urlID)
+	}
+	return restored
+}
+
+// PurgeDeleted removes the records deleted before the given time together with their clicks and revisions.
+func (store *MemoryStore) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	purged := store.deletedBefore(deletedBefore)
+	store.remove(purged)
+	return len(purged), nil
+}
+
+// DeletedBefore returns the short URLs of the records deleted before the given time in creation order.
+func (store *MemoryStore) DeletedBefore(deletedBefore time.Time) []string {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.deletedBefore(deletedBefore)
+}
+
+// deletedBefore returns the short URLs of the records deleted before the given time.
+// The caller must hold the lock.
+func (store *MemoryStore) deletedBefore(deletedBefore time.Time) []string {
+	var shortURLs []string
+	for _, shortURL := range store.order {
+		rec := store.byShortURL[shortURL]
+		if rec.DeletedFlag && rec.DeletedAt != nil && rec.DeletedAt.Before(deletedBefore) {
+			shortURLs = append(shortURLs, shortURL)
+		}
+	}
+	return shortURLs
+}
+
+// UpdateOriginalURL changes the original URL of the user's short URL and records the revision.
+// It returns os.ErrProcessDone, database.ErrorNotOwner, database.ErrorDeleted
+// or database.ErrorDuplicate the same way DBStore does.
+func (store *MemoryStore) UpdateOriginalURL(ctx context.Context, shortURL, userID, originalURL string) (*file.URLRevision, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	switch {
+	case !ok:
+		return nil, os.ErrProcessDone
+	case rec.UserUUID != userID:
+		return nil, database.ErrorNotOwner
+	case rec.DeletedFlag:
+		return nil, database.ErrorDeleted
+	}
+	if key, ok := store.dedupKey(originalURL, userID); ok && !rec.Alias {
+		if existing, ok := store.byOriginal[key]; ok && existing != shortURL && !store.byShortURL[existing].IsExpired(time.Now()) {
+			return nil, database.ErrorDuplicate
+		}
+	}
+
+	rev := file.URLRevision{
+		ShortURL:  shortURL,
+		UserUUID:  userID,
+		OldURL:    rec.OriginalURL,
+		NewURL:    originalURL,
+		ChangedAt: time.Now(),
+	}
+	store.applyRevision(&rev)
+	return &rev, nil
+}
+
+// RestoreRevision applies a revision read from a persistent store as is, skipping all checks.
+// Revisions of unknown short URLs are ignored.
+func (store *MemoryStore) RestoreRevision(rev *file.URLRevision) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.applyRevision(rev)
+}
+
+// applyRevision records the revision, sets the new original URL of the record and reindexes it.
+// The caller must hold the write lock.
+func (store *MemoryStore) applyRevision(rev *file.URLRevision) {
+	rec, ok := store.byShortURL[rev.ShortURL]
+	if !ok {
+		return
+	}
+	store.revisions[rev.ShortURL] = append(store.revisions[rev.ShortURL], *rev)
+
+	if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && store.byOriginal[key] == rec.ShortURL {
+		delete(store.byOriginal, key)
+	}
+	rec.OriginalURL = rev.NewURL
+	changedAt := rev.ChangedAt
+	rec.UpdatedAt = &changedAt
+	if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && !rec.DeletedFlag && !rec.Alias {
+		store.byOriginal[key] = rec.ShortURL
+	}
+}
+
+// GetURLHistory retrieves copies of the revisions of the user's short URL in the order they were made.
+// It returns os.ErrProcessDone or database.ErrorNotOwner the same way DBStore does.
+func (store *MemoryStore) GetURLHistory(ctx context.Context, shortURL, userID string) ([]file.URLRevision, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	rec, ok := store.byShortURL[shortURL]
+	if !ok {
+		return nil, os.ErrProcessDone
+	}
+	if rec.UserUUID != userID {
+		return nil, database.ErrorNotOwner
+	}
+	return append([]file.URLRevision(nil), store.revisions[shortURL]...), nil
+}
+
+// GetURLsCount counts shortened URLs.
+func (store *MemoryStore) GetURLsCount(ctx context.Context) (int, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return len(store.byShortURL), nil
+}
+
+// GetUsersCount counts unique users.
+func (store *MemoryStore) GetUsersCount(ctx context.Context) (int, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return len(store.byUser), nil
+}
+
+// DeleteExpired removes the records expired by now.
+// With the archive policy they are kept in the in-memory archive.
+func (store *MemoryStore) DeleteExpired(ctx context.Context, now time.Time, policy string) (int, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	var expired []string
+	for _, shortURL := range store.order {
+		rec := store.byShortURL[shortURL]
+		if rec.IsExpired(now) {
+			expired = append(expired, shortURL)
+			if policy == config.ExpiredArchive {
+				store.archive = append(store.archive, *rec)
+			}
+		}
+	}
+	store.remove(expired)
+	return len(expired), nil
+}
+
+// Archive returns copies of the records removed with the archive policy.
+func (store *MemoryStore) Archive() []file.URLRecord {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return append([]file.URLRecord(nil), store.archive...)
+}
+
+// Remove removes the records with the given short URLs. Unknown short URLs are ignored.
+func (store *MemoryStore) Remove(shortURLs ...string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.remove(shortURLs)
+}
+
+// remove drops the records from all indexes. The caller must hold the write lock.
+func (store *MemoryStore) remove(shortURLs []string) {
+	if len(shortURLs) == 0 {
+		return
+	}
+
+	removed := make(map[string]struct{}, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		rec, ok := store.byShortURL[shortURL]
+		if !ok {
+			continue
+		}
+		removed[shortURL] = struct{}{}
+		delete(store.byShortURL, shortURL)
+		store.unindexTags(rec)
+		delete(store.revisions, shortURL)
+		delete(store.clickLog, shortURL)
+		if key, ok := store.dedupKey(rec.OriginalURL, rec.UserUUID); ok && store.byOriginal[key] == shortURL {
+			delete(store.byOriginal, key)
+		}
+	}
+
+	// Rebuild the ordered indexes once for all removed records.
+	store.order = without(store.order, removed)
+	for userID, userURLs := range store.byUser {
+		if userURLs = without(userURLs, removed); len(userURLs) > 0 {
+			store.byUser[userID] = userURLs
+		} else {
+			delete(store.byUser, userID)
+		}
+	}
+}
+
+// markUpdated records the time the record was changed at, a zero time keeps the previous one.
+func markUpdated(rec *file.URLRecord, updatedAt time.Time) {
+	if !updatedAt.IsZero() {
+		rec.UpdatedAt = &updatedAt
+	}
+}
+
+// without filters the removed short URLs out of the slice in place.
+func without(shortURLs []string, removed map[string]struct{}) []string {
+	kept := shortURLs[:0]
+	for _, shortURL := range shortURLs {
+		if _, ok := removed[shortURL]; !ok {
+			kept = append(kept, shortURL)
+		}
+	}
+	return kept
+}
+
+// SaveClicks saves copies of the click events in memory and moves the last access time of the clicked records forward.
+func (store *MemoryStore) SaveClicks(ctx context.Context, events []clicks.Click) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, c := range events {
+		store.clickLog[c.ShortURL] = append(store.clickLog[c.ShortURL], c)
+		if rec, ok := store.byShortURL[c.ShortURL]; ok && (rec.LastAccessedAt == nil || rec.LastAccessedAt.Before(c.Time)) {
+			at := c.Time
+			rec.LastAccessedAt = &at
+		}
+	}
+	return nil
+}
+
+// Clicks returns copies of the clicks of all records in creation order of the records,
+// the clicks of every record in the order they were saved.
+func (store *MemoryStore) Clicks() []clicks.Click {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var events []clicks.Click
+	for _, shortURL := range store.order {
+		events = append(events, store.clickLog[shortURL]...)
+	}
+	return events
+}
+
+// GetClickStats aggregates the clicks of the short URL matching the query.
+func (store *MemoryStore) GetClickStats(ctx context.Context, q clicks.Query) (clicks.Stats, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return clicks.Aggregate(store.clickLog[q.ShortURL], q), nil
+}
+
+// NextSequence returns the next number of the sequence shared with record UUIDs.
+// Restored UUIDs advance the sequence, so numbers are not reused after a restart.
+func (store *MemoryStore) NextSequence(ctx context.Context) (int64, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.seq++
+	return store.seq, nil
+}
+
+// Sequence returns the last number assigned to a record or a deletion job or taken from the sequence.
+func (store *MemoryStore) Sequence() int64 {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	return store.seq
+}
+
+// RestoreSequence advances the sequence to at least the given number, so the numbers up to it
+// are never assigned again. It is used to rebuild the index from a persistent store.
+func (store *MemoryStore) RestoreSequence(seq int64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if seq > store.seq {
+		store.seq = seq
+	}
+}
+
+// SaveDeleteJob saves a copy of the deletion job and assigns it an ID from the sequence.
+func (store *MemoryStore) SaveDeleteJob(ctx context.Context, job *deletion.Job) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.seq++
+	job.ID = strconv.FormatInt(store.seq, 10)
+	store.putJob(job)
+	return nil
+}
+
+// RestoreDeleteJob puts a copy of the deletion job in memory as is, replacing the job with the same ID.
+// It is used to rebuild the index from a persistent store.
+func (store *MemoryStore) RestoreDeleteJob(job *deletion.Job) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	// Never assign restored IDs again.
+	if id, err := strconv.ParseInt(job.ID, 10, 64); err == nil && id > store.seq {
+		store.seq = id
+	}
+	store.putJob(job)
+}
+
+// putJob stores a copy of the job. The caller must hold the write lock.
+func (store *MemoryStore) putJob(job *deletion.Job) {
+	if _, ok := store.jobs[job.ID]; !ok {
+		store.jobOrder = append(store.jobOrder, job.ID)
+	}
+	store.jobs[job.ID] = copyJob(job)
+}
+
+// copyJob returns a copy of the job not sharing its items.
+func copyJob(job *deletion.Job) *deletion.Job {
+	c := *job
+	c.Items = append([]deletion.Item(nil), job.Items...)
+	return &c
+}
+
+// UpdateDeleteJobItems replaces the items of the deletion job with the same short URLs.
+// Unknown jobs and items are ignored.
+func (store *MemoryStore) UpdateDeleteJobItems(ctx context.Context, jobID string, items []deletion.Item) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	job, ok := store.jobs[jobID]
+	if !ok {
+		return nil
+	}
+	updated := make(map[string]deletion.Item, len(items))
+	for _, item := range items {
+		updated[item.ShortURL] = item
+	}
+	for i := range job.Items {
+		if item, ok := updated[job.Items[i].ShortURL]; ok {
+			job.Items[i] = item
+		}
+	}
+	return nil
+}
+
+// GetDeleteJob retrieves a copy of the deletion job.
+// It returns os.ErrProcessDone if the job is unknown.
+func (store *MemoryStore) GetDeleteJob(ctx context.Context, id string) (*deletion.Job, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	job, ok := store.jobs[id]
+	if !ok {
+		return nil, os.ErrProcessDone
+	}
+	return copyJob(job), nil
+}
+
+// PendingDeleteJobs returns copies of the deletion jobs having pending items in creation order.
+func (store *MemoryStore) PendingDeleteJobs(ctx context.Context) ([]deletion.Job, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	var pending []deletion.Job
+	for _, id := range store.jobOrder {
+		job := store.jobs[id]
+		if job.Counts().Pending > 0 {
+			pending = append(pending, *copyJob(job))
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,348 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KirillZiborov/lnkshortener/internal/config"
+	"github.com/KirillZiborov/lnkshortener/internal/database"
+	"github.com/KirillZiborov/lnkshortener/internal/file"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupGlobal)
+
+	shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		UUID:        "1",
+		ShortURL:    "http://localhost:8080/abc",
+		OriginalURL: "https://ya.ru",
+		UserUUID:    "user1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/abc", shortURL)
+
+	t.Run("duplicate original URL", func(t *testing.T) {
+		shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{
+			UUID:        "2",
+			ShortURL:    "http://localhost:8080/def",
+			OriginalURL: "https://ya.ru",
+			UserUUID:    "user2",
+		})
+		assert.ErrorIs(t, err, database.ErrorDuplicate)
+		assert.Equal(t, "http://localhost:8080/abc", shortURL)
+	})
+
+	t.Run("get original URL", func(t *testing.T) {
+		rec, err := store.GetURLRecord(ctx, "http://localhost:8080/abc")
+		require.NoError(t, err)
+		assert.Equal(t, "https://ya.ru", rec.OriginalURL)
+		assert.False(t, rec.DeletedFlag)
+
+		_, err = store.GetURLRecord(ctx, "http://localhost:8080/unknown")
+		assert.ErrorIs(t, err, os.ErrProcessDone)
+	})
+
+	t.Run("user URLs", func(t *testing.T) {
+		page, err := store.GetUserURLs(ctx, "user1", file.URLQuery{})
+		require.NoError(t, err)
+		require.Len(t, page.Records, 1)
+		assert.Equal(t, "https://ya.ru", page.Records[0].OriginalURL)
+
+		page, err = store.GetUserURLs(ctx, "user2", file.URLQuery{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Records)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		// Foreign user cannot delete the URL.
+		require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/abc"}, "user2"))
+		rec, err := store.GetURLRecord(ctx, "http://localhost:8080/abc")
+		require.NoError(t, err)
+		assert.False(t, rec.DeletedFlag)
+
+		require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/abc"}, "user1"))
+		rec, err = store.GetURLRecord(ctx, "http://localhost:8080/abc")
+		require.NoError(t, err)
+		assert.True(t, rec.DeletedFlag)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		urls, err := store.GetURLsCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, urls)
+
+		users, err := store.GetUsersCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, users)
+	})
+}
+
+func TestMemoryStoreConcurrentSave(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupGlobal)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+				ShortURL:    fmt.Sprintf("http://localhost:8080/%d", i),
+				OriginalURL: fmt.Sprintf("https://example.com/%d", i),
+				UserUUID:    "user",
+			})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	urls, err := store.GetURLsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 100, urls)
+
+	page, err := store.GetUserURLs(ctx, "user", file.URLQuery{})
+	require.NoError(t, err)
+	assert.Len(t, page.Records, 100)
+}
+
+func TestMemoryStoreDedupPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		policy       string
+		sameUserDup  bool
+		otherUserDup bool
+	}{
+		{policy: config.DedupGlobal, sameUserDup: true, otherUserDup: true},
+		{policy: config.DedupPerUser, sameUserDup: true, otherUserDup: false},
+		{policy: config.DedupNone, sameUserDup: false, otherUserDup: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			store := NewMemoryStore(tc.policy)
+			_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+				ShortURL: "http://localhost:8080/a", OriginalURL: "https://ya.ru", UserUUID: "user1",
+			})
+			require.NoError(t, err)
+
+			_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+				ShortURL: "http://localhost:8080/b", OriginalURL: "https://ya.ru", UserUUID: "user2",
+			})
+			assert.Equal(t, tc.otherUserDup, errors.Is(err, database.ErrorDuplicate))
+
+			shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{
+				ShortURL: "http://localhost:8080/c", OriginalURL: "https://ya.ru", UserUUID: "user1",
+			})
+			assert.Equal(t, tc.sameUserDup, errors.Is(err, database.ErrorDuplicate))
+			if tc.sameUserDup {
+				assert.Equal(t, "http://localhost:8080/a", shortURL)
+			}
+
+			// A deleted URL does not block shortening the original URL again.
+			require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/a"}, "user1"))
+			_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+				ShortURL: "http://localhost:8080/d", OriginalURL: "https://ya.ru", UserUUID: "user1",
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMemoryStoreAlias(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupGlobal)
+
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL:    "http://localhost:8080/abc",
+		OriginalURL: "https://ya.ru",
+		UserUUID:    "user1",
+	})
+	require.NoError(t, err)
+
+	// An alias skips deduplication.
+	shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL:    "http://localhost:8080/ya",
+		OriginalURL: "https://ya.ru",
+		UserUUID:    "user1",
+		Alias:       true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/ya", shortURL)
+
+	// A taken alias is reported with a distinct error.
+	_, err = store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL:    "http://localhost:8080/ya",
+		OriginalURL: "https://example.com",
+		UserUUID:    "user2",
+		Alias:       true,
+	})
+	assert.ErrorIs(t, err, database.ErrorShortURLTaken)
+
+	// Deduplication still returns the generated short URL.
+	shortURL, err = store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL:    "http://localhost:8080/def",
+		OriginalURL: "https://ya.ru",
+		UserUUID:    "user2",
+	})
+	assert.ErrorIs(t, err, database.ErrorDuplicate)
+	assert.Equal(t, "http://localhost:8080/abc", shortURL)
+}
+
+func TestMemoryStoreSaveURLRecords(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupGlobal)
+
+	_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+		ShortURL:    "http://localhost:8080/abc",
+		OriginalURL: "https://ya.ru",
+		UserUUID:    "user1",
+	})
+	require.NoError(t, err)
+
+	batch := func() []*file.URLRecord {
+		return []*file.URLRecord{
+			{ShortURL: "http://localhost:8080/a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+			{ShortURL: "http://localhost:8080/b", OriginalURL: "https://ya.ru", UserUUID: "user1"},
+			{ShortURL: "http://localhost:8080/c", OriginalURL: "https://a.ru", UserUUID: "user1"},
+			{ShortURL: "http://localhost:8080/abc", OriginalURL: "https://b.ru", UserUUID: "user1", Alias: true},
+		}
+	}
+
+	// An atomic batch with a taken short URL saves nothing.
+	results, err := store.SaveURLRecords(ctx, batch(), true)
+	assert.ErrorIs(t, err, database.ErrorBatchRejected)
+	require.Len(t, results, 4)
+	assert.ErrorIs(t, results[3].Err, database.ErrorShortURLTaken)
+	_, err = store.GetURLRecord(ctx, "http://localhost:8080/a")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+
+	// A best-effort batch saves the valid records.
+	records := batch()
+	results, err = store.SaveURLRecords(ctx, records, false)
+	require.NoError(t, err)
+	assert.Equal(t, file.SaveResult{ShortURL: "http://localhost:8080/a"}, results[0])
+	assert.NotEmpty(t, records[0].UUID)
+	assert.Equal(t, file.SaveResult{ShortURL: "http://localhost:8080/abc", Err: database.ErrorDuplicate}, results[1])
+	// An original URL repeated within the batch is a duplicate of the first one.
+	assert.Equal(t, file.SaveResult{ShortURL: "http://localhost:8080/a", Err: database.ErrorDuplicate}, results[2])
+	assert.ErrorIs(t, results[3].Err, database.ErrorShortURLTaken)
+
+	urls, err := store.GetURLsCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, urls)
+}
+
+func TestMemoryStoreUpdateOriginalURL(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupGlobal)
+
+	for _, rec := range []file.URLRecord{
+		{ShortURL: "a", OriginalURL: "https://a.ru", UserUUID: "user1"},
+		{ShortURL: "b", OriginalURL: "https://b.ru", UserUUID: "user1"},
+	} {
+		_, err := store.SaveURLRecord(ctx, &rec)
+		require.NoError(t, err)
+	}
+
+	rev, err := store.UpdateOriginalURL(ctx, "a", "user1", "https://new.ru")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.ru", rev.OldURL)
+	assert.Equal(t, "https://new.ru", rev.NewURL)
+
+	rec, err := store.GetURLRecord(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://new.ru", rec.OriginalURL)
+
+	// The old original URL is free to be shortened again, the new one is a duplicate.
+	_, err = store.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "c", OriginalURL: "https://a.ru", UserUUID: "user1"})
+	require.NoError(t, err)
+	shortURL, err := store.SaveURLRecord(ctx, &file.URLRecord{ShortURL: "d", OriginalURL: "https://new.ru", UserUUID: "user1"})
+	assert.ErrorIs(t, err, database.ErrorDuplicate)
+	assert.Equal(t, "a", shortURL)
+
+	_, err = store.UpdateOriginalURL(ctx, "b", "user1", "https://new.ru")
+	assert.ErrorIs(t, err, database.ErrorDuplicate)
+	_, err = store.UpdateOriginalURL(ctx, "a", "user2", "https://other.ru")
+	assert.ErrorIs(t, err, database.ErrorNotOwner)
+	_, err = store.UpdateOriginalURL(ctx, "unknown", "user1", "https://other.ru")
+	assert.ErrorIs(t, err, os.ErrProcessDone)
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"b"}, "user1"))
+	_, err = store.UpdateOriginalURL(ctx, "b", "user1", "https://other.ru")
+	assert.ErrorIs(t, err, database.ErrorDeleted)
+
+	history, err := store.GetURLHistory(ctx, "a", "user1")
+	require.NoError(t, err)
+	assert.Equal(t, []file.URLRevision{*rev}, history)
+	_, err = store.GetURLHistory(ctx, "a", "user2")
+	assert.ErrorIs(t, err, database.ErrorNotOwner)
+}
+
+func TestMemoryStoreGetUserURLsPage(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(config.DedupNone)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		_, err := store.SaveURLRecord(ctx, &file.URLRecord{
+			ShortURL:    fmt.Sprintf("http://localhost:8080/%d", i),
+			OriginalURL: fmt.Sprintf("https://%d.ru", i%2),
+			UserUUID:    "user1",
+			CreatedAt:   start.Add(time.Duration(i/2) * time.Hour),
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.BatchUpdateDeleteFlag(ctx, []string{"http://localhost:8080/4"}, "user1"))
+
+	shortURLs := func(page file.URLPage) []string {
+		var urls []string
+		for _, rec := range page.Records {
+			urls = append(urls, rec.ShortURL)
+		}
+		return urls
+	}
+
+	t.Run("cursor", func(t *testing.T) {
+		q := file.URLQuery{Limit: 2, Desc: true}
+		var got []string
+		for {
+			page, err := store.GetUserURLs(ctx, "user1", q)
+			require.NoError(t, err)
+			assert.Equal(t, 5, page.Total)
+			got = append(got, shortURLs(page)...)
+			if page.Next == nil {
+				break
+			}
+			q.After = page.Next
+		}
+		assert.Equal(t, []string{
+			"http://localhost:8080/4", "http://localhost:8080/3", "http://localhost:8080/2",
+			"http://localhost:8080/1", "http://localhost:8080/0",
+		}, got)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		from, to := start.Add(time.Hour), start.Add(2*time.Hour)
+		page, err := store.GetUserURLs(ctx, "user1", file.URLQuery{
+			Status:      file.URLStatusActive,
+			Contains:    "0.ru",
+			CreatedFrom: &from,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, page.Total)
+		assert.Equal(t, []string{"http://localhost:8080/2"}, shortURLs(page))
+
+		page, err = store.GetUserURLs(ctx, "user1", file.URLQuery{Status: file.URLStatusDeleted, CreatedTo: &to})
+		require.NoError(t, err)
+		assert.Zero(t, page.Total)
+	})
+}